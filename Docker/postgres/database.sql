@@ -42,6 +42,22 @@ CREATE EXTENSION IF NOT EXISTS plpgsql WITH SCHEMA pg_catalog;
 COMMENT ON EXTENSION plpgsql IS 'PL/pgSQL procedural language';
 
 
+--
+-- Name: app_tenant; Type: ROLE; Schema: -; Owner:
+--
+-- The application switches to this role in the tenant transactions (SET LOCAL ROLE), unlike the superuser
+-- it connects with the role is subject to the row level security policies
+--
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'app_tenant') THEN
+        CREATE ROLE app_tenant NOLOGIN;
+    END IF;
+END
+$$;
+
+
 CREATE FUNCTION created_at_column() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -76,6 +92,149 @@ $$;
 
 ALTER FUNCTION public.update_at_column() OWNER TO postgres;
 
+--
+-- Name: invoice_immutable(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+-- Once issued an invoice can only change its status (paid or void), and its items can not change at all.
+-- Deletes cascading from the owner (pg_trigger_depth() > 1) are still allowed
+--
+
+CREATE FUNCTION invoice_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.status <> 'draft' AND pg_trigger_depth() = 1 THEN
+            RAISE EXCEPTION 'invoice % is % and can not be deleted', OLD.id, OLD.status;
+        END IF;
+        RETURN OLD;
+    END IF;
+
+    IF OLD.status <> 'draft' AND (NEW.order_id, NEW.customer_id, NEW.number, NEW.currency, NEW.subtotal, NEW.tax_total, NEW.total, NEW.tax_breakdown, NEW.billing_name, NEW.billing_address, NEW.billing_tax_id, NEW.notes, NEW.due_date, NEW.issued_at)
+        IS DISTINCT FROM (OLD.order_id, OLD.customer_id, OLD.number, OLD.currency, OLD.subtotal, OLD.tax_total, OLD.total, OLD.tax_breakdown, OLD.billing_name, OLD.billing_address, OLD.billing_tax_id, OLD.notes, OLD.due_date, OLD.issued_at) THEN
+        RAISE EXCEPTION 'invoice % is % and can not be changed', OLD.id, OLD.status;
+    END IF;
+    RETURN NEW;
+END;
+
+$$;
+
+
+ALTER FUNCTION public.invoice_immutable() OWNER TO postgres;
+
+--
+-- Name: invoice_item_immutable(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+
+CREATE FUNCTION invoice_item_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+
+DECLARE
+    invoice_status character varying;
+BEGIN
+    SELECT status INTO invoice_status FROM invoice WHERE id = COALESCE(NEW.invoice_id, OLD.invoice_id);
+    IF invoice_status IS NOT NULL AND invoice_status <> 'draft' THEN
+        RAISE EXCEPTION 'invoice % is % and its items can not be changed', COALESCE(NEW.invoice_id, OLD.invoice_id), invoice_status;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+
+$$;
+
+
+ALTER FUNCTION public.invoice_item_immutable() OWNER TO postgres;
+
+--
+-- Name: payment_immutable(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+-- The payments are a ledger: a mistake is corrected with a new (negative) entry, never by changing an old one.
+-- Deletes cascading from the organization and the creator set to null (pg_trigger_depth() > 1) are still allowed
+--
+
+CREATE FUNCTION payment_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'payment % can not be changed, record a refund instead', OLD.id;
+END;
+
+$$;
+
+
+ALTER FUNCTION public.payment_immutable() OWNER TO postgres;
+
+--
+-- Name: shipment_event_immutable(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+-- The tracking events are an append-only timeline, they can not be changed once recorded
+--
+
+CREATE FUNCTION shipment_event_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+
+BEGIN
+    RAISE EXCEPTION 'shipment event % can not be changed', OLD.id;
+END;
+
+$$;
+
+
+ALTER FUNCTION public.shipment_event_immutable() OWNER TO postgres;
+
+--
+-- Name: stock_movement_immutable(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+-- The stock movements are a ledger: the stock levels are their sum and a mistake is corrected with an adjustment.
+-- Deletes cascading from the organization and the creator set to null (pg_trigger_depth() > 1) are still allowed
+--
+
+CREATE FUNCTION stock_movement_immutable() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    IF TG_OP = 'UPDATE' AND pg_trigger_depth() > 1 THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'stock movement % can not be changed, record an adjustment instead', OLD.id;
+END;
+
+$$;
+
+
+ALTER FUNCTION public.stock_movement_immutable() OWNER TO postgres;
+
+--
+-- Name: current_organization_id(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+-- The organization of the tenant transaction (app.organization_id), null outside of one so that the policies match no row
+--
+
+CREATE FUNCTION current_organization_id() RETURNS integer
+    LANGUAGE sql STABLE
+    AS $$
+    SELECT NULLIF(current_setting('app.organization_id', true), '')::integer;
+$$;
+
+
+ALTER FUNCTION public.current_organization_id() OWNER TO postgres;
+
 
 SET search_path = public, pg_catalog;
 
@@ -89,6 +248,7 @@ SET default_with_oids = false;
 
 CREATE TABLE article (
     id integer NOT NULL,
+    organization_id integer NOT NULL,
     user_id integer,
     title character varying,
     content text,
@@ -129,6 +289,7 @@ CREATE TABLE "user" (
     email character varying,
     password character varying,
     name character varying,
+    verified_at integer,
     updated_at integer,
     created_at integer
 );
@@ -162,9 +323,16 @@ ALTER SEQUENCE user_id_seq OWNED BY "user".id;
 
 CREATE TABLE product (
     id integer NOT NULL,
+    organization_id integer NOT NULL,
     user_id integer,
-    title character varying,
-    content text,
+    sku character varying NOT NULL,
+    name character varying NOT NULL,
+    description text,
+    price bigint DEFAULT 0 NOT NULL,
+    currency character(3) NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    track_inventory boolean DEFAULT false NOT NULL,
+    attributes jsonb DEFAULT '{}'::jsonb NOT NULL,
     updated_at integer,
     created_at integer
 );
@@ -193,161 +361,3039 @@ ALTER TABLE product_id_seq OWNER TO postgres;
 ALTER SEQUENCE product_id_seq OWNED BY product.id;
 
 --
--- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+-- Name: customer; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-ALTER TABLE ONLY article ALTER COLUMN id SET DEFAULT nextval('article_id_seq'::regclass);
+CREATE TABLE customer (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    name character varying NOT NULL,
+    company_name character varying,
+    tax_id character varying,
+    email character varying,
+    phone character varying,
+    tags text[] DEFAULT '{}'::text[] NOT NULL,
+    notes text,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE customer OWNER TO postgres;
+
 --
--- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+-- Name: customer_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY "user" ALTER COLUMN id SET DEFAULT nextval('user_id_seq'::regclass);
+CREATE SEQUENCE customer_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
+ALTER TABLE customer_id_seq OWNER TO postgres;
+
 --
--- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+-- Name: customer_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY product ALTER COLUMN id SET DEFAULT nextval('product_id_seq'::regclass);
+ALTER SEQUENCE customer_id_seq OWNED BY customer.id;
 
 --
--- Data for Name: article; Type: TABLE DATA; Schema: public; Owner: postgres
+-- Name: order; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-COPY article (id, user_id, title, content, updated_at, created_at) FROM stdin;
-\.
+CREATE TABLE "order" (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    customer_id integer NOT NULL,
+    number character varying NOT NULL,
+    status character varying DEFAULT 'draft'::character varying NOT NULL,
+    currency character(3) NOT NULL,
+    subtotal bigint DEFAULT 0 NOT NULL,
+    tax_total bigint DEFAULT 0 NOT NULL,
+    total bigint DEFAULT 0 NOT NULL,
+    notes text,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE "order" OWNER TO postgres;
+
 --
--- Name: article_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+-- Name: order_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-SELECT pg_catalog.setval('article_id_seq', 1, false);
+CREATE SEQUENCE order_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
 
 
+ALTER TABLE order_id_seq OWNER TO postgres;
+
 --
--- Data for Name: user; Type: TABLE DATA; Schema: public; Owner: postgres
+-- Name: order_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-COPY "user" (id, email, password, name, updated_at, created_at) FROM stdin;
-\.
-
+ALTER SEQUENCE order_id_seq OWNED BY "order".id;
 
 --
--- Name: user_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+-- Name: invoice; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-SELECT pg_catalog.setval('user_id_seq', 1, false);
+CREATE TABLE invoice (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    order_id integer NOT NULL,
+    customer_id integer NOT NULL,
+    number character varying,
+    status character varying DEFAULT 'draft'::character varying NOT NULL,
+    currency character(3) NOT NULL,
+    subtotal bigint DEFAULT 0 NOT NULL,
+    tax_total bigint DEFAULT 0 NOT NULL,
+    total bigint DEFAULT 0 NOT NULL,
+    tax_breakdown jsonb DEFAULT '[]'::jsonb NOT NULL,
+    billing_name character varying,
+    billing_address text,
+    billing_tax_id character varying,
+    notes text,
+    due_date date NOT NULL,
+    issued_at integer,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE invoice OWNER TO postgres;
 
 --
--- Data for Name: product; Type: TABLE DATA; Schema: public; Owner: postgres
+-- Name: invoice_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-COPY product (id, user_id, title, content, updated_at, created_at) FROM stdin;
-\.
+CREATE SEQUENCE invoice_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
 
+ALTER TABLE invoice_id_seq OWNER TO postgres;
 
 --
--- Name: product_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+-- Name: invoice_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-SELECT pg_catalog.setval('product_id_seq', 1, false);
+ALTER SEQUENCE invoice_id_seq OWNED BY invoice.id;
 
 --
--- Name: article_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+-- Name: shipment; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-ALTER TABLE ONLY article
-    ADD CONSTRAINT article_id PRIMARY KEY (id);
+CREATE TABLE shipment (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    order_id integer NOT NULL,
+    carrier character varying NOT NULL,
+    service_level character varying,
+    tracking_number character varying,
+    status character varying DEFAULT 'pending'::character varying NOT NULL,
+    recipient_name character varying NOT NULL,
+    address_line1 character varying NOT NULL,
+    address_line2 character varying,
+    city character varying NOT NULL,
+    region character varying,
+    postal_code character varying,
+    country character(2) NOT NULL,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE shipment OWNER TO postgres;
+
 --
--- Name: user_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+-- Name: shipment_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY "user"
-    ADD CONSTRAINT user_id PRIMARY KEY (id);
+CREATE SEQUENCE shipment_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE shipment_id_seq OWNER TO postgres;
 
 --
--- Name: product_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+-- Name: shipment_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY product
-    ADD CONSTRAINT product_id PRIMARY KEY (id);
+ALTER SEQUENCE shipment_id_seq OWNED BY shipment.id;
+
+--
+-- Name: order_item; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE order_item (
+    id integer NOT NULL,
+    order_id integer NOT NULL,
+    product_id integer NOT NULL,
+    variant_id integer,
+    quantity integer NOT NULL,
+    unit_price bigint NOT NULL,
+    tax_rate integer DEFAULT 0 NOT NULL,
+    subtotal bigint NOT NULL,
+    tax bigint NOT NULL,
+    total bigint NOT NULL,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE order_item OWNER TO postgres;
+
 --
--- Name: article_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: order_item_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY article
-    ADD CONSTRAINT article_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+CREATE SEQUENCE order_item_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE order_item_id_seq OWNER TO postgres;
 
 --
--- Name: product_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+-- Name: order_item_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-ALTER TABLE ONLY product
-    ADD CONSTRAINT article_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+ALTER SEQUENCE order_item_id_seq OWNED BY order_item.id;
+
+--
+-- Name: order_status_history; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE order_status_history (
+    id integer NOT NULL,
+    order_id integer NOT NULL,
+    user_id integer,
+    from_status character varying NOT NULL,
+    to_status character varying NOT NULL,
+    reason text,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE order_status_history OWNER TO postgres;
 
 --
--- TOC entry 2284 (class 2620 OID 36647)
--- Name: article create_article_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: order_status_history_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-CREATE TRIGGER create_article_created_at BEFORE INSERT ON article FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+CREATE SEQUENCE order_status_history_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
 
+ALTER TABLE order_status_history_id_seq OWNER TO postgres;
 
 --
--- TOC entry 2286 (class 2620 OID 36653)
--- Name: user create_user_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: order_status_history_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-CREATE TRIGGER create_user_created_at BEFORE INSERT ON "user" FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+ALTER SEQUENCE order_status_history_id_seq OWNED BY order_status_history.id;
 
 --
--- TOC entry 2284 (class 2620 OID 36647)
--- Name: article create_article_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: invoice_item; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-CREATE TRIGGER create_product_created_at BEFORE INSERT ON product FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+CREATE TABLE invoice_item (
+    id integer NOT NULL,
+    invoice_id integer NOT NULL,
+    product_id integer,
+    description character varying NOT NULL,
+    quantity integer NOT NULL,
+    unit_price bigint NOT NULL,
+    tax_rate integer DEFAULT 0 NOT NULL,
+    subtotal bigint NOT NULL,
+    tax bigint NOT NULL,
+    total bigint NOT NULL,
+    updated_at integer,
+    created_at integer
+);
 
 
+ALTER TABLE invoice_item OWNER TO postgres;
+
 --
--- TOC entry 2285 (class 2620 OID 36648)
--- Name: article update_article_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: invoice_item_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
 
-CREATE TRIGGER update_article_updated_at BEFORE UPDATE ON article FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+CREATE SEQUENCE invoice_item_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
 
+ALTER TABLE invoice_item_id_seq OWNER TO postgres;
 
 --
--- TOC entry 2287 (class 2620 OID 36654)
--- Name: user update_user_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: invoice_item_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
 --
 
-CREATE TRIGGER update_user_updated_at BEFORE UPDATE ON "user" FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+ALTER SEQUENCE invoice_item_id_seq OWNED BY invoice_item.id;
 
 --
--- TOC entry 2285 (class 2620 OID 36648)
--- Name: article update_article_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+-- Name: invoice_sequence; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-CREATE TRIGGER update_product_updated_at BEFORE UPDATE ON product FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+CREATE TABLE invoice_sequence (
+    organization_id integer NOT NULL,
+    year integer NOT NULL,
+    last_value integer DEFAULT 0 NOT NULL
+);
+
 
+ALTER TABLE invoice_sequence OWNER TO postgres;
 
 --
--- Name: public; Type: ACL; Schema: -; Owner: postgres
+-- Name: payment; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
 --
 
-REVOKE ALL ON SCHEMA public FROM PUBLIC;
-REVOKE ALL ON SCHEMA public FROM postgres;
-GRANT ALL ON SCHEMA public TO postgres;
-GRANT ALL ON SCHEMA public TO PUBLIC;
+CREATE TABLE payment (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    invoice_id integer NOT NULL,
+    amount bigint NOT NULL,
+    method character varying NOT NULL,
+    reference character varying,
+    paid_on date NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE payment OWNER TO postgres;
+
+--
+-- Name: payment_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE payment_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE payment_id_seq OWNER TO postgres;
+
+--
+-- Name: payment_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE payment_id_seq OWNED BY payment.id;
+
+--
+-- Name: shipment_parcel; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE shipment_parcel (
+    id integer NOT NULL,
+    shipment_id integer NOT NULL,
+    weight integer NOT NULL,
+    length integer NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE shipment_parcel OWNER TO postgres;
+
+--
+-- Name: shipment_parcel_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE shipment_parcel_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE shipment_parcel_id_seq OWNER TO postgres;
+
+--
+-- Name: shipment_parcel_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE shipment_parcel_id_seq OWNED BY shipment_parcel.id;
+
+--
+-- Name: shipment_event; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE shipment_event (
+    id integer NOT NULL,
+    shipment_id integer NOT NULL,
+    status character varying NOT NULL,
+    description character varying,
+    location character varying,
+    occurred_at integer NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE shipment_event OWNER TO postgres;
+
+--
+-- Name: shipment_event_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE shipment_event_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE shipment_event_id_seq OWNER TO postgres;
+
+--
+-- Name: shipment_event_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE shipment_event_id_seq OWNED BY shipment_event.id;
+
+--
+-- Name: shipment_item; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE shipment_item (
+    id integer NOT NULL,
+    shipment_id integer NOT NULL,
+    order_item_id integer NOT NULL,
+    quantity integer NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE shipment_item OWNER TO postgres;
+
+--
+-- Name: shipment_item_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE shipment_item_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE shipment_item_id_seq OWNER TO postgres;
+
+--
+-- Name: shipment_item_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE shipment_item_id_seq OWNED BY shipment_item.id;
+
+--
+-- Name: product_variant; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE product_variant (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    product_id integer NOT NULL,
+    sku character varying NOT NULL,
+    size character varying,
+    color character varying,
+    price bigint,
+    active boolean DEFAULT true NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE product_variant OWNER TO postgres;
+
+--
+-- Name: product_variant_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE product_variant_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE product_variant_id_seq OWNER TO postgres;
+
+--
+-- Name: product_variant_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE product_variant_id_seq OWNED BY product_variant.id;
+
+--
+-- Name: warehouse; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE warehouse (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    code character varying NOT NULL,
+    name character varying NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE warehouse OWNER TO postgres;
+
+--
+-- Name: warehouse_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE warehouse_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE warehouse_id_seq OWNER TO postgres;
+
+--
+-- Name: warehouse_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE warehouse_id_seq OWNED BY warehouse.id;
+
+--
+-- Name: stock_movement; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE stock_movement (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer,
+    product_id integer NOT NULL,
+    variant_id integer,
+    warehouse_id integer NOT NULL,
+    order_id integer,
+    shipment_id integer,
+    type character varying NOT NULL,
+    quantity integer NOT NULL,
+    note text,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE stock_movement OWNER TO postgres;
+
+--
+-- Name: stock_movement_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE stock_movement_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE stock_movement_id_seq OWNER TO postgres;
+
+--
+-- Name: stock_movement_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE stock_movement_id_seq OWNED BY stock_movement.id;
+
+--
+-- Name: customer_address; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE customer_address (
+    id integer NOT NULL,
+    customer_id integer NOT NULL,
+    type character varying NOT NULL,
+    is_default boolean DEFAULT false NOT NULL,
+    line1 character varying NOT NULL,
+    line2 character varying,
+    city character varying NOT NULL,
+    region character varying,
+    postal_code character varying,
+    country character(2) NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE customer_address OWNER TO postgres;
+
+--
+-- Name: customer_address_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE customer_address_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE customer_address_id_seq OWNER TO postgres;
+
+--
+-- Name: customer_address_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE customer_address_id_seq OWNED BY customer_address.id;
+
+--
+-- Name: customer_contact; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE customer_contact (
+    id integer NOT NULL,
+    customer_id integer NOT NULL,
+    name character varying NOT NULL,
+    role character varying,
+    email character varying,
+    phone character varying,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE customer_contact OWNER TO postgres;
+
+--
+-- Name: customer_contact_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE customer_contact_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE customer_contact_id_seq OWNER TO postgres;
+
+--
+-- Name: customer_contact_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE customer_contact_id_seq OWNED BY customer_contact.id;
+
+--
+-- Name: organization; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE organization (
+    id integer NOT NULL,
+    name character varying NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE organization OWNER TO postgres;
+
+--
+-- Name: organization_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE organization_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE organization_id_seq OWNER TO postgres;
+
+--
+-- Name: organization_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE organization_id_seq OWNED BY organization.id;
+
+--
+-- Name: membership; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE membership (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer NOT NULL,
+    role character varying DEFAULT 'member'::character varying NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE membership OWNER TO postgres;
+
+--
+-- Name: membership_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE membership_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE membership_id_seq OWNER TO postgres;
+
+--
+-- Name: membership_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE membership_id_seq OWNED BY membership.id;
+
+--
+-- Name: invitation; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE invitation (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    email character varying NOT NULL,
+    role character varying DEFAULT 'member'::character varying NOT NULL,
+    invited_by integer,
+    accepted_at integer,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE invitation OWNER TO postgres;
+
+--
+-- Name: invitation_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE invitation_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE invitation_id_seq OWNER TO postgres;
+
+--
+-- Name: invitation_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE invitation_id_seq OWNED BY invitation.id;
+
+--
+-- Name: role_permission; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE role_permission (
+    role character varying NOT NULL,
+    permission character varying NOT NULL
+);
+
+
+ALTER TABLE role_permission OWNER TO postgres;
+
+--
+-- Name: api_key; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE api_key (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer NOT NULL,
+    name character varying NOT NULL,
+    prefix character varying NOT NULL,
+    key_hash character varying NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    expires_at integer,
+    last_used_at integer,
+    revoked_at integer,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE api_key OWNER TO postgres;
+
+--
+-- Name: api_key_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE api_key_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE api_key_id_seq OWNER TO postgres;
+
+--
+-- Name: api_key_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE api_key_id_seq OWNED BY api_key.id;
+
+--
+-- Name: oauth_client; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE oauth_client (
+    id integer NOT NULL,
+    organization_id integer NOT NULL,
+    user_id integer NOT NULL,
+    name character varying NOT NULL,
+    client_id character varying NOT NULL,
+    secret_hash character varying,
+    redirect_uris text[] DEFAULT '{}'::text[] NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE oauth_client OWNER TO postgres;
+
+--
+-- Name: oauth_client_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE oauth_client_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE oauth_client_id_seq OWNER TO postgres;
+
+--
+-- Name: oauth_client_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE oauth_client_id_seq OWNED BY oauth_client.id;
+
+--
+-- Name: user_identity; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE user_identity (
+    id integer NOT NULL,
+    user_id integer NOT NULL,
+    provider character varying NOT NULL,
+    subject character varying NOT NULL,
+    email character varying,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE user_identity OWNER TO postgres;
+
+--
+-- Name: user_identity_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE user_identity_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE user_identity_id_seq OWNER TO postgres;
+
+--
+-- Name: user_identity_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE user_identity_id_seq OWNED BY user_identity.id;
+
+--
+-- Name: user_mfa; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE user_mfa (
+    user_id integer NOT NULL,
+    secret character varying NOT NULL,
+    last_step bigint DEFAULT 0 NOT NULL,
+    enabled_at integer,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE user_mfa OWNER TO postgres;
+
+--
+-- Name: user_recovery_code; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE user_recovery_code (
+    id integer NOT NULL,
+    user_id integer NOT NULL,
+    code_hash character varying NOT NULL,
+    used_at integer,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE user_recovery_code OWNER TO postgres;
+
+--
+-- Name: user_recovery_code_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE user_recovery_code_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE user_recovery_code_id_seq OWNER TO postgres;
+
+--
+-- Name: user_recovery_code_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE user_recovery_code_id_seq OWNED BY user_recovery_code.id;
+
+--
+-- Name: audit_log; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE audit_log (
+    id integer NOT NULL,
+    user_id integer,
+    organization_id integer,
+    event character varying NOT NULL,
+    ip character varying,
+    user_agent character varying,
+    details jsonb DEFAULT '{}'::jsonb NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE audit_log OWNER TO postgres;
+
+--
+-- Name: audit_log_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE audit_log_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE audit_log_id_seq OWNER TO postgres;
+
+--
+-- Name: audit_log_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE audit_log_id_seq OWNED BY audit_log.id;
+
+--
+-- Name: signing_key; Type: TABLE; Schema: public; Owner: postgres; Tablespace:
+--
+
+CREATE TABLE signing_key (
+    id integer NOT NULL,
+    kid character varying NOT NULL,
+    algorithm character varying NOT NULL,
+    private_key text NOT NULL,
+    expires_at integer NOT NULL,
+    updated_at integer,
+    created_at integer
+);
+
+
+ALTER TABLE signing_key OWNER TO postgres;
+
+--
+-- Name: signing_key_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE signing_key_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE signing_key_id_seq OWNER TO postgres;
+
+--
+-- Name: signing_key_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
+--
+
+ALTER SEQUENCE signing_key_id_seq OWNED BY signing_key.id;
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY article ALTER COLUMN id SET DEFAULT nextval('article_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "user" ALTER COLUMN id SET DEFAULT nextval('user_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product ALTER COLUMN id SET DEFAULT nextval('product_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer ALTER COLUMN id SET DEFAULT nextval('customer_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "order" ALTER COLUMN id SET DEFAULT nextval('order_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice ALTER COLUMN id SET DEFAULT nextval('invoice_id_seq'::regclass);
+
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment ALTER COLUMN id SET DEFAULT nextval('shipment_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_item ALTER COLUMN id SET DEFAULT nextval('order_item_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_status_history ALTER COLUMN id SET DEFAULT nextval('order_status_history_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice_item ALTER COLUMN id SET DEFAULT nextval('invoice_item_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment ALTER COLUMN id SET DEFAULT nextval('payment_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_parcel ALTER COLUMN id SET DEFAULT nextval('shipment_parcel_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_event ALTER COLUMN id SET DEFAULT nextval('shipment_event_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_item ALTER COLUMN id SET DEFAULT nextval('shipment_item_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant ALTER COLUMN id SET DEFAULT nextval('product_variant_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY warehouse ALTER COLUMN id SET DEFAULT nextval('warehouse_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement ALTER COLUMN id SET DEFAULT nextval('stock_movement_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer_address ALTER COLUMN id SET DEFAULT nextval('customer_address_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer_contact ALTER COLUMN id SET DEFAULT nextval('customer_contact_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY organization ALTER COLUMN id SET DEFAULT nextval('organization_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY membership ALTER COLUMN id SET DEFAULT nextval('membership_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invitation ALTER COLUMN id SET DEFAULT nextval('invitation_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY api_key ALTER COLUMN id SET DEFAULT nextval('api_key_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY oauth_client ALTER COLUMN id SET DEFAULT nextval('oauth_client_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_identity ALTER COLUMN id SET DEFAULT nextval('user_identity_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_recovery_code ALTER COLUMN id SET DEFAULT nextval('user_recovery_code_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY audit_log ALTER COLUMN id SET DEFAULT nextval('audit_log_id_seq'::regclass);
+
+--
+-- Name: id; Type: DEFAULT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY signing_key ALTER COLUMN id SET DEFAULT nextval('signing_key_id_seq'::regclass);
+
+--
+-- Data for Name: article; Type: TABLE DATA; Schema: public; Owner: postgres
+--
+
+COPY article (id, organization_id, user_id, title, content, updated_at, created_at) FROM stdin;
+\.
+
+
+--
+-- Name: article_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('article_id_seq', 1, false);
+
+
+--
+-- Data for Name: user; Type: TABLE DATA; Schema: public; Owner: postgres
+--
+
+COPY "user" (id, email, password, name, updated_at, created_at) FROM stdin;
+\.
+
+
+--
+-- Name: user_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('user_id_seq', 1, false);
+
+--
+-- Data for Name: role_permission; Type: TABLE DATA; Schema: public; Owner: postgres
+--
+-- The permissions are "<resource>:<action>", read-only only reads and only the owners, admins and billing write invoices and payments,
+-- the owners and admins manage the API keys and OAuth clients and unlock the logins of the members
+--
+
+COPY role_permission (role, permission) FROM stdin;
+owner	api_key:manage
+owner	article:read
+owner	article:write
+owner	customer:read
+owner	customer:write
+owner	inventory:read
+owner	inventory:write
+owner	invoice:read
+owner	invoice:write
+owner	member:invite
+owner	member:unlock
+owner	oauth_client:manage
+owner	order:read
+owner	order:write
+owner	payment:read
+owner	payment:write
+owner	product:read
+owner	product:write
+owner	shipment:read
+owner	shipment:write
+admin	api_key:manage
+admin	article:read
+admin	article:write
+admin	customer:read
+admin	customer:write
+admin	inventory:read
+admin	inventory:write
+admin	invoice:read
+admin	invoice:write
+admin	member:invite
+admin	member:unlock
+admin	oauth_client:manage
+admin	order:read
+admin	order:write
+admin	payment:read
+admin	payment:write
+admin	product:read
+admin	product:write
+admin	shipment:read
+admin	shipment:write
+member	article:read
+member	article:write
+member	customer:read
+member	customer:write
+member	inventory:read
+member	inventory:write
+member	invoice:read
+member	order:read
+member	order:write
+member	payment:read
+member	product:read
+member	product:write
+member	shipment:read
+member	shipment:write
+billing	article:read
+billing	customer:read
+billing	inventory:read
+billing	invoice:read
+billing	invoice:write
+billing	order:read
+billing	payment:read
+billing	payment:write
+billing	product:read
+billing	shipment:read
+read-only	article:read
+read-only	customer:read
+read-only	inventory:read
+read-only	invoice:read
+read-only	order:read
+read-only	payment:read
+read-only	product:read
+read-only	shipment:read
+\.
+
+
+--
+-- Data for Name: product; Type: TABLE DATA; Schema: public; Owner: postgres
+--
+
+COPY product (id, organization_id, user_id, sku, name, description, price, currency, active, track_inventory, attributes, updated_at, created_at) FROM stdin;
+\.
+
+
+--
+-- Name: product_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('product_id_seq', 1, false);
+
+--
+-- Name: customer_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('customer_id_seq', 1, false);
+
+--
+-- Name: order_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('order_id_seq', 1, false);
+
+--
+-- Name: invoice_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('invoice_id_seq', 1, false);
+
+--
+-- Name: shipment_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('shipment_id_seq', 1, false);
+
+--
+-- Name: order_item_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('order_item_id_seq', 1, false);
+
+--
+-- Name: order_status_history_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('order_status_history_id_seq', 1, false);
+
+--
+-- Name: invoice_item_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('invoice_item_id_seq', 1, false);
+
+--
+-- Name: payment_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('payment_id_seq', 1, false);
+
+--
+-- Name: shipment_parcel_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('shipment_parcel_id_seq', 1, false);
+
+--
+-- Name: shipment_event_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('shipment_event_id_seq', 1, false);
+
+--
+-- Name: shipment_item_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('shipment_item_id_seq', 1, false);
+
+--
+-- Name: product_variant_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('product_variant_id_seq', 1, false);
+
+--
+-- Name: warehouse_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('warehouse_id_seq', 1, false);
+
+--
+-- Name: stock_movement_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('stock_movement_id_seq', 1, false);
+
+--
+-- Name: customer_address_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('customer_address_id_seq', 1, false);
+
+--
+-- Name: customer_contact_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('customer_contact_id_seq', 1, false);
+
+--
+-- Name: organization_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('organization_id_seq', 1, false);
+
+--
+-- Name: membership_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('membership_id_seq', 1, false);
+
+--
+-- Name: invitation_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('invitation_id_seq', 1, false);
+
+--
+-- Name: api_key_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('api_key_id_seq', 1, false);
+
+--
+-- Name: oauth_client_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('oauth_client_id_seq', 1, false);
+
+--
+-- Name: user_identity_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('user_identity_id_seq', 1, false);
+
+--
+-- Name: user_recovery_code_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('user_recovery_code_id_seq', 1, false);
+
+--
+-- Name: audit_log_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('audit_log_id_seq', 1, false);
+
+--
+-- Name: signing_key_id_seq; Type: SEQUENCE SET; Schema: public; Owner: postgres
+--
+
+SELECT pg_catalog.setval('signing_key_id_seq', 1, false);
+
+--
+-- Name: article_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY article
+    ADD CONSTRAINT article_id PRIMARY KEY (id);
+
+
+--
+-- Name: user_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY "user"
+    ADD CONSTRAINT user_id PRIMARY KEY (id);
+
+--
+-- Name: product_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY product
+    ADD CONSTRAINT product_id PRIMARY KEY (id);
+
+--
+-- Name: customer_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY customer
+    ADD CONSTRAINT customer_id PRIMARY KEY (id);
+
+--
+-- Name: order_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY "order"
+    ADD CONSTRAINT order_id PRIMARY KEY (id);
+
+--
+-- Name: invoice_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_id PRIMARY KEY (id);
+
+--
+-- Name: shipment_id; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY shipment
+    ADD CONSTRAINT shipment_id PRIMARY KEY (id);
+
+
+--
+-- Name: order_item_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY order_item
+    ADD CONSTRAINT order_item_pkey PRIMARY KEY (id);
+
+--
+-- Name: order_item_quantity; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_item
+    ADD CONSTRAINT order_item_quantity CHECK (quantity > 0);
+
+--
+-- Name: invoice_status; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_status CHECK (status IN ('draft', 'issued', 'paid', 'void'));
+
+--
+-- Name: invoice_organization_id_number; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_organization_id_number UNIQUE (organization_id, number);
+
+--
+-- Name: order_status; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "order"
+    ADD CONSTRAINT order_status CHECK (status IN ('draft', 'placed', 'paid', 'fulfilled', 'cancelled', 'refunded'));
+
+--
+-- Name: order_status_history_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY order_status_history
+    ADD CONSTRAINT order_status_history_pkey PRIMARY KEY (id);
+
+--
+-- Name: invoice_item_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY invoice_item
+    ADD CONSTRAINT invoice_item_pkey PRIMARY KEY (id);
+
+--
+-- Name: invoice_sequence_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY invoice_sequence
+    ADD CONSTRAINT invoice_sequence_pkey PRIMARY KEY (organization_id, year);
+
+--
+-- Name: payment_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_pkey PRIMARY KEY (id);
+
+--
+-- Name: payment_amount; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_amount CHECK (amount <> 0);
+
+--
+-- Name: payment_method; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_method CHECK (method IN ('cash', 'bank_transfer', 'card', 'cheque', 'other'));
+
+--
+-- Name: shipment_parcel_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY shipment_parcel
+    ADD CONSTRAINT shipment_parcel_pkey PRIMARY KEY (id);
+
+--
+-- Name: shipment_parcel_dimensions; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_parcel
+    ADD CONSTRAINT shipment_parcel_dimensions CHECK (weight > 0 AND length > 0 AND width > 0 AND height > 0);
+
+--
+-- Name: shipment_event_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY shipment_event
+    ADD CONSTRAINT shipment_event_pkey PRIMARY KEY (id);
+
+--
+-- Name: shipment_event_status; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_event
+    ADD CONSTRAINT shipment_event_status CHECK (status IN ('pending', 'in_transit', 'out_for_delivery', 'delivered', 'exception', 'returned'));
+
+--
+-- Name: shipment_status; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment
+    ADD CONSTRAINT shipment_status CHECK (status IN ('pending', 'in_transit', 'out_for_delivery', 'delivered', 'exception', 'returned'));
+
+--
+-- Name: shipment_item_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY shipment_item
+    ADD CONSTRAINT shipment_item_pkey PRIMARY KEY (id);
+
+--
+-- Name: shipment_item_quantity; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_item
+    ADD CONSTRAINT shipment_item_quantity CHECK (quantity > 0);
+
+--
+-- Name: shipment_item_shipment_id_order_item_id; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_item
+    ADD CONSTRAINT shipment_item_shipment_id_order_item_id UNIQUE (shipment_id, order_item_id);
+
+--
+-- Name: product_price; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product
+    ADD CONSTRAINT product_price CHECK (price >= 0);
+
+--
+-- Name: product_organization_id_sku; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product
+    ADD CONSTRAINT product_organization_id_sku UNIQUE (organization_id, sku);
+
+--
+-- Name: product_variant_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_pkey PRIMARY KEY (id);
+
+--
+-- Name: product_variant_price; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_price CHECK (price IS NULL OR price >= 0);
+
+--
+-- Name: product_variant_organization_id_sku; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_organization_id_sku UNIQUE (organization_id, sku);
+
+--
+-- Name: warehouse_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY warehouse
+    ADD CONSTRAINT warehouse_pkey PRIMARY KEY (id);
+
+--
+-- Name: warehouse_organization_id_code; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY warehouse
+    ADD CONSTRAINT warehouse_organization_id_code UNIQUE (organization_id, code);
+
+--
+-- Name: stock_movement_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_pkey PRIMARY KEY (id);
+
+--
+-- Name: stock_movement_type; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_type CHECK (type IN ('receipt', 'adjustment', 'reservation', 'release', 'shipment'));
+
+--
+-- Name: stock_movement_quantity; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_quantity CHECK ((type IN ('receipt', 'reservation') AND quantity > 0) OR (type IN ('release', 'shipment') AND quantity < 0) OR (type = 'adjustment' AND quantity <> 0));
+
+--
+-- Name: stock_movement_product_id_variant_id_warehouse_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX stock_movement_product_id_variant_id_warehouse_id ON stock_movement USING btree (product_id, variant_id, warehouse_id);
+
+--
+-- Name: stock_movement_order_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX stock_movement_order_id ON stock_movement USING btree (order_id);
+
+--
+-- Name: customer_address_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY customer_address
+    ADD CONSTRAINT customer_address_pkey PRIMARY KEY (id);
+
+--
+-- Name: customer_address_type; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer_address
+    ADD CONSTRAINT customer_address_type CHECK (type IN ('billing', 'shipping'));
+
+--
+-- Name: customer_address_customer_id_type_default; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX customer_address_customer_id_type_default ON customer_address USING btree (customer_id, type) WHERE is_default;
+
+--
+-- Name: customer_contact_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY customer_contact
+    ADD CONSTRAINT customer_contact_pkey PRIMARY KEY (id);
+
+--
+-- Name: article_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX article_organization_id ON article USING btree (organization_id);
+
+--
+-- Name: product_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX product_organization_id ON product USING btree (organization_id);
+
+--
+-- Name: customer_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX customer_organization_id ON customer USING btree (organization_id);
+
+--
+-- Name: order_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX order_organization_id ON "order" USING btree (organization_id);
+
+--
+-- Name: invoice_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX invoice_organization_id ON invoice USING btree (organization_id);
+
+--
+-- Name: shipment_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX shipment_organization_id ON shipment USING btree (organization_id);
+
+--
+-- Name: payment_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX payment_organization_id ON payment USING btree (organization_id);
+
+--
+-- Name: warehouse_organization_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX warehouse_organization_id ON warehouse USING btree (organization_id);
+
+--
+-- Name: organization_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY organization
+    ADD CONSTRAINT organization_pkey PRIMARY KEY (id);
+
+--
+-- Name: membership_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY membership
+    ADD CONSTRAINT membership_pkey PRIMARY KEY (id);
+
+--
+-- Name: membership_role; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY membership
+    ADD CONSTRAINT membership_role CHECK (role IN ('owner', 'admin', 'member', 'billing', 'read-only'));
+
+--
+-- Name: membership_organization_id_user_id; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY membership
+    ADD CONSTRAINT membership_organization_id_user_id UNIQUE (organization_id, user_id);
+
+--
+-- Name: membership_user_id; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX membership_user_id ON membership USING btree (user_id);
+
+--
+-- Name: audit_log_user_id_created_at; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX audit_log_user_id_created_at ON audit_log USING btree (user_id, created_at);
+
+--
+-- Name: invitation_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY invitation
+    ADD CONSTRAINT invitation_pkey PRIMARY KEY (id);
+
+--
+-- Name: invitation_role; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invitation
+    ADD CONSTRAINT invitation_role CHECK (role IN ('admin', 'member', 'billing', 'read-only'));
+
+--
+-- Name: invitation_organization_id_email; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE UNIQUE INDEX invitation_organization_id_email ON invitation USING btree (organization_id, lower((email)::text)) WHERE (accepted_at IS NULL);
+
+--
+-- Name: role_permission_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY role_permission
+    ADD CONSTRAINT role_permission_pkey PRIMARY KEY (role, permission);
+
+--
+-- Name: role_permission_role; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY role_permission
+    ADD CONSTRAINT role_permission_role CHECK (role IN ('owner', 'admin', 'member', 'billing', 'read-only'));
+
+--
+-- Name: api_key_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY api_key
+    ADD CONSTRAINT api_key_pkey PRIMARY KEY (id);
+
+--
+-- Name: api_key_key_hash; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY api_key
+    ADD CONSTRAINT api_key_key_hash UNIQUE (key_hash);
+
+--
+-- Name: oauth_client_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY oauth_client
+    ADD CONSTRAINT oauth_client_pkey PRIMARY KEY (id);
+
+--
+-- Name: oauth_client_client_id; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY oauth_client
+    ADD CONSTRAINT oauth_client_client_id UNIQUE (client_id);
+
+--
+-- Name: user_identity_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY user_identity
+    ADD CONSTRAINT user_identity_pkey PRIMARY KEY (id);
+
+--
+-- Name: user_identity_provider_subject; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_identity
+    ADD CONSTRAINT user_identity_provider_subject UNIQUE (provider, subject);
+
+--
+-- Name: user_mfa_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY user_mfa
+    ADD CONSTRAINT user_mfa_pkey PRIMARY KEY (user_id);
+
+--
+-- Name: user_recovery_code_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY user_recovery_code
+    ADD CONSTRAINT user_recovery_code_pkey PRIMARY KEY (id);
+
+--
+-- Name: user_recovery_code_user_id_code_hash; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_recovery_code
+    ADD CONSTRAINT user_recovery_code_user_id_code_hash UNIQUE (user_id, code_hash);
+
+--
+-- Name: audit_log_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY audit_log
+    ADD CONSTRAINT audit_log_pkey PRIMARY KEY (id);
+
+--
+-- Name: signing_key_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres; Tablespace:
+--
+
+ALTER TABLE ONLY signing_key
+    ADD CONSTRAINT signing_key_pkey PRIMARY KEY (id);
+
+--
+-- Name: signing_key_kid; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY signing_key
+    ADD CONSTRAINT signing_key_kid UNIQUE (kid);
+
+--
+-- Name: article_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY article
+    ADD CONSTRAINT article_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: product_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product
+    ADD CONSTRAINT article_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: customer_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer
+    ADD CONSTRAINT customer_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: order_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "order"
+    ADD CONSTRAINT order_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: order_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "order"
+    ADD CONSTRAINT order_customer_id FOREIGN KEY (customer_id) REFERENCES customer(id) ON UPDATE CASCADE;
+
+--
+-- Name: invoice_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: invoice_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_order_id FOREIGN KEY (order_id) REFERENCES "order"(id) ON UPDATE CASCADE;
+
+--
+-- Name: invoice_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_customer_id FOREIGN KEY (customer_id) REFERENCES customer(id) ON UPDATE CASCADE;
+
+--
+-- Name: shipment_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment
+    ADD CONSTRAINT shipment_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+
+
+--
+-- Name: order_item_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_item
+    ADD CONSTRAINT order_item_order_id FOREIGN KEY (order_id) REFERENCES "order"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: order_item_product_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_item
+    ADD CONSTRAINT order_item_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON UPDATE CASCADE;
+
+--
+-- Name: order_item_variant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_item
+    ADD CONSTRAINT order_item_variant_id FOREIGN KEY (variant_id) REFERENCES product_variant(id) ON UPDATE CASCADE;
+
+--
+-- Name: order_status_history_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_status_history
+    ADD CONSTRAINT order_status_history_order_id FOREIGN KEY (order_id) REFERENCES "order"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: order_status_history_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY order_status_history
+    ADD CONSTRAINT order_status_history_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: invoice_item_invoice_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice_item
+    ADD CONSTRAINT invoice_item_invoice_id FOREIGN KEY (invoice_id) REFERENCES invoice(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: invoice_item_product_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice_item
+    ADD CONSTRAINT invoice_item_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: invoice_sequence_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice_sequence
+    ADD CONSTRAINT invoice_sequence_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: payment_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: payment_invoice_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_invoice_id FOREIGN KEY (invoice_id) REFERENCES invoice(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_parcel_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_parcel
+    ADD CONSTRAINT shipment_parcel_shipment_id FOREIGN KEY (shipment_id) REFERENCES shipment(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_event_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_event
+    ADD CONSTRAINT shipment_event_shipment_id FOREIGN KEY (shipment_id) REFERENCES shipment(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment
+    ADD CONSTRAINT shipment_order_id FOREIGN KEY (order_id) REFERENCES "order"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_item_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_item
+    ADD CONSTRAINT shipment_item_shipment_id FOREIGN KEY (shipment_id) REFERENCES shipment(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_item_order_item_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment_item
+    ADD CONSTRAINT shipment_item_order_item_id FOREIGN KEY (order_item_id) REFERENCES order_item(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: product_variant_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: product_variant_product_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: warehouse_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY warehouse
+    ADD CONSTRAINT warehouse_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: stock_movement_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: stock_movement_product_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON UPDATE CASCADE;
+
+--
+-- Name: stock_movement_variant_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_variant_id FOREIGN KEY (variant_id) REFERENCES product_variant(id) ON UPDATE CASCADE;
+
+--
+-- Name: stock_movement_warehouse_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_warehouse_id FOREIGN KEY (warehouse_id) REFERENCES warehouse(id) ON UPDATE CASCADE;
+
+--
+-- Name: stock_movement_order_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_order_id FOREIGN KEY (order_id) REFERENCES "order"(id) ON UPDATE CASCADE;
+
+--
+-- Name: stock_movement_shipment_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_shipment_id FOREIGN KEY (shipment_id) REFERENCES shipment(id) ON UPDATE CASCADE;
+
+--
+-- Name: customer_address_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer_address
+    ADD CONSTRAINT customer_address_customer_id FOREIGN KEY (customer_id) REFERENCES customer(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: customer_contact_customer_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer_contact
+    ADD CONSTRAINT customer_contact_customer_id FOREIGN KEY (customer_id) REFERENCES customer(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: article_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY article
+    ADD CONSTRAINT article_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: product_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product
+    ADD CONSTRAINT product_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: customer_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY customer
+    ADD CONSTRAINT customer_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: order_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY "order"
+    ADD CONSTRAINT order_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: invoice_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invoice
+    ADD CONSTRAINT invoice_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: shipment_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY shipment
+    ADD CONSTRAINT shipment_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: payment_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY payment
+    ADD CONSTRAINT payment_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: product_variant_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY product_variant
+    ADD CONSTRAINT product_variant_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: warehouse_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY warehouse
+    ADD CONSTRAINT warehouse_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: stock_movement_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY stock_movement
+    ADD CONSTRAINT stock_movement_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: membership_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY membership
+    ADD CONSTRAINT membership_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: membership_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY membership
+    ADD CONSTRAINT membership_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: invitation_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invitation
+    ADD CONSTRAINT invitation_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: invitation_invited_by; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY invitation
+    ADD CONSTRAINT invitation_invited_by FOREIGN KEY (invited_by) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- Name: api_key_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY api_key
+    ADD CONSTRAINT api_key_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: api_key_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY api_key
+    ADD CONSTRAINT api_key_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: oauth_client_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY oauth_client
+    ADD CONSTRAINT oauth_client_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: oauth_client_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY oauth_client
+    ADD CONSTRAINT oauth_client_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: user_identity_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_identity
+    ADD CONSTRAINT user_identity_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: user_mfa_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_mfa
+    ADD CONSTRAINT user_mfa_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: user_recovery_code_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY user_recovery_code
+    ADD CONSTRAINT user_recovery_code_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: audit_log_user_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY audit_log
+    ADD CONSTRAINT audit_log_user_id FOREIGN KEY (user_id) REFERENCES "user"(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+--
+-- Name: audit_log_organization_id; Type: FK CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY audit_log
+    ADD CONSTRAINT audit_log_organization_id FOREIGN KEY (organization_id) REFERENCES organization(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+--
+-- TOC entry 2284 (class 2620 OID 36647)
+-- Name: article create_article_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_article_created_at BEFORE INSERT ON article FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+
+--
+-- TOC entry 2286 (class 2620 OID 36653)
+-- Name: user create_user_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_user_created_at BEFORE INSERT ON "user" FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- TOC entry 2284 (class 2620 OID 36647)
+-- Name: article create_article_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_product_created_at BEFORE INSERT ON product FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+
+--
+-- TOC entry 2285 (class 2620 OID 36648)
+-- Name: article update_article_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_article_updated_at BEFORE UPDATE ON article FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+
+--
+-- TOC entry 2287 (class 2620 OID 36654)
+-- Name: user update_user_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_user_updated_at BEFORE UPDATE ON "user" FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- TOC entry 2285 (class 2620 OID 36648)
+-- Name: article update_article_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_product_updated_at BEFORE UPDATE ON product FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: customer create_customer_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_customer_created_at BEFORE INSERT ON customer FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: customer update_customer_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_customer_updated_at BEFORE UPDATE ON customer FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: order create_order_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_order_created_at BEFORE INSERT ON "order" FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: order update_order_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_order_updated_at BEFORE UPDATE ON "order" FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: invoice create_invoice_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_invoice_created_at BEFORE INSERT ON invoice FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: invoice update_invoice_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_invoice_updated_at BEFORE UPDATE ON invoice FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: invoice invoice_immutable; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER invoice_immutable BEFORE UPDATE OR DELETE ON invoice FOR EACH ROW EXECUTE PROCEDURE invoice_immutable();
+
+--
+-- Name: shipment create_shipment_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_shipment_created_at BEFORE INSERT ON shipment FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: shipment update_shipment_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_shipment_updated_at BEFORE UPDATE ON shipment FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+
+--
+-- Name: order_item create_order_item_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_order_item_created_at BEFORE INSERT ON order_item FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: order_item update_order_item_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_order_item_updated_at BEFORE UPDATE ON order_item FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: order_status_history create_order_status_history_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_order_status_history_created_at BEFORE INSERT ON order_status_history FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: invoice_item create_invoice_item_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_invoice_item_created_at BEFORE INSERT ON invoice_item FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: invoice_item update_invoice_item_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_invoice_item_updated_at BEFORE UPDATE ON invoice_item FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: invoice_item invoice_item_immutable; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER invoice_item_immutable BEFORE INSERT OR UPDATE OR DELETE ON invoice_item FOR EACH ROW EXECUTE PROCEDURE invoice_item_immutable();
+
+--
+-- Name: payment create_payment_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_payment_created_at BEFORE INSERT ON payment FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: payment payment_immutable; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER payment_immutable BEFORE UPDATE OR DELETE ON payment FOR EACH ROW EXECUTE PROCEDURE payment_immutable();
+
+--
+-- Name: shipment_parcel create_shipment_parcel_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_shipment_parcel_created_at BEFORE INSERT ON shipment_parcel FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: shipment_parcel update_shipment_parcel_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_shipment_parcel_updated_at BEFORE UPDATE ON shipment_parcel FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: shipment_event create_shipment_event_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_shipment_event_created_at BEFORE INSERT ON shipment_event FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: shipment_event shipment_event_immutable; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER shipment_event_immutable BEFORE UPDATE ON shipment_event FOR EACH ROW EXECUTE PROCEDURE shipment_event_immutable();
+
+--
+-- Name: shipment_item create_shipment_item_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_shipment_item_created_at BEFORE INSERT ON shipment_item FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: shipment_item update_shipment_item_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_shipment_item_updated_at BEFORE UPDATE ON shipment_item FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: product_variant create_product_variant_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_product_variant_created_at BEFORE INSERT ON product_variant FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: product_variant update_product_variant_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_product_variant_updated_at BEFORE UPDATE ON product_variant FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: warehouse create_warehouse_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_warehouse_created_at BEFORE INSERT ON warehouse FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: warehouse update_warehouse_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_warehouse_updated_at BEFORE UPDATE ON warehouse FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: stock_movement create_stock_movement_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_stock_movement_created_at BEFORE INSERT ON stock_movement FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: stock_movement stock_movement_immutable; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER stock_movement_immutable BEFORE UPDATE OR DELETE ON stock_movement FOR EACH ROW EXECUTE PROCEDURE stock_movement_immutable();
+
+--
+-- Name: customer_address create_customer_address_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_customer_address_created_at BEFORE INSERT ON customer_address FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: customer_address update_customer_address_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_customer_address_updated_at BEFORE UPDATE ON customer_address FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: customer_contact create_customer_contact_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_customer_contact_created_at BEFORE INSERT ON customer_contact FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: customer_contact update_customer_contact_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_customer_contact_updated_at BEFORE UPDATE ON customer_contact FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: organization create_organization_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_organization_created_at BEFORE INSERT ON organization FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: organization update_organization_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_organization_updated_at BEFORE UPDATE ON organization FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: membership create_membership_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_membership_created_at BEFORE INSERT ON membership FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: membership update_membership_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_membership_updated_at BEFORE UPDATE ON membership FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: invitation create_invitation_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_invitation_created_at BEFORE INSERT ON invitation FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: invitation update_invitation_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_invitation_updated_at BEFORE UPDATE ON invitation FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: article; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE article ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: product; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE product ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: product_variant; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE product_variant ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: customer; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE customer ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: order; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE "order" ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: invoice; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE invoice ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: invoice_sequence; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE invoice_sequence ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: shipment; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE shipment ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: payment; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE payment ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: warehouse; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE warehouse ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: stock_movement; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE stock_movement ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: api_key; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE api_key ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: oauth_client; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE oauth_client ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: order_item; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE order_item ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: order_status_history; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE order_status_history ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: invoice_item; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE invoice_item ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: shipment_item; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE shipment_item ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: shipment_parcel; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE shipment_parcel ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: shipment_event; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE shipment_event ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: customer_address; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE customer_address ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: customer_contact; Type: ROW SECURITY; Schema: public; Owner: postgres
+--
+
+ALTER TABLE customer_contact ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: article article_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY article_tenant ON article USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: product product_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY product_tenant ON product USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: product_variant product_variant_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY product_variant_tenant ON product_variant USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: customer customer_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY customer_tenant ON customer USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: order order_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY order_tenant ON "order" USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: invoice invoice_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY invoice_tenant ON invoice USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: invoice_sequence invoice_sequence_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY invoice_sequence_tenant ON invoice_sequence USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: shipment shipment_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY shipment_tenant ON shipment USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: payment payment_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY payment_tenant ON payment USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: warehouse warehouse_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY warehouse_tenant ON warehouse USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: stock_movement stock_movement_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY stock_movement_tenant ON stock_movement USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: api_key api_key_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+
+CREATE POLICY api_key_tenant ON api_key USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: oauth_client oauth_client_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The clients are registered by an organization but authorized by the users of any, the token endpoint reads them outside of a tenant transaction
+--
+
+CREATE POLICY oauth_client_tenant ON oauth_client USING ((organization_id = current_organization_id())) WITH CHECK ((organization_id = current_organization_id()));
+
+--
+-- Name: order_item order_item_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their order, which its own policy filters
+--
+
+CREATE POLICY order_item_tenant ON order_item USING ((EXISTS (SELECT 1 FROM "order" p WHERE (p.id = order_item.order_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM "order" p WHERE (p.id = order_item.order_id))));
+
+--
+-- Name: order_status_history order_status_history_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their order, which its own policy filters
+--
+
+CREATE POLICY order_status_history_tenant ON order_status_history USING ((EXISTS (SELECT 1 FROM "order" p WHERE (p.id = order_status_history.order_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM "order" p WHERE (p.id = order_status_history.order_id))));
+
+--
+-- Name: invoice_item invoice_item_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their invoice, which its own policy filters
+--
+
+CREATE POLICY invoice_item_tenant ON invoice_item USING ((EXISTS (SELECT 1 FROM invoice p WHERE (p.id = invoice_item.invoice_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM invoice p WHERE (p.id = invoice_item.invoice_id))));
+
+--
+-- Name: shipment_item shipment_item_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their shipment, which its own policy filters
+--
+
+CREATE POLICY shipment_item_tenant ON shipment_item USING ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_item.shipment_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_item.shipment_id))));
+
+--
+-- Name: shipment_parcel shipment_parcel_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their shipment, which its own policy filters
+--
+
+CREATE POLICY shipment_parcel_tenant ON shipment_parcel USING ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_parcel.shipment_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_parcel.shipment_id))));
+
+--
+-- Name: shipment_event shipment_event_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their shipment, which its own policy filters
+--
+
+CREATE POLICY shipment_event_tenant ON shipment_event USING ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_event.shipment_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM shipment p WHERE (p.id = shipment_event.shipment_id))));
+
+--
+-- Name: customer_address customer_address_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their customer, which its own policy filters
+--
+
+CREATE POLICY customer_address_tenant ON customer_address USING ((EXISTS (SELECT 1 FROM customer p WHERE (p.id = customer_address.customer_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM customer p WHERE (p.id = customer_address.customer_id))));
+
+--
+-- Name: customer_contact customer_contact_tenant; Type: POLICY; Schema: public; Owner: postgres
+--
+-- The rows belong to the tenant of their customer, which its own policy filters
+--
+
+CREATE POLICY customer_contact_tenant ON customer_contact USING ((EXISTS (SELECT 1 FROM customer p WHERE (p.id = customer_contact.customer_id)))) WITH CHECK ((EXISTS (SELECT 1 FROM customer p WHERE (p.id = customer_contact.customer_id))));
+
+--
+-- Name: api_key create_api_key_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_api_key_created_at BEFORE INSERT ON api_key FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: api_key update_api_key_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_api_key_updated_at BEFORE UPDATE ON api_key FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: oauth_client create_oauth_client_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_oauth_client_created_at BEFORE INSERT ON oauth_client FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: oauth_client update_oauth_client_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_oauth_client_updated_at BEFORE UPDATE ON oauth_client FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: user_identity create_user_identity_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_user_identity_created_at BEFORE INSERT ON user_identity FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: user_identity update_user_identity_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_user_identity_updated_at BEFORE UPDATE ON user_identity FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: user_mfa create_user_mfa_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_user_mfa_created_at BEFORE INSERT ON user_mfa FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: user_mfa update_user_mfa_updated_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER update_user_mfa_updated_at BEFORE UPDATE ON user_mfa FOR EACH ROW EXECUTE PROCEDURE update_at_column();
+
+--
+-- Name: user_recovery_code create_user_recovery_code_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_user_recovery_code_created_at BEFORE INSERT ON user_recovery_code FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: audit_log create_audit_log_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_audit_log_created_at BEFORE INSERT ON audit_log FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: signing_key create_signing_key_created_at; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER create_signing_key_created_at BEFORE INSERT ON signing_key FOR EACH ROW EXECUTE PROCEDURE created_at_column();
+
+--
+-- Name: public; Type: ACL; Schema: -; Owner: postgres
+--
+
+REVOKE ALL ON SCHEMA public FROM PUBLIC;
+REVOKE ALL ON SCHEMA public FROM postgres;
+GRANT ALL ON SCHEMA public TO postgres;
+GRANT ALL ON SCHEMA public TO PUBLIC;
+
+
+--
+-- Name: app_tenant; Type: ACL; Schema: public; Owner: postgres
+--
+-- The tenant role can change any table, the policies decide which rows
+--
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO app_tenant;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO app_tenant;
+
+--
+-- The private signing keys are never needed in a tenant transaction
+--
+
+REVOKE ALL ON signing_key FROM app_tenant;
 
 
 --
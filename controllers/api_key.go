@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController ...
+type APIKeyController struct{}
+
+var apiKeyModel = new(models.APIKeyModel)
+var apiKeyForm = new(forms.APIKeyForm)
+
+// apiKeyError aborts the request with the status matching the model error
+func apiKeyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "API key not found"})
+	case errors.Is(err, models.ErrScopeNotGranted):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrExpiryInPast):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// Create ...
+// @BasePath /api/v1
+
+// Create godoc
+// @Summary Create an API key
+// @Schemes
+// @Description Creates an API key acting as the logged in user in the active organization, send it in the X-API-Key header. The key is only returned once
+// @Tags api key
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIKey
+// @Failure 403 {string} message
+// @Router /api-key [post]
+func (ctrl APIKeyController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	var form forms.CreateAPIKeyForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := apiKeyForm.Create(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	apiKey, err := apiKeyModel.Create(orgID, userID, getRole(c), form)
+	if err != nil {
+		apiKeyError(c, err, "API key could not be created")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key created, store it now as it will not be shown again", "id": apiKey.ID, "data": apiKey})
+}
+
+// All ...
+// @BasePath /api/v1
+
+// All godoc
+// @Summary List the API keys
+// @Schemes
+// @Description List the API keys of the active organization with their last use, the keys themselves are never returned
+// @Tags api key
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Router /api-keys [get]
+func (ctrl APIKeyController) All(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	results, err := apiKeyModel.All(orgID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Revoke ...
+// @BasePath /api/v1
+
+// Revoke godoc
+// @Summary Revoke an API key
+// @Schemes
+// @Description Revokes an API key of the active organization, the requests using it are rejected at once
+// @Tags api key
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.APIKey
+// @Failure 404 {string} message
+// @Router /api-key/{id} [delete]
+func (ctrl APIKeyController) Revoke(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	apiKey, err := apiKeyModel.Revoke(orgID, getID)
+	if err != nil {
+		apiKeyError(c, err, "API key could not be revoked")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked", "data": apiKey})
+}
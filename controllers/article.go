@@ -30,6 +30,7 @@ var articleForm = new(forms.ArticleForm)
 // @Success 200 {string} Helloworld
 // @Router /article [post]
 func (ctrl ArticleController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	var form forms.CreateArticleForm
@@ -40,7 +41,7 @@ func (ctrl ArticleController) Create(c *gin.Context) {
 		return
 	}
 
-	id, err := articleModel.Create(userID, form)
+	id, err := articleModel.Create(orgID, userID, form)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Article could not be created"})
 		return
@@ -62,9 +63,9 @@ func (ctrl ArticleController) Create(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /article [post]
 func (ctrl ArticleController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := articleModel.All(userID)
+	results, err := articleModel.All(orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get articles"})
 		return
@@ -86,7 +87,7 @@ func (ctrl ArticleController) All(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /article [post]
 func (ctrl ArticleController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +97,7 @@ func (ctrl ArticleController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := articleModel.One(userID, getID)
+	data, err := articleModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Article not found"})
 		return
@@ -118,7 +119,7 @@ func (ctrl ArticleController) One(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /article [post]
 func (ctrl ArticleController) Update(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -136,7 +137,7 @@ func (ctrl ArticleController) Update(c *gin.Context) {
 		return
 	}
 
-	err = articleModel.Update(userID, getID, form)
+	err = articleModel.Update(orgID, getID, form)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Article could not be updated"})
 		return
@@ -158,7 +159,7 @@ func (ctrl ArticleController) Update(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /article [post]
 func (ctrl ArticleController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,7 +169,7 @@ func (ctrl ArticleController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = articleModel.Delete(userID, getID)
+	err = articleModel.Delete(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Article could not be deleted"})
 		return
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController ...
+type AuditController struct{}
+
+var auditModel = new(models.AuditModel)
+
+// All ...
+// @BasePath /api/v1
+
+// All godoc
+// @Summary List the security events
+// @Schemes
+// @Description Lists the latest security events of the user, e.g. a refresh token used twice
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.AuditLog
+// @Router /user/audit-log [get]
+func (ctrl AuditController) All(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	results, err := auditModel.All(getUserID(c))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not get the security events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -1,21 +1,19 @@
 package controllers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
-	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
 	"github.com/Massad/gin-boilerplate/models"
 	"github.com/gin-gonic/gin"
-	jwt "github.com/golang-jwt/jwt/v4"
 )
 
 // AuthController ...
 type AuthController struct{}
 
 var authModel = new(models.AuthModel)
+var permissionModel = new(models.PermissionModel)
 
 // TokenValid ...
 func (ctl AuthController) TokenValid(c *gin.Context) {
@@ -34,8 +32,117 @@ func (ctl AuthController) TokenValid(c *gin.Context) {
 		return
 	}
 
-	//To be called from GetUserID()
+	//The user may have left the organization since the token was issued
+	role, err := organizationModel.Role(tokenAuth.OrganizationID, userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "You are not a member of this organization anymore, please switch to another one"})
+		return
+	}
+
+	//To be called from GetUserID(), getOrgID() and getRole()
 	c.Set("userID", userID)
+	c.Set("orgID", tokenAuth.OrganizationID)
+	c.Set("role", role)
+	c.Set("sessionID", tokenAuth.SessionID)
+	if tokenAuth.ClientID != "" {
+		c.Set("scopes", tokenAuth.Scopes)
+	}
+
+	sessionModel.Touch(tokenAuth.SessionID, c.ClientIP())
+}
+
+// APIKeyValid authenticates the request with the X-API-Key header instead of a token,
+// setting the same context as TokenValid with the user and organization of the key
+func (ctl AuthController) APIKeyValid(c *gin.Context) {
+	details, err := apiKeyModel.Authenticate(c.GetHeader("X-API-Key"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid API key"})
+		return
+	}
+
+	//The user who created the key may have left the organization since
+	role, err := organizationModel.Role(details.OrganizationID, details.UserID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "The user of this API key is not a member of the organization anymore"})
+		return
+	}
+
+	c.Set("userID", details.UserID)
+	c.Set("orgID", details.OrganizationID)
+	c.Set("role", role)
+	c.Set("scopes", []string(details.Scopes))
+}
+
+// Permission aborts the request when the role of the user in the active organization is not granted the permission,
+// to be called after TokenValid
+func (ctl AuthController) Permission(c *gin.Context, permission string) {
+	//The requests authenticated with an API key or OAuth token are also limited to its scopes
+	if scopes, ok := c.Get("scopes"); ok {
+		if !allowedByScopes(scopes.([]string), permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "This API key or token is not allowed this action"})
+			return
+		}
+	}
+
+	granted, err := permissionModel.Can(getRole(c), permission)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not check your permissions, please try again later"})
+		return
+	}
+
+	if !granted {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "Your role in this organization does not allow this action"})
+		return
+	}
+}
+
+// JWKS ...
+// @BasePath /api/v1
+
+// JWKS godoc
+// @Summary List the public signing keys
+// @Schemes
+// @Description The JSON Web Key Set of the access tokens: the active key and the rotated ones that still verify unexpired tokens. Empty with HS256
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (ctl AuthController) JWKS(c *gin.Context) {
+	set, err := authModel.JWKS()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "Could not get the signing keys, please try again later"})
+		return
+	}
+
+	//The verifiers fetch the set again for an unknown kid, a short cache is enough
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
+
+// delegated tells whether the request is authenticated with an API key or an OAuth token rather than the login of the user,
+// those are limited to their organization and scopes
+func delegated(c *gin.Context) bool {
+	_, ok := c.Get("scopes")
+	return ok
+}
+
+// allowedByScopes tells whether an API key or OAuth token with the scopes may use the permission,
+// one without any scope has every permission of its role except managing the API keys and OAuth clients
+func allowedByScopes(scopes []string, permission string) bool {
+	if permission == models.PermissionManageAPIKeys || permission == models.PermissionManageOAuthClients {
+		return false
+	}
+	if len(scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+
+	return false
 }
 
 //Refresh ...
@@ -59,62 +166,23 @@ func (ctl AuthController) Refresh(c *gin.Context) {
 		return
 	}
 
-	//verify the token
-	token, err := jwt.Parse(tokenForm.RefreshToken, func(token *jwt.Token) (interface{}, error) {
-		//Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(os.Getenv("REFRESH_SECRET")), nil
-	})
-	//if there is an error, the token must have expired
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
+	ts, err := authModel.Refresh(tokenForm.RefreshToken, "", sessionClient(c))
+	if errors.Is(err, models.ErrRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
 		return
 	}
-	//is token valid?
-	if _, ok := token.Claims.(jwt.Claims); !ok && !token.Valid {
+	if errors.Is(err, models.ErrInvalidRefreshToken) {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
 		return
 	}
-	//Since token is valid, get the uuid:
-	claims, ok := token.Claims.(jwt.MapClaims) //the token claims should conform to MapClaims
-	if ok && token.Valid {
-		refreshUUID, ok := claims["refresh_uuid"].(string) //convert the interface to string
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
-			return
-		}
-		userID, err := strconv.ParseInt(fmt.Sprintf("%.f", claims["user_id"]), 10, 64)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
-			return
-		}
-		//Delete the previous Refresh Token
-		deleted, delErr := authModel.DeleteAuth(refreshUUID)
-		if delErr != nil || deleted == 0 { //if any goes wrong
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
-			return
-		}
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Invalid authorization, please login again"})
+		return
+	}
 
-		//Create new pairs of refresh and access tokens
-		ts, createErr := authModel.CreateToken(userID)
-		if createErr != nil {
-			c.JSON(http.StatusForbidden, gin.H{"message": "Invalid authorization, please login again"})
-			return
-		}
-		//save the tokens metadata to redis
-		saveErr := authModel.CreateAuth(userID, ts)
-		if saveErr != nil {
-			c.JSON(http.StatusForbidden, gin.H{"message": "Invalid authorization, please login again"})
-			return
-		}
-		tokens := map[string]string{
-			"access_token":  ts.AccessToken,
-			"refresh_token": ts.RefreshToken,
-		}
-		c.JSON(http.StatusOK, tokens)
-	} else {
-		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid authorization, please login again"})
+	tokens := map[string]string{
+		"access_token":  ts.AccessToken,
+		"refresh_token": ts.RefreshToken,
 	}
+	c.JSON(http.StatusOK, tokens)
 }
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
@@ -17,6 +19,20 @@ type CustomerController struct{}
 var customerModel = new(models.CustomerModel)
 var customerForm = new(forms.CustomerForm)
 
+// customerError aborts the request with the status matching the model error
+func customerError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Customer not found"})
+	case errors.Is(err, models.ErrCustomerInUse):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrDefaultAddress):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
 //Create ...
 // @BasePath /api/v1
 
@@ -30,6 +46,7 @@ var customerForm = new(forms.CustomerForm)
 // @Success 200 {string} Helloworld
 // @Router /customer [post]
 func (ctrl CustomerController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	var form forms.CreateCustomerForm
@@ -40,13 +57,13 @@ func (ctrl CustomerController) Create(c *gin.Context) {
 		return
 	}
 
-	id, err := customerModel.Create(userID, form)
+	customer, err := customerModel.Create(orgID, userID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Customer could not be created"})
+		customerError(c, err, "Customer could not be created")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Customer created", "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Customer created", "id": customer.ID, "data": customer})
 }
 
 // All ...
@@ -62,9 +79,9 @@ func (ctrl CustomerController) Create(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /customer [post]
 func (ctrl CustomerController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := customerModel.All(userID)
+	results, err := customerModel.All(orgID, c.Query("tag"))
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get customers"})
 		return
@@ -86,7 +103,7 @@ func (ctrl CustomerController) All(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /customer [post]
 func (ctrl CustomerController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +113,7 @@ func (ctrl CustomerController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := customerModel.One(userID, getID)
+	data, err := customerModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Customer not found"})
 		return
@@ -118,7 +135,7 @@ func (ctrl CustomerController) One(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /customer [post]
 func (ctrl CustomerController) Update(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -136,9 +153,9 @@ func (ctrl CustomerController) Update(c *gin.Context) {
 		return
 	}
 
-	err = customerModel.Update(userID, getID, form)
+	err = customerModel.Update(orgID, getID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Customer could not be updated"})
+		customerError(c, err, "Customer could not be updated")
 		return
 	}
 
@@ -158,7 +175,7 @@ func (ctrl CustomerController) Update(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /customer [post]
 func (ctrl CustomerController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,9 +185,9 @@ func (ctrl CustomerController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = customerModel.Delete(userID, getID)
+	err = customerModel.Delete(orgID, getID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Customer could not be deleted"})
+		customerError(c, err, "Customer could not be deleted")
 		return
 	}
 
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailController ...
+type EmailController struct{}
+
+var emailModel = new(models.EmailModel)
+var emailForm = new(forms.EmailForm)
+
+// emailError aborts the request with the status matching the model error
+func emailError(c *gin.Context, err error, fallback string) {
+	var passwordErr *forms.PasswordError
+	var throttled *models.EmailThrottledError
+
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.Itoa(throttled.Seconds()))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrInvalidEmailToken):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case errors.As(err, &passwordErr):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// RequestVerification ...
+// @BasePath /api/v1
+
+// RequestVerification godoc
+// @Summary Send the verification email again
+// @Schemes
+// @Description Sends a new link to confirm the email, the previous one stops working. The response does not tell whether the email is registered
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param email body forms.EmailRequestForm true "Email"
+// @Success 200 {string} message
+// @Failure 429 {string} message
+// @Router /user/email/verification [post]
+func (ctrl EmailController) RequestVerification(c *gin.Context) {
+	var form forms.EmailRequestForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := emailForm.Request(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	if err := emailModel.RequestVerification(form, c.ClientIP()); err != nil {
+		emailError(c, err, "Could not send the verification email, please try again later")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered and not verified yet, a verification link was sent to it"})
+}
+
+// Verify ...
+// @BasePath /api/v1
+
+// Verify godoc
+// @Summary Verify the email
+// @Schemes
+// @Description Confirms the email with the token of the verification link
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param token body forms.EmailTokenForm true "Token"
+// @Success 200 {string} message
+// @Failure 401 {string} message
+// @Router /user/email/verify [post]
+func (ctrl EmailController) Verify(c *gin.Context) {
+	var form forms.EmailTokenForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := emailForm.Token(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	if err := emailModel.Verify(form); err != nil {
+		emailError(c, err, "Could not verify the email, please try again later")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ForgotPassword ...
+// @BasePath /api/v1
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Schemes
+// @Description Sends a link to choose a new password, valid for 1 hour. The response does not tell whether the email is registered
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param email body forms.EmailRequestForm true "Email"
+// @Success 200 {string} message
+// @Failure 429 {string} message
+// @Router /user/password/forgot [post]
+func (ctrl EmailController) ForgotPassword(c *gin.Context) {
+	var form forms.EmailRequestForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := emailForm.Request(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	if err := emailModel.ForgotPassword(form, c.ClientIP()); err != nil {
+		emailError(c, err, "Could not send the password reset email, please try again later")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered, a link to reset the password was sent to it"})
+}
+
+// ResetPassword ...
+// @BasePath /api/v1
+
+// ResetPassword godoc
+// @Summary Reset the password
+// @Schemes
+// @Description Sets a new password with the token of the password reset link
+// @Tags email
+// @Accept json
+// @Produce json
+// @Param reset body forms.ResetPasswordForm true "Reset"
+// @Success 200 {string} message
+// @Failure 401 {string} message
+// @Router /user/password/reset [post]
+func (ctrl EmailController) ResetPassword(c *gin.Context) {
+	var form forms.ResetPasswordForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := emailForm.Token(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	if err := emailModel.ResetPassword(form); err != nil {
+		emailError(c, err, "Could not reset the password, please try again later")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated, you can log in with it now"})
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryController ...
+type InventoryController struct{}
+
+var inventoryModel = new(models.InventoryModel)
+var inventoryForm = new(forms.InventoryForm)
+
+// inventoryError aborts the request with the status matching the model error
+func inventoryError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Product not found"})
+	case errors.Is(err, models.ErrInsufficientStock), errors.Is(err, models.ErrWarehouseCodeTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrWarehouseNotFound), errors.Is(err, models.ErrVariantNotFound), errors.Is(err, models.ErrNegativeReceipt):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// CreateWarehouse ...
+// @BasePath /api/v1
+
+// CreateWarehouse godoc
+// @Summary Create a warehouse
+// @Schemes
+// @Description Creates a warehouse to hold stock, its code is unique in the organization
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Warehouse
+// @Failure 409 {string} message
+// @Router /warehouse [post]
+func (ctrl InventoryController) CreateWarehouse(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	var form forms.CreateWarehouseForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := inventoryForm.CreateWarehouse(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	warehouse, err := inventoryModel.CreateWarehouse(orgID, userID, form)
+	if err != nil {
+		inventoryError(c, err, "Warehouse could not be created")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Warehouse created", "id": warehouse.ID, "data": warehouse})
+}
+
+// Warehouses ...
+// @BasePath /api/v1
+
+// Warehouses godoc
+// @Summary List the warehouses
+// @Schemes
+// @Description List the warehouses of the active organization
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Warehouse
+// @Router /warehouses [get]
+func (ctrl InventoryController) Warehouses(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	results, err := inventoryModel.Warehouses(orgID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get warehouses"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Stock ...
+// @BasePath /api/v1
+
+// Stock godoc
+// @Summary Get the stock of a product
+// @Schemes
+// @Description Returns the stock on hand, reserved by placed orders and available, in total and per warehouse and variant
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Stock
+// @Router /product/{id}/stock [get]
+func (ctrl InventoryController) Stock(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	data, err := inventoryModel.Stock(orgID, getID)
+	if err != nil {
+		inventoryError(c, err, "Could not get the product stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// Move ...
+// @BasePath /api/v1
+
+// Move godoc
+// @Summary Record a stock receipt or adjustment
+// @Schemes
+// @Description Appends a receipt or an adjustment to the stock ledger of the product, the stock reserved by orders can not be adjusted away
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Stock
+// @Failure 409 {string} message
+// @Router /product/{id}/stock [post]
+func (ctrl InventoryController) Move(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	var form forms.StockMovementForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := inventoryForm.Move(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	data, err := inventoryModel.Move(orgID, userID, getID, form)
+	if err != nil {
+		inventoryError(c, err, "Stock movement could not be recorded")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stock movement recorded", "data": data})
+}
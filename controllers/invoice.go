@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
@@ -17,54 +20,79 @@ type InvoiceController struct{}
 var invoiceModel = new(models.InvoiceModel)
 var invoiceForm = new(forms.InvoiceForm)
 
-//Create ...
+// invoiceError aborts the request with the status matching the model error
+func invoiceError(c *gin.Context, err error, notFound, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": notFound})
+	case errors.Is(err, models.ErrInvoiceLocked), errors.Is(err, models.ErrOrderNotInvoiceable), errors.Is(err, models.ErrOrderInvoiced), errors.Is(err, models.ErrInvoiceNotIssued):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// Generate ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Generate godoc
+// @Summary Generate a draft invoice from an order
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Copies the order lines, totals, tax breakdown and customer billing details into a new draft invoice
+// @Tags invoice
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /invoice [post]
-func (ctrl InvoiceController) Create(c *gin.Context) {
+// @Success 200 {object} models.Invoice
+// @Failure 409 {string} message
+// @Router /order/{id}/invoice [post]
+func (ctrl InvoiceController) Generate(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
-	var form forms.CreateInvoiceForm
+	id := c.Param("id")
 
-	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
-		message := invoiceForm.Create(validationErr)
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
 		return
 	}
 
-	id, err := invoiceModel.Create(userID, form)
+	var form forms.GenerateInvoiceForm
+
+	//The body is optional, the due date defaults to 30 days
+	if c.Request.ContentLength > 0 {
+		if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+			message := invoiceForm.Generate(validationErr)
+			c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+			return
+		}
+	}
+
+	invoice, err := invoiceModel.Generate(orgID, userID, getID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Invoice could not be created"})
+		invoiceError(c, err, "Order not found", "Invoice could not be generated")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Invoice created", "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice generated", "id": invoice.ID, "data": invoice})
 }
 
 // All ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// All godoc
+// @Summary List the invoices
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description List the invoices of the active organization
+// @Tags invoice
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /invoice [post]
+// @Success 200 {array} models.Invoice
+// @Router /invoices [get]
 func (ctrl InvoiceController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := invoiceModel.All(userID)
+	results, err := invoiceModel.All(orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get invoices"})
 		return
@@ -76,17 +104,17 @@ func (ctrl InvoiceController) All(c *gin.Context) {
 // One ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// One godoc
+// @Summary Get an invoice
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Get an invoice with its items
+// @Tags invoice
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /invoice [post]
+// @Success 200 {object} models.Invoice
+// @Router /invoice/{id} [get]
 func (ctrl InvoiceController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +124,7 @@ func (ctrl InvoiceController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := invoiceModel.One(userID, getID)
+	data, err := invoiceModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invoice not found"})
 		return
@@ -108,17 +136,17 @@ func (ctrl InvoiceController) One(c *gin.Context) {
 // Update ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Update godoc
+// @Summary Update a draft invoice
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Change the due date, billing details and notes of an invoice that is not issued yet
+// @Tags invoice
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /invoice [post]
+// @Success 200 {string} message
+// @Router /invoice/{id} [put]
 func (ctrl InvoiceController) Update(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -128,15 +156,15 @@ func (ctrl InvoiceController) Update(c *gin.Context) {
 		return
 	}
 
-	var form forms.CreateInvoiceForm
+	var form forms.UpdateInvoiceForm
 
 	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
-		message := invoiceForm.Create(validationErr)
+		message := invoiceForm.Update(validationErr)
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
 		return
 	}
 
-	err = invoiceModel.Update(userID, getID, form)
+	err = invoiceModel.Update(orgID, getID, form)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Invoice could not be updated"})
 		return
@@ -145,20 +173,119 @@ func (ctrl InvoiceController) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Invoice updated"})
 }
 
+// Issue ...
+// @BasePath /api/v1
+
+// Issue godoc
+// @Summary Issue a draft invoice
+// @Schemes
+// @Description Assigns the next sequential invoice number, the invoice can not be changed afterwards
+// @Tags invoice
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Invoice
+// @Failure 409 {string} message
+// @Router /invoice/{id}/issue [post]
+func (ctrl InvoiceController) Issue(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	data, err := invoiceModel.Issue(orgID, getID)
+	if err != nil {
+		invoiceError(c, err, "Invoice not found", "Invoice could not be issued")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice issued", "data": data})
+}
+
+// Void ...
+// @BasePath /api/v1
+
+// Void godoc
+// @Summary Void an issued invoice
+// @Schemes
+// @Description The invoice keeps its number so the numbering stays complete
+// @Tags invoice
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Invoice
+// @Failure 409 {string} message
+// @Router /invoice/{id}/void [post]
+func (ctrl InvoiceController) Void(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	data, err := invoiceModel.Void(orgID, getID)
+	if err != nil {
+		invoiceError(c, err, "Invoice not found", "Invoice could not be voided")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice voided", "data": data})
+}
+
+// PDF ...
+// @BasePath /api/v1
+
+// PDF godoc
+// @Summary Download an invoice as PDF
+// @Schemes
+// @Description Renders an issued invoice with the seller, billing details, lines, taxes and totals
+// @Tags invoice
+// @Produce application/pdf
+// @Success 200 {file} file
+// @Failure 409 {string} message
+// @Router /invoice/{id}/pdf [get]
+func (ctrl InvoiceController) PDF(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	document, invoice, err := invoiceModel.PDF(orgID, getID)
+	if err != nil {
+		invoiceError(c, err, "Invoice not found", "Invoice could not be rendered")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
 // Delete ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Delete godoc
+// @Summary Delete a draft invoice
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Issued invoices can only be voided
+// @Tags invoice
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /invoice [post]
+// @Success 200 {string} message
+// @Router /invoice/{id} [delete]
 func (ctrl InvoiceController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,7 +295,7 @@ func (ctrl InvoiceController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = invoiceModel.Delete(userID, getID)
+	err = invoiceModel.Delete(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Invoice could not be deleted"})
 		return
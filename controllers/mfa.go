@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAController ...
+type MFAController struct{}
+
+var mfaModel = new(models.MFAModel)
+var mfaForm = new(forms.MFAForm)
+
+// mfaError aborts the request with the status matching the model error
+func mfaError(c *gin.Context, err error, fallback string) {
+	var locked *models.LoginLockedError
+
+	switch {
+	case errors.As(err, &locked):
+		c.Header("Retry-After", strconv.Itoa(locked.Seconds()))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrMFAEnabled):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrMFANotEnrolled):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrInvalidMFACode), errors.Is(err, models.ErrInvalidMFAChallenge):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// mfaRequired answers a login with the password (or a provider) of a user with two-factor authentication,
+// the login is completed with the challenge and a code (MFAController.Login)
+func mfaRequired(c *gin.Context, challenge string) {
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication required", "mfa_required": true, "mfa_token": challenge})
+}
+
+// Enroll ...
+// @BasePath /api/v1
+
+// Enroll godoc
+// @Summary Enroll in two-factor authentication
+// @Schemes
+// @Description Creates the secret of the authenticator app, returned with its otpauth URI for the QR code. It is enabled once a code is verified
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.MFAEnrollment
+// @Failure 409 {string} message
+// @Router /user/mfa/enroll [post]
+func (ctrl MFAController) Enroll(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	enrollment, err := mfaModel.Enroll(getUserID(c))
+	if err != nil {
+		mfaError(c, err, "Could not enroll in two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": enrollment})
+}
+
+// Activate ...
+// @BasePath /api/v1
+
+// Activate godoc
+// @Summary Activate two-factor authentication
+// @Schemes
+// @Description Verifies a code of the enrolled secret and enables two-factor authentication. The recovery codes are only returned once
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param code body forms.MFACodeForm true "Code"
+// @Success 200 {array} string
+// @Failure 401 {string} message
+// @Router /user/mfa/activate [post]
+func (ctrl MFAController) Activate(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	var form forms.MFACodeForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := mfaForm.Verify(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	codes, err := mfaModel.Activate(getUserID(c), form)
+	if err != nil {
+		mfaError(c, err, "Could not activate two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication enabled, please keep the recovery codes somewhere safe", "recovery_codes": codes})
+}
+
+// Disable ...
+// @BasePath /api/v1
+
+// Disable godoc
+// @Summary Disable two-factor authentication
+// @Schemes
+// @Description Disables two-factor authentication with a code of the authenticator app or a recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param code body forms.MFACodeForm true "Code"
+// @Success 200 {string} message
+// @Failure 401 {string} message
+// @Router /user/mfa/disable [post]
+func (ctrl MFAController) Disable(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	var form forms.MFACodeForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := mfaForm.Verify(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	err := mfaModel.Disable(getUserID(c), form)
+	if err != nil {
+		mfaError(c, err, "Could not disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// Login ...
+// @BasePath /api/v1
+
+// Login godoc
+// @Summary Complete a login with two-factor authentication
+// @Schemes
+// @Description Completes the login with the mfa_token it returned and a code of the authenticator app or a recovery code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param login body forms.MFALoginForm true "Login"
+// @Success 200 {object} models.Token
+// @Failure 401 {string} message
+// @Failure 429 {string} message
+// @Router /user/login/mfa [post]
+func (ctrl MFAController) Login(c *gin.Context) {
+	var form forms.MFALoginForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := mfaForm.Verify(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	user, token, err := mfaModel.CompleteLogin(form, sessionClient(c))
+	if err != nil {
+		mfaError(c, err, "Invalid login details")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged in", "user": user, "token": token})
+}
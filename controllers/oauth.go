@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthController ...
+type OAuthController struct{}
+
+var oauthModel = new(models.OAuthModel)
+var oauthForm = new(forms.OAuthForm)
+
+// oauthError aborts the client registration and authorization requests with the status matching the model error
+func oauthError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidClient):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Client not found"})
+	case errors.Is(err, models.ErrScopeNotGranted):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrInvalidScope):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "The requested scopes are not allowed for this client or your role"})
+	case errors.Is(err, models.ErrInvalidRedirectURI), errors.Is(err, models.ErrRedirectURIRequired):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// CreateClient ...
+// @BasePath /api/v1
+
+// CreateClient godoc
+// @Summary Register an OAuth client
+// @Schemes
+// @Description Registers a third-party app of the active organization. The confidential clients get a secret, only returned once
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.OAuthClient
+// @Failure 403 {string} message
+// @Router /oauth/client [post]
+func (ctrl OAuthController) CreateClient(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	var form forms.CreateOAuthClientForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := oauthForm.CreateClient(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	client, err := oauthModel.CreateClient(orgID, userID, getRole(c), form)
+	if err != nil {
+		oauthError(c, err, "Client could not be registered")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Client registered", "id": client.ID, "data": client})
+}
+
+// Clients ...
+// @BasePath /api/v1
+
+// Clients godoc
+// @Summary List the OAuth clients
+// @Schemes
+// @Description List the third-party apps registered by the active organization
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.OAuthClient
+// @Router /oauth/clients [get]
+func (ctrl OAuthController) Clients(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	results, err := oauthModel.Clients(orgID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get clients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Consent ...
+// @BasePath /api/v1
+
+// Consent godoc
+// @Summary Check an authorization request
+// @Schemes
+// @Description Checks the authorization request of a client (RFC 6749 4.1.1 with PKCE) and returns what the logged in user is asked to approve on the consent screen
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.OAuthConsent
+// @Failure 406 {string} message
+// @Router /oauth/authorize [get]
+func (ctrl OAuthController) Consent(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	var form forms.AuthorizeForm
+
+	if validationErr := c.ShouldBindQuery(&form); validationErr != nil {
+		message := oauthForm.Authorize(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	consent, err := oauthModel.Consent(getRole(c), form)
+	if err != nil {
+		oauthError(c, err, "Invalid authorization request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": consent})
+}
+
+// Authorize ...
+// @BasePath /api/v1
+
+// Authorize godoc
+// @Summary Answer an authorization request
+// @Schemes
+// @Description Approves or denies the authorization request for the active organization, returns the URI to redirect the user to with the authorization code or the access_denied error
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Success 200 {string} redirect_uri
+// @Failure 406 {string} message
+// @Router /oauth/authorize [post]
+func (ctrl OAuthController) Authorize(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	var form forms.AuthorizeForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := oauthForm.Authorize(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	redirect, err := oauthModel.Authorize(orgID, userID, getRole(c), form)
+	if err != nil {
+		oauthError(c, err, "Invalid authorization request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"redirect_uri": redirect})
+}
+
+// Token ...
+// @BasePath /api/v1
+
+// Token godoc
+// @Summary Issue OAuth tokens
+// @Schemes
+// @Description The token endpoint of RFC 6749 for the authorization_code (with its PKCE verifier), client_credentials and refresh_token grants. The confidential clients authenticate with HTTP Basic or client_id and client_secret
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} models.OAuthToken
+// @Failure 400 {string} error
+// @Failure 401 {string} error
+// @Router /oauth/token [post]
+func (ctrl OAuthController) Token(c *gin.Context) {
+	//The tokens must not be cached (RFC 6749 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var form forms.OAuthTokenForm
+
+	if validationErr := c.ShouldBind(&form); validationErr != nil {
+		code, description := oauthForm.Token(validationErr)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": code, "error_description": description})
+		return
+	}
+
+	if clientID, secret, ok := c.Request.BasicAuth(); ok {
+		form.ClientID = clientID
+		form.ClientSecret = secret
+	}
+
+	token, err := oauthModel.Token(form, sessionClient(c))
+	switch {
+	case errors.Is(err, models.ErrInvalidClient):
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "error_description": "Client authentication failed"})
+	case errors.Is(err, models.ErrInvalidGrant):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_description": "The authorization code or refresh token is invalid, expired or already used"})
+	case errors.Is(err, models.ErrInvalidScope):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_description": "The requested scope exceeds the scopes of the client"})
+	case errors.Is(err, models.ErrUnauthorizedClient):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error(), "error_description": "Only the confidential clients can use this grant"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "server_error", "error_description": "Could not issue the tokens, please try again later"})
+	default:
+		c.JSON(http.StatusOK, token)
+	}
+}
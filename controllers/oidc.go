@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCController ...
+type OIDCController struct{}
+
+var oidcModel = new(models.OIDCModel)
+var oidcForm = new(forms.OIDCForm)
+
+// oidcError aborts the request with the status matching the model error
+func oidcError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrUnknownProvider):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Login provider not found"})
+	case errors.Is(err, models.ErrInvalidState), errors.Is(err, models.ErrInvalidIDToken):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrEmailNotVerified):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// Providers ...
+// @BasePath /api/v1
+
+// Providers godoc
+// @Summary List the login providers
+// @Schemes
+// @Description List the configured social and OpenID Connect login providers
+// @Tags oidc
+// @Accept json
+// @Produce json
+// @Success 200 {array} string
+// @Router /oidc/providers [get]
+func (ctrl OIDCController) Providers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"results": oidcModel.Providers()})
+}
+
+// Login ...
+// @BasePath /api/v1
+
+// Login godoc
+// @Summary Start a login with a provider
+// @Schemes
+// @Description Returns the URL of the provider to send the user to, they come back to the callback
+// @Tags oidc
+// @Accept json
+// @Produce json
+// @Success 200 {string} url
+// @Failure 404 {string} message
+// @Router /oidc/{provider}/login [get]
+func (ctrl OIDCController) Login(c *gin.Context) {
+	url, err := oidcModel.AuthURL(c.Param("provider"))
+	if err != nil {
+		oidcError(c, err, "Could not reach the login provider, please try again later")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// Callback ...
+// @BasePath /api/v1
+
+// Callback godoc
+// @Summary Finish a login with a provider
+// @Schemes
+// @Description Verifies the identity returned by the provider and logs in the user linked to it, by their verified email or as a new user
+// @Tags oidc
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Token
+// @Failure 401 {string} message
+// @Failure 403 {string} message
+// @Router /oidc/{provider}/callback [get]
+func (ctrl OIDCController) Callback(c *gin.Context) {
+	var form forms.OIDCCallbackForm
+
+	if validationErr := c.ShouldBindQuery(&form); validationErr != nil {
+		message := oidcForm.Callback(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	if form.Error != "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "The login was cancelled at the provider"})
+		return
+	}
+
+	user, token, challenge, err := oidcModel.Callback(c.Param("provider"), form, sessionClient(c))
+	if err != nil {
+		oidcError(c, err, "Could not log in with the provider, please try again later")
+		return
+	}
+
+	if challenge != "" {
+		mfaRequired(c, challenge)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged in", "user": user, "token": token})
+}
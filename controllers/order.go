@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
@@ -17,6 +19,21 @@ type OrderController struct{}
 var orderModel = new(models.OrderModel)
 var orderForm = new(forms.OrderForm)
 
+// orderError aborts the request with the status matching the model error, the validation errors of the model
+// are exposed and anything else is hidden behind fallback
+func orderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Order not found"})
+	case errors.Is(err, models.ErrOrderTransition), errors.Is(err, models.ErrInsufficientStock):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrCustomerNotFound), errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrVariantNotFound), errors.Is(err, models.ErrOrderLocked):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
 //Create ...
 // @BasePath /api/v1
 
@@ -30,6 +47,7 @@ var orderForm = new(forms.OrderForm)
 // @Success 200 {string} Helloworld
 // @Router /order [post]
 func (ctrl OrderController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	var form forms.CreateOrderForm
@@ -40,13 +58,13 @@ func (ctrl OrderController) Create(c *gin.Context) {
 		return
 	}
 
-	id, err := orderModel.Create(userID, form)
+	order, err := orderModel.Create(orgID, userID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Order could not be created"})
+		orderError(c, err, "Order could not be created")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Order created", "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Order created", "id": order.ID, "data": order})
 }
 
 // All ...
@@ -62,9 +80,9 @@ func (ctrl OrderController) Create(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /order [post]
 func (ctrl OrderController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := orderModel.All(userID)
+	results, err := orderModel.All(orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get orders"})
 		return
@@ -86,7 +104,7 @@ func (ctrl OrderController) All(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /order [post]
 func (ctrl OrderController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +114,7 @@ func (ctrl OrderController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := orderModel.One(userID, getID)
+	data, err := orderModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Order not found"})
 		return
@@ -118,7 +136,7 @@ func (ctrl OrderController) One(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /order [post]
 func (ctrl OrderController) Update(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -131,14 +149,14 @@ func (ctrl OrderController) Update(c *gin.Context) {
 	var form forms.CreateOrderForm
 
 	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
-		message := orderForm.Create(validationErr)
+		message := orderForm.Update(validationErr)
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
 		return
 	}
 
-	err = orderModel.Update(userID, getID, form)
+	err = orderModel.Update(orgID, getID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Order could not be updated"})
+		orderError(c, err, "Order could not be updated")
 		return
 	}
 
@@ -158,7 +176,7 @@ func (ctrl OrderController) Update(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /order [post]
 func (ctrl OrderController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,12 +186,87 @@ func (ctrl OrderController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = orderModel.Delete(userID, getID)
+	err = orderModel.Delete(orgID, getID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Order could not be deleted"})
+		orderError(c, err, "Order could not be deleted")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Order deleted"})
 
 }
+
+// Transition ...
+// @BasePath /api/v1
+
+// Transition godoc
+// @Summary Move an order to another status
+// @Schemes
+// @Description Only the moves allowed by the order lifecycle are accepted, every move is recorded in the order history.
+// Placing the order reserves the stock of its tracked products and cancelling or refunding it releases what was not shipped
+// @Tags order
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Order
+// @Failure 409 {string} message
+// @Router /order/{id}/transition [post]
+func (ctrl OrderController) Transition(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	var form forms.OrderTransitionForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := orderForm.Transition(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	data, err := orderModel.Transition(orgID, userID, getID, form)
+	if err != nil {
+		orderError(c, err, "Order status could not be changed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Order status changed", "data": data})
+}
+
+// History ...
+// @BasePath /api/v1
+
+// History godoc
+// @Summary List the status changes of an order
+// @Schemes
+// @Description Returns who moved the order, when, from which status to which and why
+// @Tags order
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.OrderStatusHistory
+// @Router /order/{id}/history [get]
+func (ctrl OrderController) History(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	results, err := orderModel.History(orgID, getID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Order not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationController ...
+type OrganizationController struct{}
+
+var organizationModel = new(models.OrganizationModel)
+var organizationForm = new(forms.OrganizationForm)
+var lockoutModel = new(models.LockoutModel)
+
+// delegatedResponse is returned to the API keys and OAuth tokens on the actions of the user themselves
+var delegatedResponse = gin.H{"message": "API keys and OAuth tokens are limited to their organization, please login to do this"}
+
+// organizationError aborts the request with the status matching the model error
+func organizationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invitation not found"})
+	case errors.Is(err, models.ErrUnknownMember):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Member not found"})
+	case errors.Is(err, models.ErrNotMember), errors.Is(err, models.ErrNotAdmin):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrAlreadyMember), errors.Is(err, models.ErrAlreadyInvited):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// Create ...
+// @BasePath /api/v1
+
+// Create godoc
+// @Summary Create an organization
+// @Schemes
+// @Description Creates an organization owned by the logged in user, switch to it to work with its data
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Organization
+// @Router /organization [post]
+func (ctrl OrganizationController) Create(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	userID := getUserID(c)
+
+	var form forms.CreateOrganizationForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := organizationForm.Create(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	organization, err := organizationModel.Create(userID, form)
+	if err != nil {
+		organizationError(c, err, "Organization could not be created")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization created", "id": organization.ID, "data": organization})
+}
+
+// All ...
+// @BasePath /api/v1
+
+// All godoc
+// @Summary List the organizations
+// @Schemes
+// @Description List the organizations the logged in user is a member of, with their role and the active one
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Organization
+// @Router /organizations [get]
+func (ctrl OrganizationController) All(c *gin.Context) {
+	userID := getUserID(c)
+
+	results, err := organizationModel.All(userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get organizations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results, "active": getOrgID(c)})
+}
+
+// Members ...
+// @BasePath /api/v1
+
+// Members godoc
+// @Summary List the members of the active organization
+// @Schemes
+// @Description List the members of the active organization with their role
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Member
+// @Router /organization/members [get]
+func (ctrl OrganizationController) Members(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	results, err := organizationModel.Members(orgID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Invite ...
+// @BasePath /api/v1
+
+// Invite godoc
+// @Summary Invite a member to the active organization
+// @Schemes
+// @Description Invites an email to join the active organization, only its owners and admins can invite
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Invitation
+// @Failure 403 {string} message
+// @Failure 409 {string} message
+// @Router /organization/invitations [post]
+func (ctrl OrganizationController) Invite(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	var form forms.InviteMemberForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := organizationForm.Invite(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	invitation, err := organizationModel.Invite(orgID, userID, form)
+	if err != nil {
+		organizationError(c, err, "Invitation could not be sent")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation sent", "id": invitation.ID, "data": invitation})
+}
+
+// Unlock ...
+// @BasePath /api/v1
+
+// Unlock godoc
+// @Summary Unlock the login of a member
+// @Schemes
+// @Description Clears the failed logins of a member of the active organization so they can log in again right away, only its owners and admins can unlock
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {string} message
+// @Failure 404 {string} message
+// @Router /organization/members/{id}/unlock [post]
+func (ctrl OrganizationController) Unlock(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	err = lockoutModel.Unlock(orgID, userID, getID, sessionClient(c))
+	if err != nil {
+		organizationError(c, err, "Member could not be unlocked")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member unlocked"})
+}
+
+// Invitations ...
+// @BasePath /api/v1
+
+// Invitations godoc
+// @Summary List the invitations of the user
+// @Schemes
+// @Description List the pending invitations sent to the email of the logged in user
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Invitation
+// @Router /invitations [get]
+func (ctrl OrganizationController) Invitations(c *gin.Context) {
+	userID := getUserID(c)
+
+	results, err := organizationModel.Invitations(userID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Accept ...
+// @BasePath /api/v1
+
+// Accept godoc
+// @Summary Accept an invitation
+// @Schemes
+// @Description Joins the organization of an invitation sent to the email of the logged in user
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Organization
+// @Failure 404 {string} message
+// @Router /invitation/{id}/accept [post]
+func (ctrl OrganizationController) Accept(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	organization, err := organizationModel.Accept(userID, getID)
+	if err != nil {
+		organizationError(c, err, "Invitation could not be accepted")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted", "data": organization})
+}
+
+// Switch ...
+// @BasePath /api/v1
+
+// Switch godoc
+// @Summary Switch the active organization
+// @Schemes
+// @Description Returns new tokens carrying the organization as the active one, the data of the other organizations is not reachable with them
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Token
+// @Failure 403 {string} message
+// @Router /organization/{id}/switch [post]
+func (ctrl OrganizationController) Switch(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	token, err := organizationModel.Switch(userID, getID, sessionClient(c))
+	if err != nil {
+		organizationError(c, err, "Could not switch the organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization switched", "token": token})
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentController ...
+type PaymentController struct{}
+
+var paymentModel = new(models.PaymentModel)
+var paymentForm = new(forms.PaymentForm)
+
+// Create ...
+// @BasePath /api/v1
+
+// Create godoc
+// @Summary Record a payment or a refund against an invoice
+// @Schemes
+// @Description Partial payments are allowed, overpayments are rejected and a negative amount records a refund. The invoice becomes paid when its balance reaches zero
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Payment
+// @Failure 409 {string} message
+// @Router /invoice/{id}/payments [post]
+func (ctrl PaymentController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	var form forms.CreatePaymentForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := paymentForm.Create(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	payment, err := paymentModel.Create(orgID, userID, getID, form)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invoice not found"})
+		case errors.Is(err, models.ErrInvoiceNotPayable), errors.Is(err, models.ErrOverpayment), errors.Is(err, models.ErrRefundExceedsPaid):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Payment could not be recorded"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment recorded", "id": payment.ID, "data": payment})
+}
+
+// All ...
+// @BasePath /api/v1
+
+// All godoc
+// @Summary List the payments of an invoice
+// @Schemes
+// @Description The ledger of the invoice in the order it was recorded, refunds have a negative amount
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Payment
+// @Router /invoice/{id}/payments [get]
+func (ctrl PaymentController) All(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	results, err := paymentModel.All(orgID, getID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invoice not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Balance ...
+// @BasePath /api/v1
+
+// Balance godoc
+// @Summary Get the balance of an invoice
+// @Schemes
+// @Description The invoice total, the amounts paid and refunded and the remaining balance computed from the payments
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Balance
+// @Router /invoice/{id}/balance [get]
+func (ctrl PaymentController) Balance(c *gin.Context) {
+	orgID := getOrgID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	data, err := paymentModel.Balance(orgID, getID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invoice not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
@@ -17,6 +19,18 @@ type ProductController struct{}
 var productModel = new(models.ProductModel)
 var productForm = new(forms.ProductForm)
 
+// productError aborts the request with the status matching the model error
+func productError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Product not found"})
+	case errors.Is(err, models.ErrSKUTaken), errors.Is(err, models.ErrProductInUse):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
 //Create ...
 // @BasePath /api/v1
 
@@ -30,6 +44,7 @@ var productForm = new(forms.ProductForm)
 // @Success 200 {string} Helloworld
 // @Router /product [post]
 func (ctrl ProductController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	var form forms.CreateProductForm
@@ -40,13 +55,13 @@ func (ctrl ProductController) Create(c *gin.Context) {
 		return
 	}
 
-	id, err := productModel.Create(userID, form)
+	product, err := productModel.Create(orgID, userID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Product could not be created"})
+		productError(c, err, "Product could not be created")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Product created", "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Product created", "id": product.ID, "data": product})
 }
 
 // All ...
@@ -62,9 +77,9 @@ func (ctrl ProductController) Create(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /product [post]
 func (ctrl ProductController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := productModel.All(userID)
+	results, err := productModel.All(orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get products"})
 		return
@@ -86,7 +101,7 @@ func (ctrl ProductController) All(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /product [post]
 func (ctrl ProductController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +111,7 @@ func (ctrl ProductController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := productModel.One(userID, getID)
+	data, err := productModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Product not found"})
 		return
@@ -118,6 +133,7 @@ func (ctrl ProductController) One(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /product [post]
 func (ctrl ProductController) Update(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	id := c.Param("id")
@@ -131,14 +147,14 @@ func (ctrl ProductController) Update(c *gin.Context) {
 	var form forms.CreateProductForm
 
 	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
-		message := productForm.Create(validationErr)
+		message := productForm.Update(validationErr)
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
 		return
 	}
 
-	err = productModel.Update(userID, getID, form)
+	err = productModel.Update(orgID, userID, getID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Product could not be updated"})
+		productError(c, err, "Product could not be updated")
 		return
 	}
 
@@ -158,7 +174,7 @@ func (ctrl ProductController) Update(c *gin.Context) {
 // @Success 200 {string} Helloworld
 // @Router /product [post]
 func (ctrl ProductController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,9 +184,9 @@ func (ctrl ProductController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = productModel.Delete(userID, getID)
+	err = productModel.Delete(orgID, getID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Product could not be deleted"})
+		productError(c, err, "Product could not be deleted")
 		return
 	}
 
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/models"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionController ...
+type SessionController struct{}
+
+var sessionModel = new(models.SessionModel)
+
+// sessionClient returns the device of the request, to start a session on
+func sessionClient(c *gin.Context) models.SessionClient {
+	return models.SessionClient{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// All ...
+// @BasePath /api/v1
+
+// All godoc
+// @Summary List the sessions
+// @Schemes
+// @Description Lists the active sessions of the user with their device, the one of the request is marked as current
+// @Tags session
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Session
+// @Router /user/sessions [get]
+func (ctrl SessionController) All(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	results, err := sessionModel.All(getUserID(c), c.GetString("sessionID"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not get the sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// Revoke ...
+// @BasePath /api/v1
+
+// Revoke godoc
+// @Summary Revoke a session
+// @Schemes
+// @Description Logs out the device of the session, its access and refresh tokens stop working at once
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {string} message
+// @Failure 404 {string} message
+// @Router /user/sessions/{id} [delete]
+func (ctrl SessionController) Revoke(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	err := sessionModel.Revoke(getUserID(c), c.Param("id"))
+	if errors.Is(err, models.ErrSessionNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not revoke the session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeAll ...
+// @BasePath /api/v1
+
+// RevokeAll godoc
+// @Summary Log out everywhere
+// @Schemes
+// @Description Revokes every session of the user, including the one of the request
+// @Tags session
+// @Accept json
+// @Produce json
+// @Success 200 {string} message
+// @Router /user/sessions [delete]
+func (ctrl SessionController) RevokeAll(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	err := sessionModel.RevokeAll(getUserID(c))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not revoke the sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere"})
+}
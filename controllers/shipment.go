@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"database/sql"
+	"errors"
 	"strconv"
 
 	"github.com/Massad/gin-boilerplate/forms"
@@ -17,19 +19,35 @@ type ShipmentController struct{}
 var shipmentModel = new(models.ShipmentModel)
 var shipmentForm = new(forms.ShipmentForm)
 
-//Create ...
+// shipmentError aborts the request with the status matching the model error
+func shipmentError(c *gin.Context, err error, notFound, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": notFound})
+	case errors.Is(err, models.ErrOrderNotShippable), errors.Is(err, models.ErrShipmentLocked), errors.Is(err, models.ErrOverShipment), errors.Is(err, models.ErrNothingToShip):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrOrderItemNotFound):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": fallback})
+	}
+}
+
+// Create ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Create godoc
+// @Summary Ship an order
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Creates a pending shipment of a placed or paid order with its carrier, destination, parcels and the order items it carries (all the remaining ones by default)
+// @Tags shipment
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
+// @Success 200 {object} models.Shipment
+// @Failure 409 {string} message
 // @Router /shipment [post]
 func (ctrl ShipmentController) Create(c *gin.Context) {
+	orgID := getOrgID(c)
 	userID := getUserID(c)
 
 	var form forms.CreateShipmentForm
@@ -40,31 +58,31 @@ func (ctrl ShipmentController) Create(c *gin.Context) {
 		return
 	}
 
-	id, err := shipmentModel.Create(userID, form)
+	shipment, err := shipmentModel.Create(orgID, userID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Shipment could not be created"})
+		shipmentError(c, err, "Order not found", "Shipment could not be created")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Shipment created", "id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Shipment created", "id": shipment.ID, "data": shipment})
 }
 
 // All ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// All godoc
+// @Summary List the shipments
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description List the shipments of the active organization
+// @Tags shipment
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /shipment [post]
+// @Success 200 {array} models.Shipment
+// @Router /shipments [get]
 func (ctrl ShipmentController) All(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
-	results, err := shipmentModel.All(userID)
+	results, err := shipmentModel.All(orgID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Could not get shipments"})
 		return
@@ -76,17 +94,17 @@ func (ctrl ShipmentController) All(c *gin.Context) {
 // One ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// One godoc
+// @Summary Get a shipment
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Get a shipment with its parcels and tracking timeline
+// @Tags shipment
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /shipment [post]
+// @Success 200 {object} models.Shipment
+// @Router /shipment/{id} [get]
 func (ctrl ShipmentController) One(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -96,7 +114,7 @@ func (ctrl ShipmentController) One(c *gin.Context) {
 		return
 	}
 
-	data, err := shipmentModel.One(userID, getID)
+	data, err := shipmentModel.One(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Shipment not found"})
 		return
@@ -108,17 +126,18 @@ func (ctrl ShipmentController) One(c *gin.Context) {
 // Update ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Update godoc
+// @Summary Update a pending shipment
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Change the carrier, destination, parcels and items of a shipment that did not leave yet
+// @Tags shipment
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /shipment [post]
+// @Success 200 {string} message
+// @Failure 409 {string} message
+// @Router /shipment/{id} [put]
 func (ctrl ShipmentController) Update(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -128,37 +147,78 @@ func (ctrl ShipmentController) Update(c *gin.Context) {
 		return
 	}
 
-	var form forms.CreateShipmentForm
+	var form forms.UpdateShipmentForm
 
 	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
-		message := shipmentForm.Create(validationErr)
+		message := shipmentForm.Update(validationErr)
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
 		return
 	}
 
-	err = shipmentModel.Update(userID, getID, form)
+	err = shipmentModel.Update(orgID, getID, form)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Shipment could not be updated"})
+		shipmentError(c, err, "Shipment not found", "Shipment could not be updated")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Shipment updated"})
 }
 
+// Event ...
+// @BasePath /api/v1
+
+// Event godoc
+// @Summary Record a tracking event
+// @Schemes
+// @Description Appends a carrier status update to the shipment timeline, the order is fulfilled once all its lines are shipped
+// @Tags shipment
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Shipment
+// @Router /shipment/{id}/events [post]
+func (ctrl ShipmentController) Event(c *gin.Context) {
+	orgID := getOrgID(c)
+	userID := getUserID(c)
+
+	id := c.Param("id")
+
+	getID, err := strconv.ParseInt(id, 10, 64)
+	if getID == 0 || err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"Message": "Invalid parameter"})
+		return
+	}
+
+	var form forms.ShipmentEventForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := shipmentForm.Event(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	data, err := shipmentModel.AddEvent(orgID, userID, getID, form)
+	if err != nil {
+		shipmentError(c, err, "Shipment not found", "Event could not be recorded")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event recorded", "data": data})
+}
+
 // Delete ...
 // @BasePath /api/v1
 
-// PingExample godoc
-// @Summary ping example
+// Delete godoc
+// @Summary Delete a pending shipment
 // @Schemes
-// @Description do ping
-// @Tags example
+// @Description Shipments with tracking events other than pending can not be deleted
+// @Tags shipment
 // @Accept json
 // @Produce json
-// @Success 200 {string} Helloworld
-// @Router /shipment [post]
+// @Success 200 {string} message
+// @Router /shipment/{id} [delete]
 func (ctrl ShipmentController) Delete(c *gin.Context) {
-	userID := getUserID(c)
+	orgID := getOrgID(c)
 
 	id := c.Param("id")
 
@@ -168,7 +228,7 @@ func (ctrl ShipmentController) Delete(c *gin.Context) {
 		return
 	}
 
-	err = shipmentModel.Delete(userID, getID)
+	err = shipmentModel.Delete(orgID, getID)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"Message": "Shipment could not be deleted"})
 		return
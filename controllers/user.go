@@ -1,6 +1,9 @@
 package controllers
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/Massad/gin-boilerplate/forms"
 	"github.com/Massad/gin-boilerplate/models"
 
@@ -21,6 +24,16 @@ func getUserID(c *gin.Context) (userID int64) {
 	return c.MustGet("userID").(int64)
 }
 
+// getOrgID returns the active organization of the logged in user, every query is scoped to it
+func getOrgID(c *gin.Context) (orgID int64) {
+	return c.MustGet("orgID").(int64)
+}
+
+// getRole returns the role of the logged in user in the active organization
+func getRole(c *gin.Context) (role string) {
+	return c.MustGet("role").(string)
+}
+
 //Login ...
 // @BasePath /api/v1
 
@@ -42,12 +55,27 @@ func (ctrl UserController) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := userModel.Login(loginForm)
+	user, token, challenge, err := userModel.Login(loginForm, sessionClient(c))
+	var locked *models.LoginLockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(locked.Seconds()))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+		return
+	}
+	if errors.Is(err, models.ErrEmailUnverified) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Invalid login details"})
 		return
 	}
 
+	if challenge != "" {
+		mfaRequired(c, challenge)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully logged in", "user": user, "token": token})
 }
 
@@ -81,6 +109,54 @@ func (ctrl UserController) Register(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully registered", "user": user})
 }
 
+//ChangePassword ...
+// @BasePath /api/v1
+
+// ChangePassword godoc
+// @Summary Change the password
+// @Schemes
+// @Description Sets a new password following the password policy once the current one is checked, the other sessions of the user are ended
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param password body forms.ChangePasswordForm true "Password"
+// @Success 200 {string} message
+// @Failure 401 {string} message
+// @Router /user/password [put]
+func (ctrl UserController) ChangePassword(c *gin.Context) {
+	if delegated(c) {
+		c.AbortWithStatusJSON(http.StatusForbidden, delegatedResponse)
+		return
+	}
+
+	var form forms.ChangePasswordForm
+
+	if validationErr := c.ShouldBindJSON(&form); validationErr != nil {
+		message := userForm.ChangePassword(validationErr)
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": message})
+		return
+	}
+
+	err := userModel.ChangePassword(getUserID(c), c.GetString("sessionID"), form, sessionClient(c))
+
+	var locked *models.LoginLockedError
+	var passwordErr *forms.PasswordError
+
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"message": "Password updated, the other sessions were logged out"})
+	case errors.As(err, &locked):
+		c.Header("Retry-After", strconv.Itoa(locked.Seconds()))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"message": err.Error()})
+	case errors.Is(err, models.ErrWrongPassword):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+	case errors.As(err, &passwordErr):
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": err.Error()})
+	default:
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, gin.H{"message": "Could not change the password, please try again later"})
+	}
+}
+
 //Logout ...
 // @BasePath /api/v1
 
@@ -101,8 +177,9 @@ func (ctrl UserController) Logout(c *gin.Context) {
 		return
 	}
 
-	deleted, delErr := authModel.DeleteAuth(au.AccessUUID)
-	if delErr != nil || deleted == 0 { //if any goes wrong
+	//Both tokens of the session stop working
+	delErr := sessionModel.Revoke(au.UserID, au.SessionID)
+	if delErr != nil { //if any goes wrong
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "Invalid request"})
 		return
 	}
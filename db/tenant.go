@@ -0,0 +1,48 @@
+package db
+
+import (
+	"strconv"
+
+	"github.com/go-gorp/gorp"
+)
+
+// TenantRole is the role the tenant transactions run as.
+// It is subject to the row level security policies, unlike the superuser the application connects with
+const TenantRole = "app_tenant"
+
+// BeginTenant starts a transaction that only sees and changes the rows of the organization:
+// the organization is set as app.organization_id for the row level security policies until the transaction ends
+func BeginTenant(orgID int64) (tx *gorp.Transaction, err error) {
+	tx, err = db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("SELECT set_config('app.organization_id', $1, true)", strconv.FormatInt(orgID, 10))
+	if err == nil {
+		_, err = tx.Exec("SET LOCAL ROLE " + TenantRole)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// WithTenant runs fn in a transaction of the organization (see BeginTenant),
+// committed when fn succeeds and rolled back otherwise
+func WithTenant(orgID int64, fn func(tx gorp.SqlExecutor) error) (err error) {
+	tx, err := BeginTenant(orgID)
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,62 @@
+package forms
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// APIKeyForm ...
+type APIKeyForm struct{}
+
+// CreateAPIKeyForm ...
+// The key acts as the user creating it, limited to the Scopes (permissions such as invoice:read) when any is set.
+// ExpiresAt is an optional unix timestamp
+type CreateAPIKeyForm struct {
+	Name      string   `form:"name" json:"name" binding:"required,min=3,max=100"`
+	Scopes    []string `form:"scopes" json:"scopes" binding:"omitempty,max=50,dive,required,max=50"`
+	ExpiresAt int64    `form:"expires_at" json:"expires_at" binding:"omitempty,min=1"`
+}
+
+// Name ...
+func (f APIKeyForm) Name(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the API key name"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Name should be between 3 to 100 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Create ...
+func (f APIKeyForm) Create(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch {
+			case err.Field() == "Name":
+				return f.Name(err.Tag())
+			case strings.HasPrefix(err.StructNamespace(), "CreateAPIKeyForm.Scopes"):
+				return "Scopes should be up to 50 permissions such as invoice:read"
+			case err.Field() == "ExpiresAt":
+				return "Please enter a valid expiry"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
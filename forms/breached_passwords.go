@@ -0,0 +1,809 @@
+package forms
+
+// breachedPasswords are the SHA-1 hashes of the most common passwords of the public breach lists,
+// by their prefix of 5 hexadecimal characters as the k-anonymity ranges of the Pwned Passwords API.
+// Kept sorted, a new password is added with its hash split after the fifth character
+var breachedPasswords = map[string][]string{
+	"00634": {"5B12AD566BF7891BE05CEF5909DF928CBCD"},
+	"00683": {"9D264A38B7F58E5C8130447528BF4B7AEE1"},
+	"011C9": {"45F30CE2CBAFC452F39840F025693339C42"},
+	"014A5": {"F52613B4742A930F7F953EE9F59BDD19769"},
+	"018F4": {"D7F06CB8626E1756452581373E05AE41C56"},
+	"019DB": {"0BFD5F85951CB46E4452E9642858C004155"},
+	"01B30": {"7ACBA4F54F55AAFC33BB06BBBF6CA803E9A"},
+	"02726": {"D40F378E716981C4321D60BA3A325ED6A4C"},
+	"02E0A": {"999C50B1F88DF7A8F5A04E1B76B35EA6A88"},
+	"032AE": {"6FB38DBD72A84C55F56B498F5CB480D51FD"},
+	"03785": {"D4E638CD09CEA620FD0939BF06825BE88DF"},
+	"03FDF": {"1323C8D4770C90576CE2A1860D476DED8AB"},
+	"043A5": {"58250409758B64F73D07D7F06B3DF654BC0"},
+	"04497": {"3F664367E41D082942BAFEA7C346B770196"},
+	"04E6F": {"3BCA0D940B47B477D89CC9D3E92D03F22DD"},
+	"05152": {"2D0C46404D8BA5B692A10A37B99B8186360"},
+	"05962": {"04590703C7521DB519D45EF6DF0443C0F00"},
+	"05B53": {"0AD0FB56286FE051D5F8BE5B8453F1CD93F"},
+	"05FE7": {"461C607C33229772D402505601016A7D0EA"},
+	"06894": {"2C83F0E6994D046F7EC01B8F42BA8F317A7"},
+	"06FA9": {"05D7F2AACED6DC72E9511C71A2A51E8AEAD"},
+	"08808": {"065106E0F48E0D8EFBD4C492C633B4D69E8"},
+	"088E4": {"A2E6F0C20048CD3E53C639C7092BFFB8524"},
+	"08B31": {"4F0E1E2C41EC92C3735910658E5A82C6BA7"},
+	"08BC5": {"BEDA7A9157EF65F8D90A511C77C8BEDEFA4"},
+	"09405": {"1FD430D8A65B12D604B066FB5858ACA6FED"},
+	"09639": {"92090AAC2D595B32D34E8A5FCAB9FAE3151"},
+	"09F5E": {"DEB4F5B2A4E4364F6B654682C6758A3FA16"},
+	"0A66E": {"107BB05FD282DA95EF7155E7DD65E927894"},
+	"0AB09": {"B420C3F4F686E1F6503C93D3111D2038689"},
+	"0ACC7": {"FADBC8E372AA5774CE7D593474E2E61F159"},
+	"0AE9E": {"4DEBA26021986FFD99636DA6601F6393631"},
+	"0B12F": {"C56D3B2C3F3D153092E951BE67E0B2801A5"},
+	"0B32E": {"65D12D56178B55881E6F610974E37A6BF1B"},
+	"0BCD9": {"AF79F2D32E856A4EE6B99AAE59C185AF4C3"},
+	"0C62C": {"BDB682C3D53B4ED809EC32286C5C21691D5"},
+	"0C6D0": {"182595FB16D6B28FF773D569F13E6F1D4E8"},
+	"0CE79": {"11E6479995D6C346D6F03EB723B5135309E"},
+	"0E818": {"BFA0679DF304036382AAA7667DF92CBE30E"},
+	"0ECFB": {"C3894C7B8E374232CADC0AFA67162A600BE"},
+	"0F0D9": {"59BCA569BF2B0A8BFF3E2F1E88920EE7C5F"},
+	"0F125": {"41AFCCE175FB34BB05A79C95B76E765488B"},
+	"0F7D0": {"D088B6EA936FB25B477722D734706FE8B40"},
+	"0FA13": {"E9C53B81B1C4FD304FFFBFBB65A43E40DCF"},
+	"0FECA": {"720E2C29DAFB2C900713BA560E03B758711"},
+	"103FE": {"BCA8282301C88D7014BF9446121AD7F52C3"},
+	"104E0": {"3314A82F3FBC0CE1C681CFDFA2D0542E492"},
+	"10A07": {"CDB61A9A8B27B7104CF5EC97EB5FA5B4D20"},
+	"11273": {"D57B954F7B4A41CEE3F98C2F90BC80D2F59"},
+	"11536": {"F0B9652C4182C1856695E72B9D4153CC876"},
+	"11DBF": {"66D28B6E3B7508F9732611E5E2634AE4BE6"},
+	"12E92": {"93EC6B30C7FA8A0926AF42807E929C1684F"},
+	"13145": {"D1889F70AE1D295BC0E161BA8A74347F2D6"},
+	"1385B": {"EAE6F21020AA38D8A7609588EADCC5A3ECA"},
+	"14116": {"78A0B9E25EE2F7C8B2F7AC92B6A74B3F9C5"},
+	"1496A": {"A696D9D35AA2C23B0F1EF3020DF7F26F869"},
+	"150FF": {"9F168A4A60C241D3FE830D44B22E66CA0E8"},
+	"154B9": {"6C9BCA350E96223A850D9E862A6B3BF2641"},
+	"1645E": {"E78DE0F7C73001E1A8ED1FACC25A72B6796"},
+	"166AD": {"F7CB43FC4D37EE98226D117B953BCF79516"},
+	"16B23": {"C500D54837F13213853D0ABD7783D4F9122"},
+	"16F60": {"4FC68A53995F8587F74BFBF030C823A08BB"},
+	"175A8": {"F786BF44A71B947EBEC439AD05D1C06E816"},
+	"1786E": {"3BA91DC294B3C552A36A2B735E9FEA3C1B3"},
+	"17B9E": {"1C64588C7FA6419B4D29DC1F4426279BA01"},
+	"18A98": {"C35F49808B45EDADC75FB1B25EBFD4037D6"},
+	"18AD1": {"0FD4A67F21FC07B1AA5046B410F6B2BEDF1"},
+	"18C28": {"604DD31094A8D69DAE60F1BCD347F1AFC5A"},
+	"19485": {"E369C691FA8ECE1FABC8A6CEABFB5666B79"},
+	"197DC": {"3E8B66E51EE073B6EE7B59E0EB9254B4CE2"},
+	"1999E": {"4893F732BA38B948DBE8D34ED48CD54F058"},
+	"19B58": {"543C85B97C5498EDFD89C11C3AA8CB5FE51"},
+	"1AA25": {"EAD3880825480B6C0197552D90EB5D48D23"},
+	"1ABD2": {"C47DC248F9136D6E48862C75BAC09D1B05D"},
+	"1B2D4": {"3E95F16DF6039748099CCABA49766F4FF6D"},
+	"1C1B9": {"E266B93BDC5113891F54269D2D966E5D81B"},
+	"1C1DB": {"A070798A45716CF9ECA48189FF789CD189B"},
+	"1C29C": {"F0CEB89AFCE131E27B76C18AF1E9CF7F5E3"},
+	"1C60D": {"3B6CDE0D44D9B0B0BD832109AEC8C7CC9A3"},
+	"1C905": {"9170910835368500990479A5CF828444D34"},
+	"1C9E4": {"D0D9B5045F69AB72E9FA07AC5AB0B497260"},
+	"1CB5B": {"D5A9E45420321F44C72DA5D90D7F0432FFB"},
+	"1CE14": {"16347075B6070A35CE5E9D26B61D91EA6C3"},
+	"1D572": {"ACBFA68C7C6E541C7B840D6B622E5C0DC91"},
+	"1D840": {"84AB9CF35E19C62DCC344A965839862780B"},
+	"1DA84": {"02449899EC1BA9C34C095DBB79D0585DCD7"},
+	"1DC43": {"5CCBF09FCEE707F7AF0307D806E43958D49"},
+	"1E41C": {"981637834CAEC149B4D33F7F8566076DDFA"},
+	"1EE77": {"60A3190C95641442F2BE0EF7774E139FB1F"},
+	"1EF41": {"AF4175FE164BF14A260FDF226218961C106"},
+	"1F016": {"0076C9F42A157F0A8F0DCC68E02FF69045B"},
+	"1F3C5": {"3AE14626035383B39C207564D32D083E8FD"},
+	"1F552": {"3A8F535289B3401B29958D01B2966ED61D2"},
+	"1F82C": {"942BEFDA29B6ED487A51DA199F78FCE7F05"},
+	"1F8AC": {"10F23C5B5BC1167BDA84B833E5C057A77D2"},
+	"1FC85": {"4110E5532480000542834F453DE31936C2F"},
+	"1FD1B": {"4516473C36C8FB30BBF7C4490FC20419A10"},
+	"1FD65": {"5F2CFD95956EF97A04F73F5CFF2CF5F679E"},
+	"1FFF8": {"C7BE7829FB657F9CDF5D55334999C9DD6A3"},
+	"20052": {"A88869FB11E6CCE237456721D47B082C778"},
+	"206F8": {"6E64F0373A776BFEFD7DD397D4A84D25C9B"},
+	"20BEE": {"D61F5D64368B9ABA66E91A1D2A090A0D4AE"},
+	"20C19": {"4BD04A459A3344E6ACA793DC8768419860B"},
+	"20D75": {"FE135FC3ABC15AEE2F6E4657C3107899D6A"},
+	"20EAB": {"E5D64B0E216796E834F52D61FD0B70332FC"},
+	"20F9A": {"9009EB90DFD925B0BF312726C1C921FEFF1"},
+	"21597": {"A470BA16BD685B88342113D558E43F23811"},
+	"21BD1": {"2DC183F740EE76F27B78EB39C8AD972A757"},
+	"22942": {"B7C5CDF7813BA3C1EA82FF3A2B406486271"},
+	"2394E": {"EAC9FC3DB56189A894E221220B6089E78D3"},
+	"23B36": {"EA4F70670AE377A591FDC03D36A9BEBB481"},
+	"23F29": {"16E01209D6282F226BE9677AFFAEC44A8D6"},
+	"2475F": {"CB006E003DC09EA816345FAA8EF00B58654"},
+	"24773": {"1C75F3B277594AFE05F8B1D0EE049DA0B08"},
+	"24851": {"0136410798C784BA702DF249756AD286BE4"},
+	"250E7": {"7F12A5AB6972A0895D290C4792F0A326EA8"},
+	"2539D": {"3DF1FCFA43CD1D5F5D55901F6718A10C595"},
+	"25821": {"409CA02C93B79222114DB29BA3362B44FFB"},
+	"25846": {"5759831222D475216E3266E71E3567310DD"},
+	"25C2C": {"9AFDD83B8D34234AA2881CC341C09689AAA"},
+	"263D0": {"0820F9F5E0ACC0274DA747E0A9B6868145E"},
+	"2664A": {"C13C6A941CB7C07411216073C9F3ED30715"},
+	"266F8": {"3D202FA3DA4A075CEA751B4B8D6A30DA1A8"},
+	"269A0": {"3F47F0550E98664C4A542EA78A23B305A82"},
+	"26F3C": {"D230E935F8BEF3596727F75448CB446120B"},
+	"27020": {"B8711923FEFEC15B78C971363E652B101C3"},
+	"271A7": {"7093BF07CDB81C0E82CE12C41DFA0A4D6AB"},
+	"273A0": {"C7BD3C679BA9A6F5D99078E36E85D02B952"},
+	"27599": {"2E8AC56CB212E77F5932539AC21282B31CF"},
+	"275E5": {"D5F064B3DB5F71FF7A2C2B5116CF0C902D3"},
+	"27606": {"66E055262E99A57D0C1DA9D4098C0D24659"},
+	"27613": {"A753857AF6750644D260DE1C6225B7CF1AD"},
+	"27E72": {"DBA56CBC8AD7DC2FD00F42B2D369C44A02E"},
+	"286B9": {"B7B50AB89E3397B4DF540021B531F457F7F"},
+	"2891B": {"ACEEEF1652EE698294DA0E71BA78A2A4064"},
+	"2A12B": {"9FD31DD6E73EAA345B8F20BE029CE1CA60E"},
+	"2B43F": {"B8B7A234825D50DD49CE7892D78A59DA8F3"},
+	"2BCF5": {"8D3BC51B848AD1199F9AEB7B332F33BAB2D"},
+	"2C490": {"B8E68B92E79CE344C25F3D87FC297D12346"},
+	"2D27B": {"62C597EC858F6E7B54E7E58525E6A95E6D8"},
+	"2D3B2": {"AE69A50D2C9C76AD4E6A67C7707909D0797"},
+	"2DBC2": {"FD2358E1EA1B7A6BC08EA647B9A337AC92D"},
+	"2E340": {"DBAFFF22E20EF94EA9A5FDE55D8C47048C0"},
+	"2E8AA": {"918660411855C6D44D5BB2DA677AA033255"},
+	"2EA62": {"01A068C5FA0EEA5D81A3863321A87F8D533"},
+	"2EB1F": {"74718222B9CAB10E7D8B0120535F7A7AD72"},
+	"2F27C": {"5970E47C4FFD0867088F6BEC0F872991C65"},
+	"2FB5E": {"13419FC89246865E7A324F476EC624E8740"},
+	"304C8": {"EA5FB0A31CFB3B139FA66E21FD6A0433F34"},
+	"304E4": {"98AF6A9C2D173DA12A9EFCCFE52845BDFBA"},
+	"31017": {"A722665E4AFCE586950F42944A6D331DABF"},
+	"3167C": {"F76B6E83817E13B1A49B5D3312C902D0256"},
+	"3199E": {"A056253916C41D65C6FD39B52E5F239873C"},
+	"320BC": {"A71FC381A4A025636043CA86E734E31CF8B"},
+	"32715": {"6AB287C6AA52C8670E13163FC1BF660ADD4"},
+	"32B14": {"E649DDEB198F5E510A01A31C811BDBDD46D"},
+	"32CA9": {"FC1A0F5B6330E3F4C8C1BBECDE9BEDB9573"},
+	"334F2": {"CE84CCC5159347B5FE8582E9B23C1986A8F"},
+	"3366F": {"2F39460751CE537145A436AA86218AE35EE"},
+	"33712": {"D62C7B46DBC49345B5C3E15F02871FF8EDA"},
+	"34512": {"0426285FF8B1D43653A4D078170B4761F75"},
+	"34A34": {"5E9544ECABF7EA023ED2F3A80E52492A0C9"},
+	"34EB4": {"C4EF005207E8B8F916B9F1FFFACCCD6945E"},
+	"3559E": {"FC37C61A31AA9DA4F2E4ECD952192CD9DA0"},
+	"35E52": {"AD282F5122DB1EF202C536B7CE980AB3F6C"},
+	"360E4": {"6F15F432AF83C77017177A759ABA8A58519"},
+	"36749": {"51EC264A72168CB2D89A5F634E512F6629D"},
+	"3692B": {"FA45759A67D83AEDF0045F6CB635A966ABF"},
+	"36A7A": {"C9BD13EDC65DF386D0A809ABC6268B30A1A"},
+	"37AC5": {"E111A9B2F779E373F78EFA4F7678B93FEB1"},
+	"37D2E": {"F282DFCC97EB77245FF5D24E311D58625FE"},
+	"38166": {"4F19845E3D57C071007C0139A428BF459D4"},
+	"38464": {"BF083D958B53580C63C01E56707FD043588"},
+	"3978D": {"009748EF54AD6EF7BF851BD55491B1FE6BB"},
+	"39DFA": {"55283318D31AFE5A3FF4A0E3253E2045E43"},
+	"39F6F": {"95327B31D796F8D305A29DF43B1D585E3CF"},
+	"3A01B": {"E17246D588CAF9A649F8A04E3E5D629DB94"},
+	"3A308": {"231D963D64AC22A3866B4D982CE86209A00"},
+	"3A960": {"464D36C1B8BAD183ED57EE79C0E39953CCE"},
+	"3ACD0": {"BE86DE7DCCCDBF91B20F94A68CEA535922D"},
+	"3B19E": {"CD69B492A40E3061F17786B33C28F504239"},
+	"3B92B": {"DD28588B7F448A438F818042F00BEE316D4"},
+	"3B9DE": {"09F2FF76AFE9F0AD4FCAE4FF68F52EC7FC4"},
+	"3BC6F": {"2208B90542717470DE5278F48AE0E983C97"},
+	"3CACF": {"D9C7FB9CB4CB9E97F95107E5E56BF020C5D"},
+	"3D0F3": {"B9DDCACEC30C4008C5E030E6C13A478CB4F"},
+	"3D4F2": {"BF07DC1BE38B20CD6E46949A1071F9D0E3D"},
+	"3D7B4": {"F23B8F853910E4C64F09CDF897A59DB524A"},
+	"3D920": {"9C4598BFBC38B3C096081BEE3A09697E939"},
+	"3DA54": {"1559918A808C2402BBA5012F6C60B27661C"},
+	"3DCAD": {"53B7BCDDD2D77A9C8ABF601016B7ADBDBFA"},
+	"3DD23": {"9573C69034EE59E32917AF7143F60659D55"},
+	"3E257": {"3A75821576A00DAE928F8A77E35EF60E176"},
+	"3FCFC": {"1F7F34E78A937E81171BA51DC39538DB993"},
+	"40123": {"E9C6273385EA69892C48C80AA6CB25B9113"},
+	"402F5": {"89227669E58C0FCBD6E310F6C7ED68D95C7"},
+	"4068F": {"0880B399410602D694B3CC711C8A8F4727E"},
+	"41001": {"3F679F8A5F0C2995C0432467124EF7CEA10"},
+	"41250": {"C14DB7A7F8A82EBDAF6CB6F90E154FB35E8"},
+	"4162C": {"ED6406E0FE70B201ACC706F246A448D879F"},
+	"41880": {"EE3438C878762E9A1A0FEC66BCC23DAC767"},
+	"420FC": {"C63481AC21FDCA8F011608A9F8731609CFA"},
+	"42331": {"37D1C510F2E55BA5CB220B864B11033F156"},
+	"42CFE": {"854913594FE572CB9712A188E829830291F"},
+	"42D1F": {"9243114643C3B0DC2D3E5E86A94122D2306"},
+	"42F25": {"B39E1B00C11F7050E1F29105A0C13242061"},
+	"435B4": {"1068E8665513A20070C033B08B9C66E4332"},
+	"44060": {"752D7F7AE069C8187120455195325AF0CCA"},
+	"44213": {"F9F4D59B557314FADCD233232EEBCAC8012"},
+	"44452": {"8FC68F99EA0F4FE027CB6CBD262F2A707FE"},
+	"44649": {"4B1FD32A6B2D66E2B5F470FEB0F7E1FD6C3"},
+	"44993": {"8CD38C82BCDDC2B534548DDBE984ADB8EFC"},
+	"45022": {"29742DDA5345D35A1C216DFADB1A96B3C68"},
+	"45198": {"07F709053C6DB209A1EF913328F3B511A0D"},
+	"45650": {"14CDC6B876C4531BBAE8A5D2377946BEAA1"},
+	"4580B": {"A99B3B956AE81A94DB509CDFB357B905E5F"},
+	"46147": {"6587780AA9FA5611EA6DC3912C146A91760"},
+	"466BC": {"8CEF3E71DE796EC483E212724A2C2044C68"},
+	"468DA": {"084E9953050D716E5425E004F33AC88C947"},
+	"4693D": {"851FCB96CE93BC9B8B01220C69DDED615FB"},
+	"46AC8": {"338E68F5DB86C47C36D40CA48750E452CAA"},
+	"46E3D": {"772A1888EADFF26C7ADA47FD7502D796E07"},
+	"46FC8": {"54F002BAFB7311206BCB223A0B972DFB32A"},
+	"47135": {"9C4F08C72D1FAF32A973DA3B7B4018E6365"},
+	"473C2": {"D0D0950352C9927B3EADD71015C390478CB"},
+	"474BA": {"67BDB289C6263B36DFD8A7BED6C85B04943"},
+	"47C1D": {"C4559EAE95CDDE6246BF4AA3FB058DD8373"},
+	"48058": {"E0C99BF7D689CE71C360699A14CE2F99774"},
+	"482FA": {"19D5C487CB69ACDA19EEE861CC69D82CC94"},
+	"488E3": {"99CA964E714552C654DD63D032547705816"},
+	"48EFC": {"4851E15940AF5D477D3C0CE99211A70A3BE"},
+	"49EFE": {"F5F70D47ADC2DB2EB397FBEF5F7BC560E29"},
+	"49F2B": {"18D5D38E0470E6634A98A6847190A00ADCF"},
+	"4B067": {"7CA1FC8BC7F5BD5B3581AEC09A4C3D31A30"},
+	"4B4B0": {"4529D87B5C318702BC1D7689F70B15EF4FC"},
+	"4B5D1": {"0C71B8F2EDC5C200A1EAD9D36EA7B5E68E0"},
+	"4B837": {"3D016F277527198385BA72FDA0FEB5DA015"},
+	"4BBF2": {"DDC38798E41CDC1D415C756FAA92BA47FFD"},
+	"4BE30": {"D9814C6D4E9800E0D2EA9EC9FB00EFA887B"},
+	"4BFE0": {"29D971DDB359DABED0D0AB968A329ED0AB0"},
+	"4C9A8": {"2CE72CA2519F38D0AF0ABBB4CECB9FCECA9"},
+	"4CC19": {"AAFF82F60AC4097F935AB4A06AD4F0891CC"},
+	"4D0FB": {"475B242228032CBDF6D53924D2538DF037B"},
+	"4D8B4": {"D6E78C7A1679BCF58B4E37FF35F623C2B56"},
+	"4D901": {"2B4A77A9524D675DAD27C3276AB5705E5E8"},
+	"4E17A": {"448E043206801B95DE317E07C839770C8B8"},
+	"4E199": {"B4A1C40B497A95FCD1CD896351733849949"},
+	"4E3E0": {"1B9AF84F54D95F94D24EEB0583332A85268"},
+	"4E49D": {"854C9BFFB0A64257124971234C44952926C"},
+	"4E861": {"409DBAD2B3A8DB9240779D21184BD82A860"},
+	"4E990": {"D5A3B46448665ED12DACB235676C51DEAC5"},
+	"4F26A": {"EAFDB2367620A393C973EDDBE8F8B846EBD"},
+	"4F8EF": {"089B64B5690B657D8DA56CB94A9EAB02389"},
+	"501AB": {"5444EAE9AD32B562570B36FF628EC3790CE"},
+	"505E8": {"36BB07E69BA387CD3D62A70890B0001BEBB"},
+	"5116E": {"40694AC48F654CB7B6816177E0E717237C6"},
+	"516FA": {"3FD6BF97A4B3FF09EC93877D39005A7996D"},
+	"519BC": {"3F0FDA96312357E1409DE278BFF4D5F5B25"},
+	"52547": {"92D5579984F98C41D1858E1722B2DBCC6B3"},
+	"528EC": {"39C38EBECCDC65FFBF915EDCAE51EE7A074"},
+	"52DB9": {"057163F2B831A5452D895239A249597D1CC"},
+	"5300F": {"44183EEE909B3FE2C2527315B5F4169EB55"},
+	"536C0": {"B339345616C1B33CAF454454D8B8A190D6C"},
+	"53A56": {"87CB26DC41F2AB4033E97E13ADEFD3740D6"},
+	"53E11": {"EB7B24CC39E33733A0FF06640F1B39425EA"},
+	"54669": {"547A225FF20CBA8B75A4ADCA540EEF25858"},
+	"5479F": {"2FA49524ADACFF538D1CB23DF73200D0EC6"},
+	"5491C": {"11F9EE6FF22B260040F4F1B1A3442D127C4"},
+	"54B1C": {"DF540B66C50DB0859922765DB9C5A6E5346"},
+	"5514A": {"E81CF9B1AF3B5719D9446F062E2B1F0CA9D"},
+	"55B5A": {"0F748D3A82DCE10B205ECB0A0D8916C66A1"},
+	"56259": {"DD1C4EA0117CD601FFF7AEFA0E8892A3B25"},
+	"5634C": {"D3297757D15C7E37D0A8A50EA166B448D8D"},
+	"565EE": {"90FA9602C0C16491A7A0F3F6C70D917A32B"},
+	"568B1": {"56009CA4316B0D656DA88F0E1C2ACEB2185"},
+	"57449": {"F915FCB5FB12533512C5320A98615718BBE"},
+	"57456": {"E092EE24CAF80D45AFCB55CD74AC209C9FD"},
+	"5801C": {"8B4F3BD25B0E94EFF40FBBD7D80D42DF6A0"},
+	"583AD": {"C8AEBB04A62CC76E71314B46474113BE146"},
+	"59033": {"478180D07080D5E4F3BAA0099996C364162"},
+	"59672": {"7C8A0EA4DB3BA2CECEEDCCBACD3D7B371B8"},
+	"59C82": {"6FC854197CBD4D1083BCE8FC00D0761E8B3"},
+	"5A00B": {"FD4CBA30F607EE98641CB11EC2A1572EDAC"},
+	"5A46B": {"8253D07320A14CACE9B4DCBF80F93DCEF04"},
+	"5A4F2": {"6B21EBC770C5837D49E7C35574B29654610"},
+	"5B7C4": {"FB03313B31F3B924070023A22887E72127B"},
+	"5BAA6": {"1E4C9B93F3F0682250B6CF8331B7EE68FD8"},
+	"5BC18": {"24930FFBBAFC27E7EB204260A4017859A35"},
+	"5BF82": {"649C8F5401745708119D12AB51DC7E17980"},
+	"5BFD0": {"8BDAC5988B8C1D14A86BF8AB736DB159E9F"},
+	"5C17F": {"A03E6D5FC247565E1CD8FFA70E1BFE5B8D9"},
+	"5C6AC": {"A6504E010FC38BDBF9B940CAA1D463407CF"},
+	"5C6D9": {"EDC3A951CDA763F650235CFC41A3FC23FE8"},
+	"5C8A7": {"A129DE8B649E9A0CBFBB7E9CEC37A6EFCB6"},
+	"5C968": {"8A59F3FCBFDBFEEA06378A76AF06A09AA95"},
+	"5C995": {"BBB81B028B869EE4EA7C44BB1A9EA6152BC"},
+	"5CC9D": {"C7FA726D8D8CFA53F899984125409090863"},
+	"5CEC1": {"75B165E3D5E62C9E13CE848EF6FEAC81BFF"},
+	"5D70C": {"3D101EFD9CC0A69F4DF2DDF33B21E641F6A"},
+	"5D74A": {"E093A16A00E5AF127763F2DC7E13988F162"},
+	"5DAC5": {"F2325BF44F7A7DD8B2AFE5C3728F59FD454"},
+	"5EDF2": {"57AB0926E163DA2FC52DF82E5D97ADE5F2A"},
+	"5F079": {"981221CE504832142E9526B623BBFB6E686"},
+	"5F136": {"10453FD0DABEBE3D680E0B2990619BF138C"},
+	"5F504": {"43BFE76F7279A8E0F2F0A98975CDBFF38E9"},
+	"5F50A": {"84C1FA3BCFF146405017F36AEC1A10A9E38"},
+	"5F802": {"11CCB43CD491C4E2FFBBDA4C7F6BA0FF604"},
+	"5FA33": {"9BBBB1EEACED3B52E54F44576AAF0D77D96"},
+	"5FEE0": {"0239940F883D4C2854E41C7F989E75278A3"},
+	"60098": {"2CF9C0C41E12DF616D2A9A72D675345CED7"},
+	"601F1": {"889667EFAEBB33B8C12572835DA3F027F78"},
+	"60348": {"814B4904875ADE5265A687213283FA19D4C"},
+	"6092A": {"032351D76D6AACE89D4467BAC17E09B52CE"},
+	"60C6D": {"277A8BD81DE7FDDE19201BF9C58A3DF08F4"},
+	"60EB7": {"E5F19F749BFF6C73CAEA6DE7FB0B54F27F8"},
+	"612D9": {"EC34BDDCE122042DB4C143E86DCA655BC15"},
+	"61519": {"3F904A227A9CEBF5AD3042A37668B81F4C6"},
+	"618DC": {"DFB0CD9AE4481164961C4796DD8E3930C8D"},
+	"624C2": {"2A8C8F8C93F18FE5ECD4713100C8D754507"},
+	"62560": {"0233CB3BCAB32268C17610882E0FDAED295"},
+	"62A56": {"A64C1489FBE3BAD6983401EF58E0CC26B41"},
+	"62B48": {"7BC84825B3DF028A932F082526E195EEFF2"},
+	"62C78": {"6C5932DA8817304F644E74141DB94B5B83F"},
+	"6320B": {"01C0A04AF092B14A9BEA75C2A7168D47764"},
+	"6367C": {"48DD193D56EA7B0BAAD25B19455E529F5EE"},
+	"63A5F": {"D3BC5F45A0490E4DECA178D288050E26803"},
+	"640FB": {"06193D8F2177C0FBF84F172DC686D33DD00"},
+	"6420E": {"D4D831B436D1E92D25605D18297296374E3"},
+	"64356": {"BCFAE350C970263C1CE575185B289F7B836"},
+	"643FE": {"C50E79C69BC6BBB7616AFD3904ACF40867C"},
+	"6467B": {"AA3B187373E3931422E2A8EF22F3E447D77"},
+	"64875": {"FCCCAAC069FCB3E0E201E7D5B9166641608"},
+	"64EA0": {"DC7DADD49A337F1EF14815BD3F428141C7D"},
+	"655F8": {"3BE7512E5B5B3BA4C9976C043ECE4B3CE51"},
+	"65B3D": {"D225FE19C6A9EC4383161EA00FE0F161157"},
+	"66481": {"9D8C5343676C9225B5ED00A5CDC6F3A1FF3"},
+	"66DA9": {"F3B8D9D83F34770A14C38276A69433A535B"},
+	"6713F": {"37922D4417399DF21A1BD5A189B1B0AD1CF"},
+	"675DC": {"611BAFB0B7348DD3BAF7E005B6916FB954D"},
+	"67B5F": {"A48F92CE8525701F324D6DFED859C20B64F"},
+	"67C1A": {"7FEB14FE3540F7A70650E2B9F0A5A48D3EC"},
+	"67D96": {"74C8455F6C240F73EBADBE2795BB187109A"},
+	"68C46": {"A606457643EAB92053C1C05574ABB26F861"},
+	"68EC1": {"917C84EBE566FA8DC168D6015ADFD44F415"},
+	"691AB": {"698A43FD6443F845CCD2B7F8F1607A14AEE"},
+	"69341": {"05AD50010B814C933314B1DA6841431BC8B"},
+	"69DF7": {"9BEF9287D3BCB8F104A408B06DE6A108FD8"},
+	"6B060": {"C4678D379863897045B978102BF778B80C4"},
+	"6B43E": {"6C822EC426567D261D91812135E420017C0"},
+	"6C616": {"F7C2D2FDE9018A09F06EAEFCFC7582BC7BA"},
+	"6CF34": {"755B9DE3322045869F47DC449B4785B8226"},
+	"6D0EB": {"BBDCE32474DB8141D23D2C01BD9628D6E5F"},
+	"6DEFC": {"DCE4D06B8518640F0FE5F692B639BF31A4A"},
+	"6E001": {"2C588F997639167097BDF76B5BADA65360C"},
+	"6E112": {"6F61663FAB8BC4BF7C73BF53613143E802F"},
+	"6E1A4": {"38CFE5A6C9E2165665F8C2258849CCC43F0"},
+	"6E2F9": {"E6111E77EDD0C446EA7A84E25323D137A61"},
+	"70352": {"F41061EDA4FF3C322094AF068BA70C3B38B"},
+	"7073D": {"0FAB1EA36CD0C0F1F603A2A5E44B931B31C"},
+	"70C88": {"1D4A26984DDCE795F6F71817C9CF4480E79"},
+	"70CCD": {"9007338D6D81DD3B6271621B9CF9A97EA00"},
+	"70FFC": {"281DBEC8DACF4E02E879C6E20A93B1ACD59"},
+	"7110E": {"DA4D09E062AA5E4A390B0A572AC0D2C0220"},
+	"711C7": {"3F64AFDCE07B7E38039A96D2224209E9A6C"},
+	"71486": {"86369B144C8E4147A0C9BA3E45FECEFD6B3"},
+	"7212A": {"9E01329EA93A57F574BD9BF77695D5FDCA4"},
+	"721D6": {"5122734734800A1EDD6E68C03210E7B2ACA"},
+	"7334C": {"E7FF7D6FA1CC7B6CF7F8A0588FE7ECD5D4A"},
+	"74A87": {"1ACBF060DDA5FC7260D05A5924A34E4C0E7"},
+	"75072": {"39F3C3EB689DB85A29151C0CF5BB5F4A1FD"},
+	"75105": {"193BFDD0DB68CD7B988DDA79744A9BAEA41"},
+	"75328": {"EF481B4A7A0B3513179D2780C64D9AE2186"},
+	"7539B": {"2514C21539549E11ECA3B17B90DDADBDECA"},
+	"75973": {"0A97E4373F3A0EE12805DB065E3A4A649A5"},
+	"75A0A": {"1C981FEA69A013811B3091B66D8E1457FC6"},
+	"760E7": {"DAB2836853C63805033E514668301FA9C47"},
+	"76C24": {"36B593F27AA073F0B2404531B8DE04A6AE7"},
+	"775BB": {"961B81DA1CA49217A48E533C832C337154A"},
+	"77BCE": {"9FB18F977EA576BBCD143B2B521073F0CD6"},
+	"782F9": {"B10621E362D5BD0DEF3A279B5E0908C9EBB"},
+	"7965A": {"665163253A12F43312BF69D07012A113A2A"},
+	"79946": {"7800736CC259595FDA194DF8AFA84F3D069"},
+	"79B33": {"3C96EC99512A3BF72653B23C7ED8A52DC42"},
+	"7AA12": {"9F67FDE68C6D88AA58B8B8C5C28EB7DD3A3"},
+	"7AB51": {"5D12BD2CF431745511AC4EE13FED15AB578"},
+	"7AF2D": {"10B73AB7CD8F603937F7697CB5FE432C7FF"},
+	"7AFAA": {"0A74C41394C7122FE61723DDC365F322A55"},
+	"7B218": {"48AC9AF35BE0DDB2D6B9FC3851934DB8420"},
+	"7C222": {"FB2927D828AF22F592134E8932480637C0D"},
+	"7C4A8": {"D09CA3762AF61E59520943DC26494F8941B"},
+	"7C6A6": {"1C68EF8B9B6B061B28C348BC1ED7921CB53"},
+	"7CC91": {"8F959308C71F292F9308E7A748ADF4D1434"},
+	"7CE03": {"59F12857F2A90C7DE465F40A95F01CB5DA9"},
+	"7CE82": {"77C35AC7D51701DECAD652C060741BD7E48"},
+	"7D4EE": {"BAB7CE33F2C5D6D8C6240CC8FE65EA14CD7"},
+	"7D8F4": {"B4B4613DC7E15333E6449692AD4AF502D1D"},
+	"7E41C": {"6480852A4A914E48C7A3A4084F193E963D9"},
+	"7E8B0": {"A3433F1210A9699D85420E363A1B162ECAC"},
+	"7EA35": {"D812706D9213868749011AF1ED4FA2F6AA0"},
+	"7ECFD": {"8F97B4729C6FF0799B0B4D40F870083B461"},
+	"7F2BE": {"99D71F38FEEF79D926C8F8FFA7A41C7D7DC"},
+	"7FBAB": {"7792163B4F428AF58908C61B3C6A5E8C260"},
+	"814FF": {"90C56A74B5E2BB48CD240331867A95357E1"},
+	"81941": {"ADD3E463581722BAC84D02282CAFB1C32C2"},
+	"84883": {"07681665F3DC017EBCAB0C4CD7B1733E102"},
+	"85136": {"C79CBF9FE36BB9D05D0639C70C265C18D37"},
+	"85895": {"2923C2BBB9C34D3FA859A46EFDC73EF18A7"},
+	"8594E": {"5DC6E05443FF53308A444710B3EE75FA1D2"},
+	"85D8D": {"76BA15BDE3EF1602F477F32FD64E32FEA5A"},
+	"85F45": {"E1685B99E03226A2A1371245DDB286D887A"},
+	"85F94": {"0C72D551AB70C79A22134A14DC2838D31AB"},
+	"862BF": {"FD3A14F343F266DE6AE527E300E23798289"},
+	"86A8C": {"2DA8527A1C6978BDCA6D7986FE14AE147FE"},
+	"878B3": {"4C71A5AAE401AEC0EED884BC4D4575395A9"},
+	"87ACE": {"C17CD9DCD20A716CC2CF67417B71C8A7016"},
+	"88495": {"0A05FE822DDDEE8030304783E21CDC2B246"},
+	"889C6": {"853A117ACA83EF9D6523335DC065213AE86"},
+	"88C4F": {"286BFA68445EB170E6D159B35F74E98847B"},
+	"88EA3": {"9439E74FA27C09A4FC0BC8EBE6D00978392"},
+	"88FA8": {"46E5F8AA198848BE76E1ABDCB7D7A42D292"},
+	"88FDD": {"585121A4CCB3D1540527AEE53A77C77ABB8"},
+	"891A4": {"AC3F0101A20236B7F3DBE519F0CD38413C4"},
+	"895B3": {"17C76B8E504C2FB32DBB4420178F60CE321"},
+	"8A2DA": {"05455775E8987CBFAC5A0CA54F3F728E274"},
+	"8A6B3": {"C5E6BA4DA6EBFDF08B068CA74F7D99ED161"},
+	"8BC5D": {"E83CF1DAF79ED5B2F13F93D7C05D01D0388"},
+	"8BE3C": {"943B1609FFFBFC51AAD666D0A04ADF83C9D"},
+	"8BE93": {"77EB23A3A1FF6EDAA540117CFC75C183C93"},
+	"8C16F": {"71669B51628630F3EE0D57CC3922F1F1398"},
+	"8C258": {"085654083B891CB5125CB6DCB740C8A73F8"},
+	"8CB22": {"37D0679CA88DB6464EAC60DA96345513964"},
+	"8D6E3": {"4F987851AA599257D3831A1AF040886842F"},
+	"8D993": {"CCDF628E26E170A949EE2A3870455DBD8FA"},
+	"8E627": {"A22D72ACBBE824BF8FF109367A4EB70FBEC"},
+	"8EEC7": {"BC461808E0B8A28783D0BEC1A3A22EB0821"},
+	"8F217": {"4C83B060AD8A652B5070A46CF2CC46314F0"},
+	"90093": {"37CF16333F07109B593405CF7552ED8059A"},
+	"90CF1": {"6D678E8C6F00804F1CD5F9F0E7757B13993"},
+	"92119": {"E2C63E9366ACFEFE818B50537A85577E2DB"},
+	"92429": {"D82A41E930486C6DE5EBDA9602D55C39986"},
+	"9299B": {"2A61BB26C08E468354079CADBC5CA35F664"},
+	"929D3": {"BA22D02B494DD0971784A3700C3DBF1D89F"},
+	"92F2F": {"D99879B0C2466AB8648AFB63C49032379C1"},
+	"93A4B": {"670ECF7057A2D3F561FA2C9CE6DF8E960B1"},
+	"93EC7": {"1B22793A81569C94CA17E4D9C293D8E201F"},
+	"947C8": {"44D900B26A575AEAF8EF37C3851E8BE474B"},
+	"9653A": {"F05F246108D5724E5DA6F5ED0E89FC69C02"},
+	"96773": {"332455A5770CBA61B43B62383E896C09C39"},
+	"96D53": {"734FC1BD54D848CD30F98069B90333B1BB3"},
+	"96DE5": {"543D183D7DE52AC5FA21C46FC811F673F89"},
+	"9752F": {"B540F7084FF266A7A6439FE883C380CF49F"},
+	"97627": {"2B40FB37F813D4A0104C7C8310FA8D0E85F"},
+	"97BBC": {"79679FE1CFD9AFB52FD6F01D033B479555D"},
+	"982AA": {"9D151715B549D93E019889747170D5C147D"},
+	"984FF": {"6EE7C78078D4CB1CA08255303FB8741D986"},
+	"98850": {"6D376BA789DA3640B49E2B2ECB5E9B9B8B3"},
+	"98E30": {"02450246538ADCFB1E5FF3C89071BC45C29"},
+	"99996": {"B911567C83CCE17CDF194F314975C57DDF1"},
+	"99EA0": {"D69A63871AE1D7405298539E6504F4A9D85"},
+	"99EFC": {"50A9206BDE3D7A8E694AAD8E138CA7DC3F7"},
+	"9A217": {"D4AC743134C04F39D220CDE8F9D1E4F9FA3"},
+	"9ADC7": {"A1161DDF32FF608DE792A7E50179545F026"},
+	"9B8C0": {"2FED3901E82728D18F32BB0369743B22C35"},
+	"9C19E": {"3B3DC5D215EC9402A4549988AF4734031DB"},
+	"9C421": {"D03FE8562827BCF573310051844A65DA0FC"},
+	"9C5C7": {"2058DB17D14A6E41FF3ECAC2FE6FD30F679"},
+	"9C881": {"BDB6BC930D18797D72D07BB9E01EEB40D8B"},
+	"9CE57": {"70B3BB4B2A1D59BE2D97E34379CD192299F"},
+	"9CF61": {"7634874AD4B72F7F26EA4753CF8BC3AFDC4"},
+	"9CF95": {"DACD226DCF43DA376CDB6CBBA7035218921"},
+	"9D4E1": {"E23BD5B727046A9E3B4B7DB57BD8D6EE684"},
+	"9D61B": {"A84065FC83956CDFC63E49BC7A9D21D8665"},
+	"9DC72": {"26A87062ACBF9F614CDC26FCC847A47D3DB"},
+	"9EC42": {"36A09D01395A838F2E774923B4E8548FD19"},
+	"9F2FE": {"B0F1EF425B292F2F94BC8482494DF430413"},
+	"9FD8D": {"E5FC2A7C2C0D469B2FFF1AFDE4E5DEF37BA"},
+	"A01D6": {"3C36DA6132F18E95B8B5FDB68AD01A0E314"},
+	"A0271": {"84A55211CD23E3F3094F1FDC728DF5E0500"},
+	"A04DE": {"1AE55CD191725E4C9580C65745160ED06FC"},
+	"A0847": {"543CDE93421D289F9CA3F9372A660844CED"},
+	"A0867": {"0FF00AB376DFCA8A7542DCCE81626B2B469"},
+	"A0C84": {"9D62D67126BB39974573611F1CDF03FBCA4"},
+	"A17FE": {"D27EAA842282862FF7C1B9C8395A26AC320"},
+	"A1F02": {"80EDDD46E463B6AC45B98D3A87B6C002358"},
+	"A247E": {"D270CC8ACB88EEB5865703EBCDE87AC8892"},
+	"A248B": {"F1D171D9F7EA5683F6E096512090D17D94E"},
+	"A29C5": {"7C6894DEE6E8251510D58C07078EE3F49BF"},
+	"A2B74": {"29C2D5480505D5E2673C8E4EB580F65D80D"},
+	"A2C90": {"1C8C6DEA98958C219F6F2D038C44DC5D362"},
+	"A2D44": {"5FE78F64EA1290F519E676536312581EFB1"},
+	"A346F": {"3083515CBC8CA18AAE24F331DEE2D23454B"},
+	"A36E1": {"F2D2C1309E9F4CD2D6D2EF75D01DD4FD21C"},
+	"A4097": {"E080C550462A9E3ACBA941947657CC8EE2B"},
+	"A47B5": {"CC8F06168F0EC3832A99894834E1D27F744"},
+	"A4AC9": {"14C09D7C097FE1F4F96B897E625B6922069"},
+	"A4CAC": {"82164EF67D9D07D379B5D5D8C4ABE1E02FF"},
+	"A51DD": {"A7C7FF50B61EAEA0444371F4A6A9301E501"},
+	"A61C0": {"DAFC3CB7D7887781C0943219363EDC5D18F"},
+	"A642A": {"77ABD7D4F51BF9226CEAF891FCBB5B299B8"},
+	"A6842": {"48598A590E37DD16686C8022B880A9A63D9"},
+	"A68B8": {"351560179AAC558C46820CB57B9D16DA7BF"},
+	"A6F37": {"5A196CD4C89C41DBB4500553EBF3BAB0A41"},
+	"A7759": {"1BE2044AFCD45B50ACDFCE3A585CAAE257C"},
+	"A7D57": {"9BA76398070EAE654C30FF153A4C273272A"},
+	"A807D": {"08E4C29A35398DC10E4084BDA7D2AD600A7"},
+	"A8A34": {"5BE5C4EC9546D4A8B399C0256542C1E44A6"},
+	"A93CF": {"93DB3AE6D491E1B4FC8C4E1D869DAA36A33"},
+	"A94A8": {"FE5CCB19BA61C4C0873D391E987982FBBD3"},
+	"AA743": {"A0AAEC8F7D7A1F01442503957F4D7A2D634"},
+	"AAF4C": {"61DDCC5E8A2DABEDE0F3B482CD9AEA9434D"},
+	"AB307": {"66B923D5908E5A50D5BBC76CFF6E3E3B2C2"},
+	"AB4D8": {"D2A5F480A137067DA17100271CD176607A1"},
+	"AB5E2": {"BCA84933118BBC9D48FFACCCE3BAC4EEB64"},
+	"AB65D": {"8B9611FB58F4C612F6A5EC239E0E73FD38C"},
+	"AB874": {"467A7D1FF5FC71A4ADE87DC0E098B458AAE"},
+	"AB87D": {"24BDC7452E55738DEB5F868E1F16DEA5ACE"},
+	"ABAE8": {"54DCEB7A01AB186D14E8E024480E917AF31"},
+	"ABCCF": {"54B832D256110CD9DB45C5391DA9AB6AB33"},
+	"AC137": {"C6AE0947718332991E7CB2F50EB20B62AAA"},
+	"ACE89": {"3FB2C9553A38A873FB03D0E21A406B351A1"},
+	"AD61E": {"E8F19F3D7D6F4AE2B44E18F35B3AA6BB8BE"},
+	"AD70A": {"B97AE1376E656002641CFB067C9C94906A2"},
+	"ADBA3": {"6F9108B398238E763E8E0E8997BAFCA3AE9"},
+	"AEBC3": {"EBEE2F0C8B08B43D26C2B0055B19CAEAF4A"},
+	"AF218": {"EA96A34C5BC5829A95248227654853E1043"},
+	"AF2C4": {"1EB4E034ED0A417D1EC637082072A4D3AAE"},
+	"AF897": {"8B1797B72ACFFF9595A5A2A373EC3D9106D"},
+	"AFAED": {"75406BD414820CEA4A5119F90C259C05755"},
+	"AFBA1": {"37331D0450D9FB52DF738268407E0A594A4"},
+	"B0399": {"D2029F64D445BD131FFAA399A42D2F8E7DC"},
+	"B03B7": {"4363BBB6EE42CE248C7A5344E92FFE76CC7"},
+	"B0983": {"3CEC69EFF1BB667940A45E311262E85A422"},
+	"B0F44": {"571644F9EA3C4440BB803853A4DDA25237E"},
+	"B1285": {"D4B43914CC9980FF65D3F54031D0F908E72"},
+	"B14AB": {"480028768CB748FD97DE56144A304EB8A1A"},
+	"B1B37": {"73A05C0ED0176787A4F1574FF0075F7521E"},
+	"B1F45": {"ED147D6803AC1A2A91BDEA1FAB603F910A5"},
+	"B24ED": {"7DB06817C48245A939DD97E72573A81C881"},
+	"B2A49": {"1E28DDF8A34771E051242725211EF4F54FA"},
+	"B2E98": {"AD6F6EB8508DD6A14CFA704BAD7F05F6FB1"},
+	"B2EE6": {"0370AD57D9BC3877E9024C507AB99303A64"},
+	"B2FFD": {"BEB87E8E6331D350B482B328D309BC5A321"},
+	"B363C": {"6EF45640A79DDC7BBC826A87E02734D88F0"},
+	"B3ACA": {"92C793EE0E9B1A9B0A5F5FC044E05140DF3"},
+	"B3F59": {"4E10A9EDCF5413CF1190121D45078C62290"},
+	"B4098": {"1AAB75932C5B2F555F50769D878E44913D7"},
+	"B44DD": {"A1DADD351948FCACE1856ED97366E679239"},
+	"B480C": {"074D6B75947C02681F31C90C668C46BF6B8"},
+	"B487A": {"F41779CFFB9572B982E1A0BF83F0EAFBE05"},
+	"B573F": {"24E55D6B7547CB53BD67B8F50A5256006FF"},
+	"B75C9": {"C3D904A16107B9C620CC8E6AF24C7F171CC"},
+	"B77EB": {"819278979B8524ABDDDC9CEC90F76C61268"},
+	"B7A87": {"5FC1EA228B9061041B7CEC4BD3C52AB3CE3"},
+	"B7C40": {"B9C66BC88D38A59E554C639D743E77F1B65"},
+	"B80A9": {"AED8AF17118E51D4D0C2D7872AE26E2109E"},
+	"B8468": {"9B769AB3D929F7CC14EE35E77C4AE6427C8"},
+	"B9809": {"03D8033945F546CCC9AE8A7ADF7E0223D1E"},
+	"B9864": {"15C93241513D33D01FCF532A6C47AC4F3EE"},
+	"BA5D8": {"027D4FBAF0E92582959DECFE1A2E20FD300"},
+	"BADCF": {"A3C62742B3BCC1DCD893E78713BD36AA430"},
+	"BB3AC": {"F149DB4936FBACA693A61D56BE89205D997"},
+	"BC3FA": {"85725FAAFB899D3CD087484ECD09D05D8CE"},
+	"BC654": {"0F4A42842EEE3374DDC9C66F7DDF1581D1F"},
+	"BC74F": {"4F071A5A33F00AB88A6D6385B5E6638B86C"},
+	"BCD59": {"17B85289CF889711720CE741F75C47ADD13"},
+	"BCEE5": {"9CECBC4A9A283E2AB6222DF371C0906261D"},
+	"BCEF7": {"A046258082993759BADE995B3AE8BEE26C7"},
+	"BCF22": {"DFC6FB76B7366B1F1675BAF2332A0E6A7CE"},
+	"BD340": {"4F882780FB6F1D4233CE0C3D9CBE1AD5B86"},
+	"BD5BD": {"A15418D7E571550396DDD50801D65CA7FAD"},
+	"BD5E5": {"EB049F3907175F54F5A571BA6B9FDEA36AB"},
+	"BEE38": {"FBC71DC4377BEF693AF6C11F462AC065BD6"},
+	"BF1ED": {"B9A0628BD52C6E20A2DA633EF3FB5CF8B56"},
+	"BF2F7": {"49E80C970F50552E9D5F3E8434E78B88D35"},
+	"BFCDF": {"3E6CA6CEF45543BFBB57509C92AEC9A39FB"},
+	"BFE54": {"CAA6D483CC3887DCE9D1B8EB91408F1EA7A"},
+	"BFFF2": {"DD4F1B310EB0DBF593BD83F94DD8D34077E"},
+	"C0049": {"442A7CA6D3B3EAE5BFC4439EB4FD9E52464"},
+	"C0B13": {"7FE2D792459F26FF763CCE44574A5B5AB03"},
+	"C129B": {"324AEE662B04ECCF68BABBA85851346DFF9"},
+	"C16AA": {"B9FE3288DF0FB8FC1D24990A300B6B8F299"},
+	"C2011": {"091E592A41D557B425C4DA65241FCE12C0C"},
+	"C22D4": {"A0C96122151D0F579000083484879DBB527"},
+	"C23DF": {"43FA2D4AEF609585DC8CC55F150138BCA54"},
+	"C2577": {"430D91716490DC5D33C20D901E008B696E7"},
+	"C29E4": {"D9C8824409119EAA8BA182051B89121E663"},
+	"C3140": {"5B16FBB48ADB41B8F6505E788FCB13EBD91"},
+	"C3387": {"3C987BC9D5BC6A51E095311D747B85A78E1"},
+	"C33F0": {"59B0CA7725FBFD6C9EA4F2F012CC7AC5A74"},
+	"C35B0": {"7262FCA57647E4281358EEC6674C2C5BB44"},
+	"C3F63": {"EE769C8F251565E45CF724F6E4EFAEE0387"},
+	"C448A": {"AA999398E9C1D52956094F51B4BDC7DA3D3"},
+	"C4CEC": {"A4FD2C0A6E4F444CD2646248DD74DCB1B91"},
+	"C5325": {"5317BB11707D0F614696B3CE6F221D0E2F2"},
+	"C5391": {"53BA1F947BD4B6F910263B967C4A0A62357"},
+	"C590A": {"FA9BB59191FFAB30F223791E82D3FD3E3AF"},
+	"C6026": {"6A8ADAD2F8EE67D793B4FD3FD0FFD73CC61"},
+	"C6922": {"B6BA9E0939583F973BC1682493351AD4FE8"},
+	"C692D": {"6A10598E0A801576FDD4ECF3C37E45BFBC4"},
+	"C75C6": {"ABEBD904A02E62CFE65E0A82DD55414A217"},
+	"C824F": {"E0AFE16857DD6F587AA7C4044D2642D60FB"},
+	"C8A50": {"F632C3C4BAF27FC05FACB1883104E1D16EF"},
+	"C8D99": {"C2F7CD5F432C163ABCD422672B9F77550BB"},
+	"C9525": {"9DE1FD719814DAEF8F1DC4BD64F9D885FF0"},
+	"C984A": {"ED014AEC7623A54F0591DA07A85FD4B762D"},
+	"CA581": {"782DD06E7199AC414994744D633ED8FEDEF"},
+	"CA709": {"18E5246BC91B47ECB4EC585293C593C6412"},
+	"CA929": {"0D12CE41B907521589D52120245481AB028"},
+	"CAD15": {"24360E58851CD0AE1E82B75FF5283474667"},
+	"CAE35": {"5B615B61313E7A2D42D0C650F705DC3D94E"},
+	"CB047": {"D26CECB70DE3B7E682FA5E9D6C5539F7603"},
+	"CB45C": {"671CBC500627EA424EEA5F91996221B5935"},
+	"CB654": {"AC8F36F840016F043AA3E4E06796529704D"},
+	"CBB73": {"53E6D953EF360BAF960C122346276C6E320"},
+	"CBDB0": {"CC7F3F5B4BE81A75FA7242590E3E9882E1E"},
+	"CBF25": {"10A5F9F7EECE23428DA7125C06115839E2B"},
+	"CBF41": {"F5B461CEA4E1E261D2918D5334BEE8C6A06"},
+	"CBFDA": {"C6008F9CAB4083784CBD1874F76618D2A97"},
+	"CC472": {"3995CE819915E734147A77850427A9E95F9"},
+	"CC9F8": {"16A42431CF852CDC7A3FAD42A6F65FFCE24"},
+	"CCDEB": {"3789AA4A84316FCF8AC51977126BEF8DE35"},
+	"CD1B3": {"3E25BDFF155B4063E0262049799E5D4F0E2"},
+	"CDF54": {"7ED4C64E6994AF35CFCD69C4204C9227A97"},
+	"CDF6D": {"9EFE408D1290F449E3802C437E266BDC88D"},
+	"CE560": {"BB434FE815838A2ECD1190E5C87638F26EC"},
+	"CE6A5": {"0F4F8E62545EE777E70B84669EFAE4EB271"},
+	"CEA67": {"55B26710FB5B31D9AB6615142F7552AD9DD"},
+	"CEDF4": {"1FCCB586DC39E1CE34BB482F0AFE557B49F"},
+	"CEF7E": {"59218E3A7E18AAF7FAA4A23BCD964323A66"},
+	"CFE74": {"FFCE19725B649A58C767CF804FA2E18EF54"},
+	"D033E": {"22AE348AEB5660FC2140AEC35850C4DA997"},
+	"D04C1": {"675B232C6ECE69ED95E189E95D589F217B0"},
+	"D0A65": {"436A81128B4FAC0F27A75B9A15CFD6F07C9"},
+	"D0BE2": {"DC421BE4FCD0172E5AFCEEA3970E2F3D940"},
+	"D0EE3": {"45E31F83883D76B54EDD9964410ADBD191B"},
+	"D232C": {"6C498283DA7CB5B433A82E2B2BB9D5B39A9"},
+	"D29BF": {"1C58FD7E4B2176064A97F21595954139A74"},
+	"D30D7": {"7BC8442DB84A0F7343D0256480D3F1B74C4"},
+	"D318F": {"44739DCED66793B1A603028133A76AE680E"},
+	"D4F55": {"DEC8C7BC9675182779E564FAE1327D30F9B"},
+	"D50F3": {"D3D525303997D705F86CD80182365F964ED"},
+	"D5365": {"2DE63B26F2B99ABFC5699FAC10F3F95E1F7"},
+	"D54B7": {"6B2BAD9D9946011EBC62A1D272F4122C7B5"},
+	"D5BD4": {"22EFE6A0881A746E4F32360CAD19E91117E"},
+	"D637E": {"6EDAF4193FFCD807B5F60282A26FF72989B"},
+	"D6791": {"DDBA07DF4735F83E91C43814E891038559C"},
+	"D6955": {"D9721560531274CB8F50FF595A9BD39D66F"},
+	"D6CFE": {"5E76C8347BC803168FE861F69FCC69CC79C"},
+	"D6F8C": {"DD522E4013EA482C6DFB3154C086B627EEC"},
+	"D714D": {"8456935FA20E60BD9E661423CB2583C79D9"},
+	"D7966": {"074B3D619B43EE1C6296AE5332C48D6CB1C"},
+	"D79AC": {"4A2B1AC0251B7BBBCEB4649E4A964BC5597"},
+	"D7EB2": {"AA54EC8D25420A7E45089969F7BDD0F4A9E"},
+	"D81B6": {"9B3443BE6529521AE051E08515F45B39BF1"},
+	"D8516": {"07621E80FD175DFECBBA90F2DF08DFAD5BF"},
+	"D869D": {"B7FE62FB07C25A0403ECAEA55031744B5FB"},
+	"D8B9E": {"A0DE170D9B948FE78D155A04F49EF6EEEAD"},
+	"D8CD1": {"0B920DCBDB5163CA0185E402357BC27C265"},
+	"D915F": {"4E970E53654202C1CF5C62E60A7280A8219"},
+	"D969E": {"7E0B0571370CD6763192BC24AC56C255472"},
+	"D99A1": {"6EBF6A70D2F47406343DF6BC9DAEF0D4895"},
+	"D9D71": {"AB718931A89DE1E986BC62F6C988DDC1813"},
+	"DABA7": {"8D3C4AD9A0083B686515778DABDB3305BED"},
+	"DB25F": {"2FC14CD2D2B1E7AF307241F548FB03C312A"},
+	"DB4B2": {"7566B63F17B3082D7EE96BC773DC86D8E7E"},
+	"DB9D9": {"4A2F9D45102C4C9B09DBD13AD3D116AE0B4"},
+	"DC724": {"AF18FBDD4E59189F5FE768A5F8311527050"},
+	"DC76E": {"9F0C0006E8F919E0C515C66DBBA3982F785"},
+	"DC796": {"FFDB94337B1B76087DED630ADA2E7A02ACD"},
+	"DC918": {"6A06078733915A6FCBAB34E59120BE2B484"},
+	"DCB94": {"B0B87D6222FD6F30214FE01ABE179A9B16E"},
+	"DD08B": {"58E1D30DAD48D37A35A8760CFFE8D756CFA"},
+	"DD5FE": {"F9C1C1DA1394D6D34B248C51BE2AD740840"},
+	"DDF45": {"997A7E18A25AD5F5CF222DA64814DD060D5"},
+	"DE346": {"0832EA070EFFABBC7032D7594BBDE1BB120"},
+	"DE4AB": {"6E26DB462B930510BA83E9F80B7DB2BEF88"},
+	"DE5E9": {"2D0AA220E70735AC740F87439CFA8F82EB2"},
+	"DEA74": {"2E166979027AE70B28E0A9006FB1010E760"},
+	"DEFF1": {"D836528DB4FD128932EBD48E568E52B7BB4"},
+	"DF0B6": {"C410FC70CEEB16C10880A3D0A573CA26631"},
+	"DF70F": {"9B975B42116EE6C0231A7E6EAD0BBB283AA"},
+	"DFE2D": {"B74975E0AA9F6FDD4D61DEDCB7328502456"},
+	"E07F8": {"C4AB682212744526982F0F08D336E1C9041"},
+	"E0C95": {"748A455C27A80FD289269120D4944D1F318"},
+	"E0F34": {"FFA3C10D2940937A2D499DB16C5E72F5D58"},
+	"E10E8": {"4BE7F575EFA10A8F64F2E52E9D8B30A52E9"},
+	"E10F8": {"315A56FF5A31C910B310DA6A09BE4846584"},
+	"E18BA": {"7E526C93A837D7BA6D45EA292AD66C42930"},
+	"E2869": {"77B13F1A89E20D0459207545D15FE1EBA08"},
+	"E2F3E": {"36EA43BA45AB3503CED0A944CD1A950065C"},
+	"E30A8": {"3CC3A6473FBE7B3C5F99F92865E61A1F55E"},
+	"E35BE": {"CE6C5E6E0E86CA51D0440E92282A9D6AC8A"},
+	"E38AD": {"214943DAAD1D64C102FAEC29DE4AFE9DA3D"},
+	"E3CD9": {"F6469FC3E1ACFB9F2BDBFC5A3D2BBB8E2AD"},
+	"E3D9D": {"95962C452F35E4CE7166B8D584F7B43ADF0"},
+	"E4210": {"28269715F36C3FC6CA42F5FA4787876AD0D"},
+	"E4722": {"3A8F61EA86FE5A82D5DD48D2D0CA6E9684B"},
+	"E53D9": {"2CAA56E00A9CFB84EBFD57DDE859F77E2C1"},
+	"E5E9F": {"A1BA31ECD1AE84F75CAAA474F3A663F05F4"},
+	"E68E1": {"1BE8B70E435C65AEF8BA9798FF7775C361E"},
+	"E6986": {"7CA7D5A7B0AB60A2A61E7B791C106F7BF64"},
+	"E6CC0": {"FB2B8DAD4110EF62E9A33E5A8AA4E0F86D7"},
+	"E7039": {"08953979ABA5049EC2E83F4E104282ABE84"},
+	"E79EF": {"C4520FBD4B25C3660F5B088BD388C6C61E3"},
+	"E7EA4": {"F94CB4AF75C6643566CA6D95D9433B8A6F2"},
+	"E8072": {"1793C24AE14EDFCA9B26AD406A9815CD3FF"},
+	"E8126": {"C64C3486E84081FFFAD6A0AB22D4267BB41"},
+	"E9BFB": {"2C543DD33934BCFF71879B108246996B567"},
+	"EAB0F": {"0D675765E4F0E8773762673A9D86F53028C"},
+	"EAB3D": {"2BAB6DED567F25CA57B0C0D2C21EE017287"},
+	"EB068": {"C74E80689F5FE7A1028D991786BBACCFF57"},
+	"EB3B0": {"C150D06E5AA2E8D921FEA8C1056C1FEA6F8"},
+	"EBE53": {"C61982711F13AF8BBC09844E4E2849268BA"},
+	"EBFC7": {"910077770C8340F63CD2DCA2AC1F120444F"},
+	"EC30A": {"DC79E734900430E4174CF0A36C2D0C42272"},
+	"EC408": {"3CA341DA86269204F1FDEBBA909F0F5699E"},
+	"EC461": {"B5480380ECF863D9802EDBE70152AEE1C46"},
+	"EC5A7": {"C3E21436A8E76716710CE551356F9AA745E"},
+	"EC711": {"7851C0E5DBAAD4EFFDB7CD17C050CEA88CB"},
+	"ECB7B": {"4F4EA2FE692223555D6051620A093CA01CB"},
+	"ECE4E": {"6B27CF0A2C5C9D83E44BFD5A71795F8A6E0"},
+	"ED4B0": {"10FF1358E962D6AD1CDC7F4EA698BDE8239"},
+	"ED9D3": {"D832AF899035363A69FD53CD3BE8F71501C"},
+	"EE848": {"A3B5B3FB00481D269777D97FD7795DD1A70"},
+	"EE87E": {"62281EE4CEE394DD9B5FF17A4FAB7AB84FC"},
+	"EE8D8": {"728F435FD550F83852AABAB5234CE1DA528"},
+	"EEFC1": {"767FEC313F654053139E7D7AA4D786E6387"},
+	"EF047": {"4C47C7C51DBD5CECCD4D96ED6B90E6F5664"},
+	"EF0EB": {"BB77298E1FBD81F756A4EFC35B977C93DAE"},
+	"EF783": {"0DB5BFBF3536820C00105AB5734EF4609FC"},
+	"EF89A": {"3A842B0384565A210F0122804F411FE51FB"},
+	"EF971": {"EE38BBA25D9AC8A840D235457A038448B09"},
+	"EF9A6": {"F5BF9F36B2E2487F0B174990A581CA8C044"},
+	"EFB29": {"D093BDDEA2C0C2712631ABACA6D0081EC2B"},
+	"EFC6B": {"7D61533CFDDA07064E14D0B94A8C322CDDF"},
+	"EFCE8": {"CD161897FEEAA7979D892DC26A8A8D8EEA3"},
+	"EFEBD": {"FC78EA1935C4B926324522B452B766FBC76"},
+	"F001F": {"96576472A769C087F98121B0345A559A11E"},
+	"F0744": {"D60DD500C92C0D37C16174CC58D3C4BDD8E"},
+	"F0D61": {"723FDF7301391BEA5FFF1EF28FA3C7D0EEA"},
+	"F0F73": {"2BCA615FFEFE8510FD4475653A633F0750D"},
+	"F1196": {"A8A993E28D05BD187B7B130720E5DD34147"},
+	"F11EA": {"658082349955674A565FE658AD5BEDFB328"},
+	"F15E5": {"18A239A5DDBC4E7F942B93B7FBD60C1048D"},
+	"F1707": {"F87B7662B61EA627B9769338D60AA852E16"},
+	"F18F9": {"D8BAA2FA0CB58562A87B426733853E0A4E9"},
+	"F1B44": {"E125E30BFD0ED3CEAD5AFB55376F957350D"},
+	"F1B5A": {"91D4D6AD523F2610114591C007E75D15084"},
+	"F1CA6": {"ECC68651B9E3B717B8A5B568309978FF98C"},
+	"F1E64": {"002D25976DA3F216D67976C0475364B5F4D"},
+	"F1EB0": {"8C4E3F8A5AB5761723B1210AD4C30E41DC7"},
+	"F2439": {"E4EA89A947308076ED64BCB5EDD10BA4892"},
+	"F25B7": {"2CF45C8EF0687D919E455F9064205653713"},
+	"F2847": {"B1BD9624F927E979C1846D9FE17DD65F518"},
+	"F2A12": {"F187EBB7080BD75AAC9160214E6B1E49F7D"},
+	"F2B14": {"F68EB995FACB3A1C35287B778D5BD785511"},
+	"F3215": {"7A45887E4FE5ADC0B5198F7EC4920A526D7"},
+	"F32BC": {"A49B3796C2F74F13B29FCDBF6C5F7BE00A8"},
+	"F3415": {"0D4573703380AB0B3D610C554C91479C993"},
+	"F4542": {"DB9BA30F7958AE42C113DD87AD21FB2EDDB"},
+	"F49F5": {"77D627D39B70E8F55692AAB6D21A8611FC0"},
+	"F4A69": {"973E7B0BF9D160F9F60E3C3ACD2494BEB0D"},
+	"F4C16": {"FCFFE10DC7743AB27040AC0A805B3D54F9A"},
+	"F4EE7": {"415066B23ED0C5555E3A10AA76726A995D7"},
+	"F504F": {"8ABA09A861A7D3D2462F10D72DCC63AEADE"},
+	"F56D6": {"351AA71CFF0DEBEA014D13525E42036187A"},
+	"F58CF": {"5E7E10F195E21B553096D092C763ED18B0E"},
+	"F5DA2": {"5704AF3EBD5808A6D561413A8E3EE4DB62D"},
+	"F67A1": {"883F3921718C3FE37A3D6CFD3518A73B47A"},
+	"F71B4": {"7E5F8BE4C6E31DAD9F5BB646B0D544B5A90"},
+	"F732D": {"FDBD0AED62727F958CCCCA9EC3A5CB13EDA"},
+	"F7A9E": {"24777EC23212C54D7A350BC5BEA5477FDBB"},
+	"F7C3B": {"C1D808E04732ADF679965CCC34CA7AE3441"},
+	"F80D0": {"CA101E967B50B730DDF8E8ACA0DE85E8DF6"},
+	"F8248": {"E12727710C946F73D8F6E02EB93530DD9DE"},
+	"F865B": {"53623B121FD34EE5426C792E5C33AF8C227"},
+	"F872C": {"AAD177D67BBE18C119D0505F2D3CAA02AF3"},
+	"F9A3B": {"F509DF08651E7E2E1052F9695B878C0783E"},
+	"FA2CA": {"509FA3E8098FEF64564B46DFB0C51900932"},
+	"FA376": {"E383626491FB6F3B6B5C06B1C208BBA702B"},
+	"FA697": {"7C99B809DB68E1C56888EC38BD004719B39"},
+	"FA9BE": {"B99E4029AD5A6615399E7BBAE21356086B3"},
+	"FAC4D": {"F3AC163AC84229520B26B81411854E694FF"},
+	"FAC67": {"3092FBDCAB2CD92EFC19675F2750ED97CA1"},
+	"FAFDF": {"3100F711534E89E32C9E33016EE95E0C2B4"},
+	"FB271": {"93AB6E0BB48F6E68125B8A04F12B65A41DC"},
+	"FBA9F": {"1C9AE2A8AFE7815C9CDD492512622A66302"},
+	"FBB26": {"A620528A062BA43CCB4BB5E71C714DF8E5E"},
+	"FC278": {"9A2F2F3303F7322EFA51BB5882FE034A321"},
+	"FC84A": {"AA687374AED41957693F32664E5F4981862"},
+	"FC8E9": {"7F57F3A41D70F13A42B3DC81B52D57CBA74"},
+	"FCB8F": {"40140297C7D1E3464C53E1F9A8BC4DDBEDF"},
+	"FD2B0": {"A636ED0C80C1646CD2C2E72F7A758B42B5B"},
+	"FD68D": {"303E5C01C188D5518526CEE844721646A36"},
+	"FDB87": {"DFD199045AF7165780B11640B83768A0D57"},
+	"FDDA0": {"C46F953C1A45BDC520849BE1E4EDF4E228C"},
+	"FE09B": {"C2EF2737A3258F978E26226DCBAC1B3F948"},
+	"FE105": {"66E2ADEECE8FAF585A8FBD5DB896E4A60F7"},
+	"FEA7F": {"657F56A2A448DA7D4B535EE5E279CAF3D9A"},
+	"FF9E4": {"3337E6AF8AB422C86C86B5C7F99375BF5C0"},
+	"FFAAA": {"FBDEE1DE041310096E1FF171618A2049F6E"},
+	"FFB47": {"61CBA839470133BEE36AEB139F58D7DBAA9"},
+}
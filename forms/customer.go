@@ -2,6 +2,7 @@ package forms
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -9,42 +10,134 @@ import (
 // CustomerForm ...
 type CustomerForm struct{}
 
+// CustomerAddressForm ...
+// The first address of each type is the default one unless another one is marked as default
+type CustomerAddressForm struct {
+	Type       string `form:"type" json:"type" binding:"required,oneof=billing shipping"`
+	Default    bool   `form:"default" json:"default"`
+	Line1      string `form:"line1" json:"line1" binding:"required,max=200"`
+	Line2      string `form:"line2" json:"line2" binding:"max=200"`
+	City       string `form:"city" json:"city" binding:"required,max=100"`
+	Region     string `form:"region" json:"region" binding:"max=100"`
+	PostalCode string `form:"postal_code" json:"postal_code" binding:"max=20"`
+	Country    string `form:"country" json:"country" binding:"required,iso3166_1_alpha2"`
+}
+
+// CustomerContactForm ...
+type CustomerContactForm struct {
+	Name  string `form:"name" json:"name" binding:"required,max=200"`
+	Role  string `form:"role" json:"role" binding:"max=100"`
+	Email string `form:"email" json:"email" binding:"omitempty,email,max=254"`
+	Phone string `form:"phone" json:"phone" binding:"omitempty,e164"`
+}
+
 // CreateCustomerForm ...
+// Phone numbers are in the E.164 format (+14155550123), the addresses and contacts replace the current ones on update
 type CreateCustomerForm struct {
-	Title   string `form:"title" json:"title" binding:"required,min=3,max=100"`
-	Content string `form:"content" json:"content" binding:"required,min=3,max=1000"`
+	Name        string                `form:"name" json:"name" binding:"required,min=2,max=200"`
+	CompanyName string                `form:"company_name" json:"company_name" binding:"max=200"`
+	TaxID       string                `form:"tax_id" json:"tax_id" binding:"max=50"`
+	Email       string                `form:"email" json:"email" binding:"omitempty,email,max=254"`
+	Phone       string                `form:"phone" json:"phone" binding:"omitempty,e164"`
+	Tags        []string              `form:"tags" json:"tags" binding:"omitempty,max=20,dive,required,max=50"`
+	Notes       string                `form:"notes" json:"notes" binding:"max=5000"`
+	Addresses   []CustomerAddressForm `form:"addresses" json:"addresses" binding:"omitempty,max=20,dive"`
+	Contacts    []CustomerContactForm `form:"contacts" json:"contacts" binding:"omitempty,max=50,dive"`
 }
 
-// Title ...
-func (f CustomerForm) Title(tag string, errMsg ...string) (message string) {
+// Name ...
+func (f CustomerForm) Name(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the customer title"
+			return "Please enter the customer name"
 		}
 		return errMsg[0]
 	case "min", "max":
-		return "Title should be between 3 to 100 characters"
+		return "Name should be between 2 to 200 characters"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Content ...
-func (f CustomerForm) Content(tag string, errMsg ...string) (message string) {
+// Email ...
+func (f CustomerForm) Email(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "email", "max":
+		return "Please enter a valid email"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Phone ...
+func (f CustomerForm) Phone(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "e164":
+		return "Phone should be in the international format (e.g. +14155550123)"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Tags ...
+func (f CustomerForm) Tags(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "A customer can have up to 20 tags of up to 50 characters"
+	case "required":
+		return "Tags can not be empty"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Address ...
+func (f CustomerForm) Address(field, tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the customer content"
+			return "Please enter the address " + field
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Content should be between 3 to 1000 characters"
+	case "oneof":
+		return "Address type should be either billing or shipping"
+	case "max":
+		return "Address " + field + " is too long"
+	case "iso3166_1_alpha2":
+		return "Address country should be a valid ISO 3166 code (e.g. US)"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
+// Contact ...
+func (f CustomerForm) Contact(field, tag string, errMsg ...string) (message string) {
+	switch field {
+	case "Name":
+		return "Every contact needs a name of up to 200 characters"
+	case "Role":
+		return "Contact role should be less than 100 characters"
+	case "Email":
+		return "Please enter a valid contact email"
+	case "Phone":
+		return "Contact phone should be in the international format (e.g. +14155550123)"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// addressFields names the address fields in the messages
+var addressFields = map[string]string{
+	"Type":       "type",
+	"Line1":      "line",
+	"Line2":      "line",
+	"City":       "city",
+	"Region":     "region",
+	"PostalCode": "postal code",
+	"Country":    "country",
+}
+
 // Create ...
 func (f CustomerForm) Create(err error) string {
 	switch err.(type) {
@@ -55,11 +148,33 @@ func (f CustomerForm) Create(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
+			//The nested errors are reported as CreateCustomerForm.Contacts[0].Email
+			switch {
+			case strings.HasPrefix(err.StructNamespace(), "CreateCustomerForm.Addresses["):
+				return f.Address(addressFields[err.Field()], err.Tag())
+			case strings.HasPrefix(err.StructNamespace(), "CreateCustomerForm.Contacts["):
+				return f.Contact(err.Field(), err.Tag())
+			case strings.HasPrefix(err.StructNamespace(), "CreateCustomerForm.Tags"):
+				return f.Tags(err.Tag())
 			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+
+			switch err.Field() {
+			case "Name":
+				return f.Name(err.Tag())
+			case "CompanyName":
+				return "Company name should be less than 200 characters"
+			case "TaxID":
+				return "Tax ID should be less than 50 characters"
+			case "Email":
+				return f.Email(err.Tag())
+			case "Phone":
+				return f.Phone(err.Tag())
+			case "Notes":
+				return "Notes should be less than 5000 characters"
+			case "Addresses":
+				return "A customer can have up to 20 addresses"
+			case "Contacts":
+				return "A customer can have up to 50 contacts"
 			}
 		}
 
@@ -72,25 +187,5 @@ func (f CustomerForm) Create(err error) string {
 
 // Update ...
 func (f CustomerForm) Update(err error) string {
-	switch err.(type) {
-	case validator.ValidationErrors:
-
-		if _, ok := err.(*json.UnmarshalTypeError); ok {
-			return "Something went wrong, please try again later"
-		}
-
-		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
-			}
-		}
-
-	default:
-		return "Invalid request"
-	}
-
-	return "Something went wrong, please try again later"
+	return f.Create(err)
 }
@@ -0,0 +1,66 @@
+package forms
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// EmailForm ...
+type EmailForm struct{}
+
+// EmailRequestForm ...
+// The email to send the verification or password reset link to
+type EmailRequestForm struct {
+	Email string `form:"email" json:"email" binding:"required,email"`
+}
+
+// EmailTokenForm ...
+// The token of the link in the verification email
+type EmailTokenForm struct {
+	Token string `form:"token" json:"token" binding:"required,max=200"`
+}
+
+// ResetPasswordForm ...
+// The token of the link in the password reset email with the new password
+type ResetPasswordForm struct {
+	Token    string `form:"token" json:"token" binding:"required,max=200"`
+	Password string `form:"password" json:"password" binding:"required,max=72"`
+}
+
+// Request ...
+func (f EmailForm) Request(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			if err.Field() == "Email" {
+				return UserForm{}.Email(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
+
+// Token ...
+func (f EmailForm) Token(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Token":
+				return "The link is invalid, please request a new one"
+			case "Password":
+				return UserForm{}.Password(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -0,0 +1,128 @@
+package forms
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// InventoryForm ...
+type InventoryForm struct{}
+
+// CreateWarehouseForm ...
+type CreateWarehouseForm struct {
+	Code string `form:"code" json:"code" binding:"required,sku"`
+	Name string `form:"name" json:"name" binding:"required,min=3,max=200"`
+}
+
+// StockMovementForm ...
+// Receipts add stock to the warehouse and adjustments correct it in either direction (e.g. -2 for a damaged pair),
+// VariantID is only set for the stock of a variant
+type StockMovementForm struct {
+	WarehouseID int64  `form:"warehouse_id" json:"warehouse_id" binding:"required,min=1"`
+	VariantID   int64  `form:"variant_id" json:"variant_id" binding:"omitempty,min=1"`
+	Type        string `form:"type" json:"type" binding:"required,oneof=receipt adjustment"`
+	Quantity    int64  `form:"quantity" json:"quantity" binding:"required,min=-1000000,max=1000000"`
+	Note        string `form:"note" json:"note" binding:"max=500"`
+}
+
+// Code ...
+func (f InventoryForm) Code(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the warehouse code"
+		}
+		return errMsg[0]
+	case "sku":
+		return "Code should be up to 64 letters, digits, dots, dashes or underscores"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Name ...
+func (f InventoryForm) Name(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the warehouse name"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Name should be between 3 to 200 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// CreateWarehouse ...
+func (f InventoryForm) CreateWarehouse(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Code":
+				return f.Code(err.Tag())
+			case "Name":
+				return f.Name(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
+
+// Quantity ...
+func (f InventoryForm) Quantity(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter a quantity other than 0"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Quantity should be between -1000000 and 1000000"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Move ...
+func (f InventoryForm) Move(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "WarehouseID":
+				return "Please select the warehouse"
+			case "VariantID":
+				return "Please select a valid variant"
+			case "Type":
+				return "Type should be either receipt or adjustment"
+			case "Quantity":
+				return f.Quantity(err.Tag())
+			case "Note":
+				return "Note should be less than 500 characters"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
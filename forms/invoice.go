@@ -9,65 +9,70 @@ import (
 // InvoiceForm ...
 type InvoiceForm struct{}
 
-// CreateInvoiceForm ...
-type CreateInvoiceForm struct {
-	Title   string `form:"title" json:"title" binding:"required,min=3,max=100"`
-	Content string `form:"content" json:"content" binding:"required,min=3,max=1000"`
+// GenerateInvoiceForm ...
+// DueDate defaults to 30 days from today when empty
+type GenerateInvoiceForm struct {
+	DueDate string `form:"due_date" json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+	Notes   string `form:"notes" json:"notes" binding:"max=1000"`
 }
 
-// Title ...
-func (f InvoiceForm) Title(tag string, errMsg ...string) (message string) {
+// UpdateInvoiceForm ...
+type UpdateInvoiceForm struct {
+	DueDate        string `form:"due_date" json:"due_date" binding:"required,datetime=2006-01-02"`
+	BillingName    string `form:"billing_name" json:"billing_name" binding:"max=200"`
+	BillingAddress string `form:"billing_address" json:"billing_address" binding:"max=1000"`
+	BillingTaxID   string `form:"billing_tax_id" json:"billing_tax_id" binding:"max=50"`
+	Notes          string `form:"notes" json:"notes" binding:"max=1000"`
+}
+
+// DueDate ...
+func (f InvoiceForm) DueDate(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the invoice title"
+			return "Please enter the invoice due date"
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Title should be between 3 to 100 characters"
+	case "datetime":
+		return "Due date should be formatted as YYYY-MM-DD"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Content ...
-func (f InvoiceForm) Content(tag string, errMsg ...string) (message string) {
+// BillingName ...
+func (f InvoiceForm) BillingName(tag string, errMsg ...string) (message string) {
 	switch tag {
-	case "required":
-		if len(errMsg) == 0 {
-			return "Please enter the invoice content"
-		}
-		return errMsg[0]
-	case "min", "max":
-		return "Content should be between 3 to 1000 characters"
+	case "max":
+		return "Billing name should be less than 200 characters"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Create ...
-func (f InvoiceForm) Create(err error) string {
-	switch err.(type) {
-	case validator.ValidationErrors:
-
-		if _, ok := err.(*json.UnmarshalTypeError); ok {
-			return "Something went wrong, please try again later"
-		}
-
-		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
-			}
-		}
+// BillingAddress ...
+func (f InvoiceForm) BillingAddress(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Billing address should be less than 1000 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
 
+// Notes ...
+func (f InvoiceForm) Notes(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Notes should be less than 1000 characters"
 	default:
-		return "Invalid request"
+		return "Something went wrong, please try again later"
 	}
+}
 
-	return "Something went wrong, please try again later"
+// Generate ...
+func (f InvoiceForm) Generate(err error) string {
+	return f.Update(err)
 }
 
 // Update ...
@@ -80,11 +85,17 @@ func (f InvoiceForm) Update(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+			switch err.Field() {
+			case "DueDate":
+				return f.DueDate(err.Tag())
+			case "BillingName":
+				return f.BillingName(err.Tag())
+			case "BillingAddress":
+				return f.BillingAddress(err.Tag())
+			case "BillingTaxID":
+				return "Billing tax ID should be less than 50 characters"
+			case "Notes":
+				return f.Notes(err.Tag())
 			}
 		}
 
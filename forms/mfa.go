@@ -0,0 +1,57 @@
+package forms
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// MFAForm ...
+type MFAForm struct{}
+
+// MFACodeForm ...
+// Code is the 6 digits of the authenticator app or, where accepted, one of the recovery codes
+type MFACodeForm struct {
+	Code string `form:"code" json:"code" binding:"required,max=20"`
+}
+
+// MFALoginForm ...
+// The second step of the login, MFAToken is the challenge returned by the login with the password
+type MFALoginForm struct {
+	MFAToken string `form:"mfa_token" json:"mfa_token" binding:"required,max=200"`
+	Code     string `form:"code" json:"code" binding:"required,max=20"`
+}
+
+// Code ...
+func (f MFAForm) Code(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the code of your authenticator app"
+		}
+		return errMsg[0]
+	case "max":
+		return "Invalid code"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Verify ...
+func (f MFAForm) Verify(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Code":
+				return f.Code(err.Tag())
+			case "MFAToken":
+				return "The login expired, please log in again"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -0,0 +1,147 @@
+package forms
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// OAuthForm ...
+type OAuthForm struct{}
+
+// CreateOAuthClientForm ...
+// A confidential client (a server) gets a secret and may use the client credentials grant,
+// a public client (a mobile or browser app) only has the authorization code flow with PKCE
+type CreateOAuthClientForm struct {
+	Name         string   `form:"name" json:"name" binding:"required,min=3,max=100"`
+	RedirectURIs []string `form:"redirect_uris" json:"redirect_uris" binding:"omitempty,max=10,dive,required,url,max=500"`
+	Scopes       []string `form:"scopes" json:"scopes" binding:"required,min=1,max=50,dive,required,max=50"`
+	Confidential bool     `form:"confidential" json:"confidential"`
+}
+
+// AuthorizeForm ...
+// The query of the authorization request (RFC 6749 4.1.1) with its PKCE challenge (RFC 7636), only S256 is supported.
+// Scope is space separated, Approve is the answer of the user on the consent screen
+type AuthorizeForm struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,eq=code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required,max=100"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required,url,max=500"`
+	Scope               string `form:"scope" json:"scope" binding:"required,max=2000"`
+	State               string `form:"state" json:"state" binding:"max=500"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required,min=43,max=128"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,eq=S256"`
+	Approve             bool   `form:"approve" json:"approve"`
+}
+
+// OAuthTokenForm ...
+// The token request of every supported grant, form encoded as in RFC 6749.
+// The confidential clients may send their credentials with HTTP Basic authentication instead
+type OAuthTokenForm struct {
+	GrantType    string `form:"grant_type" json:"grant_type" binding:"required,oneof=authorization_code client_credentials refresh_token"`
+	ClientID     string `form:"client_id" json:"client_id" binding:"max=100"`
+	ClientSecret string `form:"client_secret" json:"client_secret" binding:"max=200"`
+	Code         string `form:"code" json:"code" binding:"required_if=GrantType authorization_code,max=200"`
+	RedirectURI  string `form:"redirect_uri" json:"redirect_uri" binding:"required_if=GrantType authorization_code,max=500"`
+	CodeVerifier string `form:"code_verifier" json:"code_verifier" binding:"required_if=GrantType authorization_code,max=128"`
+	RefreshToken string `form:"refresh_token" json:"refresh_token" binding:"required_if=GrantType refresh_token,max=2000"`
+	Scope        string `form:"scope" json:"scope" binding:"max=2000"`
+}
+
+// Name ...
+func (f OAuthForm) Name(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the application name"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Name should be between 3 to 100 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// CreateClient ...
+func (f OAuthForm) CreateClient(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch {
+			case err.Field() == "Name":
+				return f.Name(err.Tag())
+			case strings.HasPrefix(err.StructNamespace(), "CreateOAuthClientForm.RedirectURIs"):
+				return "Redirect URIs should be up to 10 valid URLs"
+			case strings.HasPrefix(err.StructNamespace(), "CreateOAuthClientForm.Scopes"):
+				return "Please select up to 50 scopes such as invoice:read"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
+
+// Authorize ...
+func (f OAuthForm) Authorize(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "ResponseType":
+				return "The response type should be code"
+			case "ClientID":
+				return "Please provide the client ID"
+			case "RedirectURI":
+				return "Please provide a valid redirect URI"
+			case "Scope":
+				return "Please provide the requested scope"
+			case "State":
+				return "State should be less than 500 characters"
+			case "CodeChallenge", "CodeChallengeMethod":
+				return "Please provide a PKCE code challenge with the S256 method"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
+
+// Token returns the OAuth2 error code with its description
+func (f OAuthForm) Token(err error) (code string, description string) {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "GrantType":
+				if err.Tag() == "oneof" {
+					return "unsupported_grant_type", "The grant type should be authorization_code, client_credentials or refresh_token"
+				}
+				return "invalid_request", "Please provide the grant type"
+			case "Code":
+				return "invalid_request", "Please provide the authorization code"
+			case "RedirectURI":
+				return "invalid_request", "Please provide the redirect URI of the authorization request"
+			case "CodeVerifier":
+				return "invalid_request", "Please provide the PKCE code verifier of up to 128 characters"
+			case "RefreshToken":
+				return "invalid_request", "Please provide the refresh token"
+			}
+		}
+	}
+
+	return "invalid_request", "Invalid request"
+}
@@ -0,0 +1,37 @@
+package forms
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// OIDCForm ...
+type OIDCForm struct{}
+
+// OIDCCallbackForm ...
+// The query the provider redirects back with, Error is set instead of Code when the user refused
+type OIDCCallbackForm struct {
+	Code  string `form:"code" json:"code" binding:"required_without=Error,max=2000"`
+	State string `form:"state" json:"state" binding:"required,max=200"`
+	Error string `form:"error" json:"error" binding:"max=200"`
+}
+
+// Callback ...
+func (f OIDCForm) Callback(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Code":
+				return "The provider did not return an authorization code"
+			case "State":
+				return "The login state is missing, please try again"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -9,37 +9,123 @@ import (
 // OrderForm ...
 type OrderForm struct{}
 
+// OrderItemForm ...
+// UnitPrice is in the minor unit of the order currency (cents) and TaxRate in basis points (2000 = 20%),
+// VariantID is only set when a variant of the product is ordered.
+// The limits keep the largest order (100 items of 1000000 at 1000000000 with 100% tax, 2e17) within int64
+type OrderItemForm struct {
+	ProductID int64 `form:"product_id" json:"product_id" binding:"required,min=1"`
+	VariantID int64 `form:"variant_id" json:"variant_id" binding:"omitempty,min=1"`
+	Quantity  int64 `form:"quantity" json:"quantity" binding:"required,min=1,max=1000000"`
+	UnitPrice int64 `form:"unit_price" json:"unit_price" binding:"min=0,max=1000000000"`
+	TaxRate   int64 `form:"tax_rate" json:"tax_rate" binding:"min=0,max=10000"`
+}
+
 // CreateOrderForm ...
 type CreateOrderForm struct {
-	Title   string `form:"title" json:"title" binding:"required,min=3,max=100"`
-	Content string `form:"content" json:"content" binding:"required,min=3,max=1000"`
+	CustomerID int64           `form:"customer_id" json:"customer_id" binding:"required,min=1"`
+	Currency   string          `form:"currency" json:"currency" binding:"required,iso4217"`
+	Notes      string          `form:"notes" json:"notes" binding:"max=1000"`
+	Items      []OrderItemForm `form:"items" json:"items" binding:"required,min=1,max=100,dive"`
 }
 
-// Title ...
-func (f OrderForm) Title(tag string, errMsg ...string) (message string) {
+// OrderTransitionForm ...
+type OrderTransitionForm struct {
+	Status string `form:"status" json:"status" binding:"required,oneof=draft placed paid fulfilled cancelled refunded"`
+	Reason string `form:"reason" json:"reason" binding:"max=500"`
+}
+
+// CustomerID ...
+func (f OrderForm) CustomerID(tag string, errMsg ...string) (message string) {
 	switch tag {
-	case "required":
+	case "required", "min":
 		if len(errMsg) == 0 {
-			return "Please enter the order title"
+			return "Please select the order customer"
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Title should be between 3 to 100 characters"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Content ...
-func (f OrderForm) Content(tag string, errMsg ...string) (message string) {
+// Currency ...
+func (f OrderForm) Currency(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the order content"
+			return "Please enter the order currency"
+		}
+		return errMsg[0]
+	case "iso4217":
+		return "Currency should be a valid ISO 4217 code (e.g. USD)"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Notes ...
+func (f OrderForm) Notes(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Notes should be less than 1000 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Items ...
+func (f OrderForm) Items(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min":
+		if len(errMsg) == 0 {
+			return "Please add at least one item to the order"
 		}
 		return errMsg[0]
+	case "max":
+		return "An order can have up to 100 items"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// ProductID ...
+func (f OrderForm) ProductID(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min":
+		if len(errMsg) == 0 {
+			return "Please select the item product"
+		}
+		return errMsg[0]
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Quantity ...
+func (f OrderForm) Quantity(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min", "max":
+		return "Item quantity should be between 1 and 1000000"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// UnitPrice ...
+func (f OrderForm) UnitPrice(tag string, errMsg ...string) (message string) {
+	switch tag {
 	case "min", "max":
-		return "Content should be between 3 to 1000 characters"
+		return "Item unit price should be between 0 and 1000000000 in minor units"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// TaxRate ...
+func (f OrderForm) TaxRate(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "min", "max":
+		return "Item tax rate should be between 0 and 10000 basis points"
 	default:
 		return "Something went wrong, please try again later"
 	}
@@ -55,11 +141,25 @@ func (f OrderForm) Create(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+			switch err.Field() {
+			case "CustomerID":
+				return f.CustomerID(err.Tag())
+			case "Currency":
+				return f.Currency(err.Tag())
+			case "Notes":
+				return f.Notes(err.Tag())
+			case "Items":
+				return f.Items(err.Tag())
+			case "ProductID":
+				return f.ProductID(err.Tag())
+			case "VariantID":
+				return "Please select a valid item variant"
+			case "Quantity":
+				return f.Quantity(err.Tag())
+			case "UnitPrice":
+				return f.UnitPrice(err.Tag())
+			case "TaxRate":
+				return f.TaxRate(err.Tag())
 			}
 		}
 
@@ -72,6 +172,36 @@ func (f OrderForm) Create(err error) string {
 
 // Update ...
 func (f OrderForm) Update(err error) string {
+	return f.Create(err)
+}
+
+// Status ...
+func (f OrderForm) Status(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the new order status"
+		}
+		return errMsg[0]
+	case "oneof":
+		return "Status should be one of draft, placed, paid, fulfilled, cancelled or refunded"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Reason ...
+func (f OrderForm) Reason(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Reason should be less than 500 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Transition ...
+func (f OrderForm) Transition(err error) string {
 	switch err.(type) {
 	case validator.ValidationErrors:
 
@@ -80,11 +210,11 @@ func (f OrderForm) Update(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
+			if err.Field() == "Status" {
+				return f.Status(err.Tag())
 			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+			if err.Field() == "Reason" {
+				return f.Reason(err.Tag())
 			}
 		}
 
@@ -0,0 +1,99 @@
+package forms
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// OrganizationForm ...
+type OrganizationForm struct{}
+
+// CreateOrganizationForm ...
+type CreateOrganizationForm struct {
+	Name string `form:"name" json:"name" binding:"required,min=2,max=100"`
+}
+
+// InviteMemberForm ...
+// Role defaults to member, the owners are the ones who created the organization
+type InviteMemberForm struct {
+	Email string `form:"email" json:"email" binding:"required,email,max=254"`
+	Role  string `form:"role" json:"role" binding:"omitempty,oneof=admin member billing read-only"`
+}
+
+// Name ...
+func (f OrganizationForm) Name(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the organization name"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Name should be between 2 to 100 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Email ...
+func (f OrganizationForm) Email(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the email to invite"
+		}
+		return errMsg[0]
+	case "email", "max":
+		return "Please enter a valid email"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Create ...
+func (f OrganizationForm) Create(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			if err.Field() == "Name" {
+				return f.Name(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
+
+// Invite ...
+func (f OrganizationForm) Invite(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Email":
+				return f.Email(err.Tag())
+			case "Role":
+				return "Role should be one of admin, member, billing or read-only"
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -0,0 +1,221 @@
+package forms
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tags of the password rules, turned into messages by UserForm.Password ...
+const (
+	PasswordTooShort = "passwordMin"
+	PasswordTooLong  = "passwordMax"
+	PasswordClasses  = "passwordClasses"
+	PasswordIdentity = "passwordIdentity"
+	PasswordBreached = "passwordBreached"
+	PasswordTooLarge = "passwordBytes"
+)
+
+// Defaults of the policy, unless PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH and PASSWORD_CHARACTER_CLASSES are set ...
+const (
+	defaultPasswordMinLength = 10
+	defaultPasswordMaxLength = 64
+	defaultPasswordClasses   = 2
+)
+
+// bcryptMaxBytes is the length bcrypt hashes, the bytes after it would be ignored: a password of 64 characters
+// may well be longer with the accented letters and symbols, whatever PASSWORD_MAX_LENGTH allows
+const bcryptMaxBytes = 72
+
+// PasswordError ...
+// The rule the password breaks, Param is the number of the rule (e.g. the minimum length)
+type PasswordError struct {
+	Tag   string
+	Param string
+}
+
+func (e *PasswordError) Error() string {
+	return UserForm{}.Password(e.Tag, e.Param)
+}
+
+// PasswordRule ...
+// A rule of the policy, identity is the email and the name of the user
+type PasswordRule interface {
+	Check(password string, identity []string) *PasswordError
+}
+
+// PasswordPolicy ...
+// The rules a new password follows at the registration, the change and the reset, in the order they are checked
+type PasswordPolicy []PasswordRule
+
+var passwordPolicy PasswordPolicy
+
+// SetPasswordPolicy replaces the policy configured by the environment
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicy = policy
+}
+
+// GetPasswordPolicy returns the policy set with SetPasswordPolicy, or the one of the environment
+func GetPasswordPolicy() PasswordPolicy {
+	if passwordPolicy != nil {
+		return passwordPolicy
+	}
+
+	policy := PasswordPolicy{
+		MinLength(envInt("PASSWORD_MIN_LENGTH", defaultPasswordMinLength)),
+		MaxLength(envInt("PASSWORD_MAX_LENGTH", defaultPasswordMaxLength)),
+		CharacterClasses(envInt("PASSWORD_CHARACTER_CLASSES", defaultPasswordClasses)),
+		NoIdentity{},
+	}
+	if os.Getenv("PASSWORD_BREACHED_CHECK") != "FALSE" {
+		policy = append(policy, NotBreached{Source: BundledBreachedPasswords{}})
+	}
+	return policy
+}
+
+// Check returns the first rule the password breaks as a *PasswordError, nil when it follows the policy.
+// The passwords bcrypt would truncate are refused before any rule
+func (p PasswordPolicy) Check(password string, identity ...string) error {
+	if len(password) > bcryptMaxBytes {
+		return &PasswordError{Tag: PasswordTooLarge, Param: strconv.Itoa(bcryptMaxBytes)}
+	}
+
+	for _, rule := range p {
+		if err := rule.Check(password, identity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envInt returns the positive integer of the variable or the default
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// MinLength is the minimum of characters
+type MinLength int
+
+// Check ...
+func (r MinLength) Check(password string, identity []string) *PasswordError {
+	if utf8.RuneCountInString(password) < int(r) {
+		return &PasswordError{Tag: PasswordTooShort, Param: strconv.Itoa(int(r))}
+	}
+	return nil
+}
+
+// MaxLength is the maximum of characters
+type MaxLength int
+
+// Check ...
+func (r MaxLength) Check(password string, identity []string) *PasswordError {
+	if utf8.RuneCountInString(password) > int(r) {
+		return &PasswordError{Tag: PasswordTooLong, Param: strconv.Itoa(int(r))}
+	}
+	return nil
+}
+
+// CharacterClasses is how many of lowercase letters, uppercase letters, digits and symbols are mixed
+type CharacterClasses int
+
+// Check ...
+func (r CharacterClasses) Check(password string, identity []string) *PasswordError {
+	var lower, upper, digit, symbol int
+	for _, char := range password {
+		switch {
+		case unicode.IsLower(char):
+			lower = 1
+		case unicode.IsUpper(char):
+			upper = 1
+		case unicode.IsLetter(char):
+			//The scripts without case count as lowercase
+			lower = 1
+		case unicode.IsDigit(char):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	if lower+upper+digit+symbol < int(r) {
+		return &PasswordError{Tag: PasswordClasses, Param: strconv.Itoa(int(r))}
+	}
+	return nil
+}
+
+// NoIdentity rejects the passwords containing the name of the user, their email or a word of them
+type NoIdentity struct{}
+
+// identityMinWord is the shortest word of the name or the email looked for in the password
+const identityMinWord = 4
+
+// Check ...
+func (r NoIdentity) Check(password string, identity []string) *PasswordError {
+	password = strings.ToLower(password)
+
+	for _, value := range identity {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if at := strings.Index(value, "@"); at >= 0 {
+			value = value[:at]
+		}
+
+		words := strings.FieldsFunc(value, func(char rune) bool {
+			return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+		})
+		words = append(words, value)
+
+		for _, word := range words {
+			if utf8.RuneCountInString(word) >= identityMinWord && strings.Contains(password, word) {
+				return &PasswordError{Tag: PasswordIdentity}
+			}
+		}
+	}
+	return nil
+}
+
+// BreachedPasswords ...
+// Returns the SHA-1 suffixes (35 uppercase hex characters) of the breached passwords under a prefix of 5,
+// the k-anonymity range of the Pwned Passwords API: only the prefix ever has to leave the check
+type BreachedPasswords interface {
+	Range(prefix string) ([]string, error)
+}
+
+// NotBreached rejects the passwords known from the data breaches
+type NotBreached struct {
+	Source BreachedPasswords
+}
+
+// Check ...
+// A source that can not be reached does not block the user, the other rules still apply
+func (r NotBreached) Check(password string, identity []string) *PasswordError {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := r.Source.Range(hash[:5])
+	if err != nil {
+		return nil
+	}
+
+	for _, suffix := range suffixes {
+		if suffix == hash[5:] {
+			return &PasswordError{Tag: PasswordBreached}
+		}
+	}
+	return nil
+}
+
+// BundledBreachedPasswords ...
+// The offline source of the hashes of the most common breached passwords, listed in breached_passwords.go
+type BundledBreachedPasswords struct{}
+
+// Range ...
+func (s BundledBreachedPasswords) Range(prefix string) ([]string, error) {
+	return breachedPasswords[prefix], nil
+}
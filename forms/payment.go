@@ -0,0 +1,99 @@
+package forms
+
+import (
+	"encoding/json"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PaymentForm ...
+type PaymentForm struct{}
+
+// CreatePaymentForm ...
+// Amount is in the minor unit of the invoice currency, a negative amount records a refund.
+// PaidOn defaults to today when empty
+type CreatePaymentForm struct {
+	Amount    int64  `form:"amount" json:"amount" binding:"required,min=-100000000000,max=100000000000"`
+	Method    string `form:"method" json:"method" binding:"required,oneof=cash bank_transfer card cheque other"`
+	Reference string `form:"reference" json:"reference" binding:"max=200"`
+	PaidOn    string `form:"paid_on" json:"paid_on" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// Amount ...
+func (f PaymentForm) Amount(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the payment amount, use a negative amount for a refund"
+		}
+		return errMsg[0]
+	case "min", "max":
+		return "Payment amount is out of range"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Method ...
+func (f PaymentForm) Method(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the payment method"
+		}
+		return errMsg[0]
+	case "oneof":
+		return "Method should be one of cash, bank_transfer, card, cheque or other"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Reference ...
+func (f PaymentForm) Reference(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Reference should be less than 200 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// PaidOn ...
+func (f PaymentForm) PaidOn(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "datetime":
+		return "Payment date should be formatted as YYYY-MM-DD"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Create ...
+func (f PaymentForm) Create(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			return "Something went wrong, please try again later"
+		}
+
+		for _, err := range err.(validator.ValidationErrors) {
+			switch err.Field() {
+			case "Amount":
+				return f.Amount(err.Tag())
+			case "Method":
+				return f.Method(err.Tag())
+			case "Reference":
+				return f.Reference(err.Tag())
+			case "PaidOn":
+				return f.PaidOn(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -9,69 +9,133 @@ import (
 // ProductForm ...
 type ProductForm struct{}
 
+// ProductVariantForm ...
+// Price overrides the product price when set
+type ProductVariantForm struct {
+	SKU    string `form:"sku" json:"sku" binding:"required,sku"`
+	Size   string `form:"size" json:"size" binding:"max=50"`
+	Color  string `form:"color" json:"color" binding:"max=50"`
+	Price  *int64 `form:"price" json:"price" binding:"omitempty,min=0,max=100000000000"`
+	Active *bool  `form:"active" json:"active"`
+}
+
 // CreateProductForm ...
+// Price is in the minor unit of the currency (cents), Active defaults to true,
+// TrackInventory reserves the stock of the product when it is ordered
+// and the variants replace the current ones on update
 type CreateProductForm struct {
-	Title   string `form:"title" json:"title" binding:"required,min=3,max=100"`
-	Content string `form:"content" json:"content" binding:"required,min=3,max=1000"`
+	SKU            string                 `form:"sku" json:"sku" binding:"required,sku"`
+	Name           string                 `form:"name" json:"name" binding:"required,min=3,max=200"`
+	Description    string                 `form:"description" json:"description" binding:"max=5000"`
+	Price          int64                  `form:"price" json:"price" binding:"min=0,max=100000000000"`
+	Currency       string                 `form:"currency" json:"currency" binding:"required,iso4217"`
+	Active         *bool                  `form:"active" json:"active"`
+	TrackInventory bool                   `form:"track_inventory" json:"track_inventory"`
+	Attributes     map[string]interface{} `form:"attributes" json:"attributes" binding:"max=50"`
+	Variants       []ProductVariantForm   `form:"variants" json:"variants" binding:"omitempty,max=100,dive"`
 }
 
-// Title ...
-func (f ProductForm) Title(tag string, errMsg ...string) (message string) {
+// SKU ...
+func (f ProductForm) SKU(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the product title"
+			return "Please enter the product SKU"
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Title should be between 3 to 100 characters"
+	case "sku":
+		return "SKU should be up to 64 letters, digits, dots, dashes or underscores"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Content ...
-func (f ProductForm) Content(tag string, errMsg ...string) (message string) {
+// Name ...
+func (f ProductForm) Name(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the product content"
+			return "Please enter the product name"
 		}
 		return errMsg[0]
 	case "min", "max":
-		return "Content should be between 3 to 1000 characters"
+		return "Name should be between 3 to 200 characters"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Create ...
-func (f ProductForm) Create(err error) string {
-	switch err.(type) {
-	case validator.ValidationErrors:
+// Description ...
+func (f ProductForm) Description(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Description should be less than 5000 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
 
-		if _, ok := err.(*json.UnmarshalTypeError); ok {
-			return "Something went wrong, please try again later"
-		}
+// Price ...
+func (f ProductForm) Price(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "min", "max":
+		return "Price should be a positive amount in minor units"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
 
-		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
-			}
+// Currency ...
+func (f ProductForm) Currency(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the product currency"
 		}
+		return errMsg[0]
+	case "iso4217":
+		return "Currency should be a valid ISO 4217 code (e.g. USD)"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
 
+// Attributes ...
+func (f ProductForm) Attributes(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "A product can have up to 50 attributes"
 	default:
-		return "Invalid request"
+		return "Something went wrong, please try again later"
 	}
+}
 
-	return "Something went wrong, please try again later"
+// Variants ...
+func (f ProductForm) Variants(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "A product can have up to 100 variants"
+	default:
+		return "Something went wrong, please try again later"
+	}
 }
 
-// Update ...
-func (f ProductForm) Update(err error) string {
+// Variant ...
+func (f ProductForm) Variant(field, tag string, errMsg ...string) (message string) {
+	switch field {
+	case "SKU":
+		return "Every variant needs its own SKU of up to 64 letters, digits, dots, dashes or underscores"
+	case "Size", "Color":
+		return "Variant size and color should be less than 50 characters"
+	case "Price":
+		return "Variant price should be a positive amount in minor units"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Create ...
+func (f ProductForm) Create(err error) string {
 	switch err.(type) {
 	case validator.ValidationErrors:
 
@@ -80,11 +144,26 @@ func (f ProductForm) Update(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
+			//The variants errors are reported as CreateProductForm.Variants[0].SKU
+			if err.StructNamespace() != "CreateProductForm."+err.StructField() {
+				return f.Variant(err.Field(), err.Tag())
 			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+
+			switch err.Field() {
+			case "SKU":
+				return f.SKU(err.Tag())
+			case "Name":
+				return f.Name(err.Tag())
+			case "Description":
+				return f.Description(err.Tag())
+			case "Price":
+				return f.Price(err.Tag())
+			case "Currency":
+				return f.Currency(err.Tag())
+			case "Attributes":
+				return f.Attributes(err.Tag())
+			case "Variants":
+				return f.Variants(err.Tag())
 			}
 		}
 
@@ -94,3 +173,8 @@ func (f ProductForm) Update(err error) string {
 
 	return "Something went wrong, please try again later"
 }
+
+// Update ...
+func (f ProductForm) Update(err error) string {
+	return f.Create(err)
+}
@@ -9,37 +9,161 @@ import (
 // ShipmentForm ...
 type ShipmentForm struct{}
 
+// ShipmentAddressForm ...
+type ShipmentAddressForm struct {
+	Name       string `form:"name" json:"name" binding:"required,max=200"`
+	Line1      string `form:"line1" json:"line1" binding:"required,max=200"`
+	Line2      string `form:"line2" json:"line2" binding:"max=200"`
+	City       string `form:"city" json:"city" binding:"required,max=100"`
+	Region     string `form:"region" json:"region" binding:"max=100"`
+	PostalCode string `form:"postal_code" json:"postal_code" binding:"max=20"`
+	Country    string `form:"country" json:"country" binding:"required,iso3166_1_alpha2"`
+}
+
+// ParcelForm ...
+// Weight is in grams and the dimensions in millimeters
+type ParcelForm struct {
+	Weight int64 `form:"weight" json:"weight" binding:"required,min=1,max=1000000"`
+	Length int64 `form:"length" json:"length" binding:"required,min=1,max=10000"`
+	Width  int64 `form:"width" json:"width" binding:"required,min=1,max=10000"`
+	Height int64 `form:"height" json:"height" binding:"required,min=1,max=10000"`
+}
+
+// ShipmentItemForm ...
+type ShipmentItemForm struct {
+	OrderItemID int64 `form:"order_item_id" json:"order_item_id" binding:"required,min=1"`
+	Quantity    int64 `form:"quantity" json:"quantity" binding:"required,min=1,max=1000000"`
+}
+
 // CreateShipmentForm ...
+// Items defaults to the quantities of the order lines that are not on a shipment yet
 type CreateShipmentForm struct {
-	Title   string `form:"title" json:"title" binding:"required,min=3,max=100"`
-	Content string `form:"content" json:"content" binding:"required,min=3,max=1000"`
+	OrderID        int64               `form:"order_id" json:"order_id" binding:"required,min=1"`
+	Carrier        string              `form:"carrier" json:"carrier" binding:"required,max=50"`
+	ServiceLevel   string              `form:"service_level" json:"service_level" binding:"max=50"`
+	TrackingNumber string              `form:"tracking_number" json:"tracking_number" binding:"max=100"`
+	Destination    ShipmentAddressForm `form:"destination" json:"destination"`
+	Parcels        []ParcelForm        `form:"parcels" json:"parcels" binding:"required,min=1,max=50,dive"`
+	Items          []ShipmentItemForm  `form:"items" json:"items" binding:"omitempty,max=100,dive"`
+}
+
+// UpdateShipmentForm ...
+// Items keeps the current shipment items when empty
+type UpdateShipmentForm struct {
+	Carrier        string              `form:"carrier" json:"carrier" binding:"required,max=50"`
+	ServiceLevel   string              `form:"service_level" json:"service_level" binding:"max=50"`
+	TrackingNumber string              `form:"tracking_number" json:"tracking_number" binding:"max=100"`
+	Destination    ShipmentAddressForm `form:"destination" json:"destination"`
+	Parcels        []ParcelForm        `form:"parcels" json:"parcels" binding:"required,min=1,max=50,dive"`
+	Items          []ShipmentItemForm  `form:"items" json:"items" binding:"omitempty,max=100,dive"`
 }
 
-// Title ...
-func (f ShipmentForm) Title(tag string, errMsg ...string) (message string) {
+// ShipmentEventForm ...
+// OccurredAt is an RFC 3339 time and defaults to now when empty
+type ShipmentEventForm struct {
+	Status      string `form:"status" json:"status" binding:"required,oneof=pending in_transit out_for_delivery delivered exception returned"`
+	Description string `form:"description" json:"description" binding:"max=500"`
+	Location    string `form:"location" json:"location" binding:"max=200"`
+	OccurredAt  string `form:"occurred_at" json:"occurred_at" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// OrderID ...
+func (f ShipmentForm) OrderID(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min":
+		if len(errMsg) == 0 {
+			return "Please select the order to ship"
+		}
+		return errMsg[0]
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Carrier ...
+func (f ShipmentForm) Carrier(tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the shipment title"
+			return "Please enter the shipment carrier"
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Title should be between 3 to 100 characters"
+	case "max":
+		return "Carrier should be less than 50 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// ServiceLevel ...
+func (f ShipmentForm) ServiceLevel(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Service level should be less than 50 characters"
 	default:
 		return "Something went wrong, please try again later"
 	}
 }
 
-// Content ...
-func (f ShipmentForm) Content(tag string, errMsg ...string) (message string) {
+// TrackingNumber ...
+func (f ShipmentForm) TrackingNumber(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "Tracking number should be less than 100 characters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Address ...
+func (f ShipmentForm) Address(field, tag string, errMsg ...string) (message string) {
 	switch tag {
 	case "required":
 		if len(errMsg) == 0 {
-			return "Please enter the shipment content"
+			return "Please enter the destination " + field
+		}
+		return errMsg[0]
+	case "max":
+		return "Destination " + field + " is too long"
+	case "iso3166_1_alpha2":
+		return "Destination country should be a valid ISO 3166 code (e.g. US)"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Parcels ...
+func (f ShipmentForm) Parcels(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min":
+		if len(errMsg) == 0 {
+			return "Please add at least one parcel to the shipment"
 		}
 		return errMsg[0]
-	case "min", "max":
-		return "Content should be between 3 to 1000 characters"
+	case "max":
+		return "A shipment can have up to 50 parcels"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Parcel ...
+func (f ShipmentForm) Parcel(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required", "min", "max":
+		return "Parcel weight should be between 1 and 1000000 grams and its dimensions between 1 and 10000 millimeters"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Items ...
+func (f ShipmentForm) Items(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "max":
+		return "A shipment can have up to 100 items"
+	case "required", "min":
+		return "Please select the order item and a quantity of at least 1 for every shipment item"
 	default:
 		return "Something went wrong, please try again later"
 	}
@@ -55,11 +179,33 @@ func (f ShipmentForm) Create(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+			switch err.Field() {
+			case "OrderID":
+				return f.OrderID(err.Tag())
+			case "Carrier":
+				return f.Carrier(err.Tag())
+			case "ServiceLevel":
+				return f.ServiceLevel(err.Tag())
+			case "TrackingNumber":
+				return f.TrackingNumber(err.Tag())
+			case "Name":
+				return f.Address("name", err.Tag())
+			case "Line1", "Line2":
+				return f.Address("address", err.Tag())
+			case "City":
+				return f.Address("city", err.Tag())
+			case "Region":
+				return f.Address("region", err.Tag())
+			case "PostalCode":
+				return f.Address("postal code", err.Tag())
+			case "Country":
+				return f.Address("country", err.Tag())
+			case "Parcels":
+				return f.Parcels(err.Tag())
+			case "Weight", "Length", "Width", "Height":
+				return f.Parcel(err.Tag())
+			case "Items", "OrderItemID", "Quantity":
+				return f.Items(err.Tag())
 			}
 		}
 
@@ -72,6 +218,26 @@ func (f ShipmentForm) Create(err error) string {
 
 // Update ...
 func (f ShipmentForm) Update(err error) string {
+	return f.Create(err)
+}
+
+// Status ...
+func (f ShipmentForm) Status(tag string, errMsg ...string) (message string) {
+	switch tag {
+	case "required":
+		if len(errMsg) == 0 {
+			return "Please enter the tracking status"
+		}
+		return errMsg[0]
+	case "oneof":
+		return "Status should be one of pending, in_transit, out_for_delivery, delivered, exception or returned"
+	default:
+		return "Something went wrong, please try again later"
+	}
+}
+
+// Event ...
+func (f ShipmentForm) Event(err error) string {
 	switch err.(type) {
 	case validator.ValidationErrors:
 
@@ -80,11 +246,15 @@ func (f ShipmentForm) Update(err error) string {
 		}
 
 		for _, err := range err.(validator.ValidationErrors) {
-			if err.Field() == "Title" {
-				return f.Title(err.Tag())
-			}
-			if err.Field() == "Content" {
-				return f.Content(err.Tag())
+			switch err.Field() {
+			case "Status":
+				return f.Status(err.Tag())
+			case "Description":
+				return "Description should be less than 500 characters"
+			case "Location":
+				return "Location should be less than 200 characters"
+			case "OccurredAt":
+				return "Occurred at should be an RFC 3339 time (e.g. 2006-01-02T15:04:05Z)"
 			}
 		}
 
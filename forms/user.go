@@ -2,6 +2,7 @@ package forms
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -12,14 +13,20 @@ type UserForm struct{}
 //LoginForm ...
 type LoginForm struct {
 	Email    string `form:"email" json:"email" binding:"required,email"`
-	Password string `form:"password" json:"password" binding:"required,min=3,max=50"`
+	Password string `form:"password" json:"password" binding:"required,min=3,max=72"`
 }
 
 //RegisterForm ...
 type RegisterForm struct {
 	Name     string `form:"name" json:"name" binding:"required,min=3,max=20,fullName"` //fullName rule is in validator.go
 	Email    string `form:"email" json:"email" binding:"required,email"`
-	Password string `form:"password" json:"password" binding:"required,min=3,max=50"`
+	Password string `form:"password" json:"password" binding:"required,max=72"` //the password policy is checked by the model, see password.go
+}
+
+//ChangePasswordForm ...
+type ChangePasswordForm struct {
+	CurrentPassword string `form:"current_password" json:"current_password" binding:"required,max=72"`
+	Password        string `form:"password" json:"password" binding:"required,max=72"`
 }
 
 //Name ...
@@ -55,14 +62,31 @@ func (f UserForm) Email(tag string, errMsg ...string) (message string) {
 }
 
 //Password ...
-func (f UserForm) Password(tag string) (message string) {
+//param is the number of the rule of the password policy, e.g. its minimum length
+func (f UserForm) Password(tag string, param ...string) (message string) {
+	if len(param) == 0 {
+		param = []string{""}
+	}
+
 	switch tag {
 	case "required":
 		return "Please enter your password"
 	case "min", "max":
-		return "Your password should be between 3 and 50 characters"
+		return "Your password should be between 3 and 72 characters"
 	case "eqfield":
 		return "Your passwords does not match"
+	case PasswordTooShort:
+		return fmt.Sprintf("Your password should be at least %s characters", param[0])
+	case PasswordTooLong:
+		return fmt.Sprintf("Your password should be at most %s characters", param[0])
+	case PasswordTooLarge:
+		return fmt.Sprintf("Your password is too long, it should be at most %s bytes (accented letters and symbols take several)", param[0])
+	case PasswordClasses:
+		return fmt.Sprintf("Your password should mix at least %s of lowercase letters, uppercase letters, digits and symbols", param[0])
+	case PasswordIdentity:
+		return "Your password should not contain your name or email"
+	case PasswordBreached:
+		return "This password appeared in a data breach, please choose another one"
 	default:
 		return "Something went wrong, please try again later"
 	}
@@ -122,3 +146,24 @@ func (f UserForm) Register(err error) string {
 
 	return "Something went wrong, please try again later"
 }
+
+//ChangePassword ...
+func (f UserForm) ChangePassword(err error) string {
+	switch err.(type) {
+	case validator.ValidationErrors:
+
+		for _, err := range err.(validator.ValidationErrors) {
+			if err.Field() == "CurrentPassword" && err.Tag() == "required" {
+				return "Please enter your current password"
+			}
+			if err.Field() == "CurrentPassword" || err.Field() == "Password" {
+				return f.Password(err.Tag())
+			}
+		}
+
+	default:
+		return "Invalid request"
+	}
+
+	return "Something went wrong, please try again later"
+}
@@ -49,6 +49,9 @@ func (v *DefaultValidator) lazyinit() {
 
 		//Custom rule for user full name
 		v.validate.RegisterValidation("fullName", ValidateFullName)
+
+		//Custom rule for product and variant SKUs
+		v.validate.RegisterValidation("sku", ValidateSKU)
 	})
 }
 
@@ -76,3 +79,11 @@ func ValidateFullName(fl validator.FieldLevel) bool {
 	matched, _ := regexp.Match(`^[^±!@£$%^&*_+§¡€#¢§¶•ªº«\\/<>?:;'"|=.,0123456789]{3,20}$`, []byte(name))
 	return matched
 }
+
+//skuPattern is a letter or digit followed by up to 63 letters, digits, dots, dashes or underscores
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+//ValidateSKU implements validator.Func
+func ValidateSKU(fl validator.FieldLevel) bool {
+	return skuPattern.MatchString(fl.Field().String())
+}
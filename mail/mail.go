@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Message ...
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends the emails of the application, set with SetMailer
+type Mailer interface {
+	Send(message Message) error
+}
+
+var mailer Mailer
+
+// Init ...
+// Starts the mailer of MAIL_DRIVER: "smtp" sends with the SMTP_* settings, "memory" keeps the emails in memory (e.g. for local development)
+func Init() {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "memory":
+		mailer = NewMemoryMailer()
+	default:
+		mailer = SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+	}
+}
+
+// SetMailer replaces the mailer, e.g. with a MemoryMailer in the tests
+func SetMailer(m Mailer) {
+	mailer = m
+}
+
+// GetMailer ...
+func GetMailer() Mailer {
+	return mailer
+}
+
+// SMTPMailer ...
+// Sends plain text emails through an SMTP server, with PLAIN authentication when Username is set
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send ...
+func (m SMTPMailer) Send(message Message) error {
+	if m.Host == "" {
+		return fmt.Errorf("the SMTP host is not configured")
+	}
+
+	//The headers are built from our own values, but a line break would still start a new header
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	body := "From: " + m.From + "\r\n" +
+		"To: " + message.To + "\r\n" +
+		"Subject: " + message.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n" +
+		"\r\n" + message.Body
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{message.To}, []byte(body))
+}
+
+// MemoryMailer ...
+// Keeps the sent emails instead of sending them
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer ...
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send ...
+func (m *MemoryMailer) Send(message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Last returns the last email sent to the address, false when there is none
+func (m *MemoryMailer) Last(to string) (message Message, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if strings.EqualFold(m.messages[i].To, to) {
+			return m.messages[i], true
+		}
+	}
+	return message, false
+}
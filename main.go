@@ -10,6 +10,7 @@ import (
 	"github.com/Massad/gin-boilerplate/controllers"
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/mail"
 	"github.com/gin-contrib/gzip"
 	uuid "github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -28,7 +29,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, UPDATE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "X-Requested-With, Content-Type, Origin, Authorization, Accept, Client-Security-Token, Accept-Encoding, x-access-token")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "X-Requested-With, Content-Type, Origin, Authorization, Accept, Client-Security-Token, Accept-Encoding, x-access-token, X-API-Key")
 		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
@@ -54,10 +55,24 @@ func RequestIDMiddleware() gin.HandlerFunc {
 var auth = new(controllers.AuthController)
 
 // TokenAuthMiddleware ...
-// JWT Authentication middleware attached to each request that needs to be authenitcated to validate the access_token in the header
+// JWT Authentication middleware attached to each request that needs to be authenitcated to validate the access_token in the header,
+// the server-to-server requests send an API key in the X-API-Key header instead
 func TokenAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		auth.TokenValid(c)
+		if c.GetHeader("X-API-Key") != "" {
+			auth.APIKeyValid(c)
+		} else {
+			auth.TokenValid(c)
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission ...
+// Attached after TokenAuthMiddleware, aborts with 403 when the role of the user in the active organization is not granted the permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.Permission(c, permission)
 		c.Next()
 	}
 }
@@ -91,6 +106,9 @@ func main() {
 	//Example: db.GetRedis().Set(KEY, VALUE, at.Sub(now)).Err()
 	db.InitRedis(1)
 
+	//Start the mailer of the verification and password reset emails, MAIL_DRIVER=memory keeps them in memory instead of sending
+	mail.Init()
+
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	v1 := r.Group("/v1")
 	{
@@ -100,6 +118,27 @@ func main() {
 		v1.POST("/user/login", user.Login)
 		v1.POST("/user/register", user.Register)
 		v1.GET("/user/logout", user.Logout)
+		v1.PUT("/user/password", TokenAuthMiddleware(), user.ChangePassword)
+
+		/*** START SESSION ***/
+		session := new(controllers.SessionController)
+
+		v1.GET("/user/sessions", TokenAuthMiddleware(), session.All)
+		v1.DELETE("/user/sessions/:id", TokenAuthMiddleware(), session.Revoke)
+		v1.DELETE("/user/sessions", TokenAuthMiddleware(), session.RevokeAll)
+
+		/*** START AUDIT ***/
+		audit := new(controllers.AuditController)
+
+		v1.GET("/user/audit-log", TokenAuthMiddleware(), audit.All)
+
+		/*** START EMAIL ***/
+		email := new(controllers.EmailController)
+
+		v1.POST("/user/email/verification", email.RequestVerification)
+		v1.POST("/user/email/verify", email.Verify)
+		v1.POST("/user/password/forgot", email.ForgotPassword)
+		v1.POST("/user/password/reset", email.ResetPassword)
 
 		/*** START AUTH ***/
 		auth := new(controllers.AuthController)
@@ -107,23 +146,126 @@ func main() {
 		//Refresh the token when needed to generate new access_token and refresh_token for the user
 		v1.POST("/token/refresh", auth.Refresh)
 
+		/*** START OIDC ***/
+		oidc := new(controllers.OIDCController)
+
+		v1.GET("/oidc/providers", oidc.Providers)
+		v1.GET("/oidc/:provider/login", oidc.Login)
+		v1.GET("/oidc/:provider/callback", oidc.Callback)
+
+		/*** START MFA ***/
+		mfa := new(controllers.MFAController)
+
+		//Second step of the login for the users with two-factor authentication, with the mfa_token of the first one
+		v1.POST("/user/login/mfa", mfa.Login)
+		v1.POST("/user/mfa/enroll", TokenAuthMiddleware(), mfa.Enroll)
+		v1.POST("/user/mfa/activate", TokenAuthMiddleware(), mfa.Activate)
+		v1.POST("/user/mfa/disable", TokenAuthMiddleware(), mfa.Disable)
+
+		/*** START Organization ***/
+		organization := new(controllers.OrganizationController)
+
+		v1.POST("/organization", TokenAuthMiddleware(), organization.Create)
+		v1.GET("/organizations", TokenAuthMiddleware(), organization.All)
+		v1.GET("/organization/members", TokenAuthMiddleware(), organization.Members)
+		v1.POST("/organization/invitations", TokenAuthMiddleware(), RequirePermission("member:invite"), organization.Invite)
+		v1.POST("/organization/members/:id/unlock", TokenAuthMiddleware(), RequirePermission("member:unlock"), organization.Unlock)
+		v1.POST("/organization/:id/switch", TokenAuthMiddleware(), organization.Switch)
+		v1.GET("/invitations", TokenAuthMiddleware(), organization.Invitations)
+		v1.POST("/invitation/:id/accept", TokenAuthMiddleware(), organization.Accept)
+
+		/*** START API Key ***/
+		apiKey := new(controllers.APIKeyController)
+
+		v1.POST("/api-key", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.Create)
+		v1.GET("/api-keys", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.All)
+		v1.DELETE("/api-key/:id", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.Revoke)
+
+		/*** START OAuth ***/
+		oauth := new(controllers.OAuthController)
+
+		v1.POST("/oauth/client", TokenAuthMiddleware(), RequirePermission("oauth_client:manage"), oauth.CreateClient)
+		v1.GET("/oauth/clients", TokenAuthMiddleware(), RequirePermission("oauth_client:manage"), oauth.Clients)
+		v1.GET("/oauth/authorize", TokenAuthMiddleware(), oauth.Consent)
+		v1.POST("/oauth/authorize", TokenAuthMiddleware(), oauth.Authorize)
+		v1.POST("/oauth/token", oauth.Token)
+
 		/*** START Article ***/
 		article := new(controllers.ArticleController)
 
-		v1.POST("/article", TokenAuthMiddleware(), article.Create)
-		v1.GET("/articles", TokenAuthMiddleware(), article.All)
-		v1.GET("/article/:id", TokenAuthMiddleware(), article.One)
-		v1.PUT("/article/:id", TokenAuthMiddleware(), article.Update)
-		v1.DELETE("/article/:id", TokenAuthMiddleware(), article.Delete)
+		v1.POST("/article", TokenAuthMiddleware(), RequirePermission("article:write"), article.Create)
+		v1.GET("/articles", TokenAuthMiddleware(), RequirePermission("article:read"), article.All)
+		v1.GET("/article/:id", TokenAuthMiddleware(), RequirePermission("article:read"), article.One)
+		v1.PUT("/article/:id", TokenAuthMiddleware(), RequirePermission("article:write"), article.Update)
+		v1.DELETE("/article/:id", TokenAuthMiddleware(), RequirePermission("article:write"), article.Delete)
 
 		/*** START Product ***/
-		product := new(controllers.ArticleController)
-
-		v1.POST("/product", TokenAuthMiddleware(), product.Create)
-		v1.GET("/product", TokenAuthMiddleware(), product.All)
-		v1.GET("/product/:id", TokenAuthMiddleware(), product.One)
-		v1.PUT("/product/:id", TokenAuthMiddleware(), product.Update)
-		v1.DELETE("/product/:id", TokenAuthMiddleware(), product.Delete)
+		product := new(controllers.ProductController)
+
+		v1.POST("/product", TokenAuthMiddleware(), RequirePermission("product:write"), product.Create)
+		//The list stays at /product for the existing clients, /products matches the other lists
+		v1.GET("/product", TokenAuthMiddleware(), RequirePermission("product:read"), product.All)
+		v1.GET("/products", TokenAuthMiddleware(), RequirePermission("product:read"), product.All)
+		v1.GET("/product/:id", TokenAuthMiddleware(), RequirePermission("product:read"), product.One)
+		v1.PUT("/product/:id", TokenAuthMiddleware(), RequirePermission("product:write"), product.Update)
+		v1.DELETE("/product/:id", TokenAuthMiddleware(), RequirePermission("product:write"), product.Delete)
+
+		/*** START Customer ***/
+		customer := new(controllers.CustomerController)
+
+		v1.POST("/customer", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Create)
+		v1.GET("/customers", TokenAuthMiddleware(), RequirePermission("customer:read"), customer.All)
+		v1.GET("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:read"), customer.One)
+		v1.PUT("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Update)
+		v1.DELETE("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Delete)
+
+		/*** START Order ***/
+		order := new(controllers.OrderController)
+
+		v1.POST("/order", TokenAuthMiddleware(), RequirePermission("order:write"), order.Create)
+		v1.GET("/orders", TokenAuthMiddleware(), RequirePermission("order:read"), order.All)
+		v1.GET("/order/:id", TokenAuthMiddleware(), RequirePermission("order:read"), order.One)
+		v1.PUT("/order/:id", TokenAuthMiddleware(), RequirePermission("order:write"), order.Update)
+		v1.DELETE("/order/:id", TokenAuthMiddleware(), RequirePermission("order:write"), order.Delete)
+		v1.POST("/order/:id/transition", TokenAuthMiddleware(), RequirePermission("order:write"), order.Transition)
+		v1.GET("/order/:id/history", TokenAuthMiddleware(), RequirePermission("order:read"), order.History)
+
+		/*** START Invoice ***/
+		invoice := new(controllers.InvoiceController)
+
+		v1.POST("/order/:id/invoice", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Generate)
+		v1.GET("/invoices", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.All)
+		v1.GET("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.One)
+		v1.PUT("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Update)
+		v1.DELETE("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Delete)
+		v1.POST("/invoice/:id/issue", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Issue)
+		v1.POST("/invoice/:id/void", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Void)
+		v1.GET("/invoice/:id/pdf", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.PDF)
+
+		/*** START Payment ***/
+		payment := new(controllers.PaymentController)
+
+		v1.POST("/invoice/:id/payments", TokenAuthMiddleware(), RequirePermission("payment:write"), payment.Create)
+		v1.GET("/invoice/:id/payments", TokenAuthMiddleware(), RequirePermission("payment:read"), payment.All)
+		v1.GET("/invoice/:id/balance", TokenAuthMiddleware(), RequirePermission("payment:read"), payment.Balance)
+
+		/*** START Shipment ***/
+		shipment := new(controllers.ShipmentController)
+
+		v1.POST("/shipment", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Create)
+		v1.GET("/shipments", TokenAuthMiddleware(), RequirePermission("shipment:read"), shipment.All)
+		v1.GET("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:read"), shipment.One)
+		v1.PUT("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Update)
+		v1.DELETE("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Delete)
+		v1.POST("/shipment/:id/events", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Event)
+
+		/*** START Inventory ***/
+		inventory := new(controllers.InventoryController)
+
+		v1.POST("/warehouse", TokenAuthMiddleware(), RequirePermission("inventory:write"), inventory.CreateWarehouse)
+		v1.GET("/warehouses", TokenAuthMiddleware(), RequirePermission("inventory:read"), inventory.Warehouses)
+		v1.GET("/product/:id/stock", TokenAuthMiddleware(), RequirePermission("inventory:read"), inventory.Stock)
+		v1.POST("/product/:id/stock", TokenAuthMiddleware(), RequirePermission("inventory:write"), inventory.Move)
 	}
 
 	r.LoadHTMLGlob("./public/html/*")
@@ -141,6 +283,9 @@ func main() {
 		c.HTML(404, "404.html", gin.H{})
 	})
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+
+	//The public keys of the access tokens, for the other services to verify them
+	r.GET("/.well-known/jwks.json", auth.JWKS)
 	port := os.Getenv("PORT")
 
 	log.Printf("\n\n PORT: %s \n ENV: %s \n SSL: %s \n Version: %s \n\n", port, os.Getenv("ENV"), os.Getenv("SSL"), os.Getenv("API_VERSION"))
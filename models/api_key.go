@@ -0,0 +1,162 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+)
+
+// PermissionManageAPIKeys is never granted to an API key, a leaked key could otherwise mint new ones
+const PermissionManageAPIKeys = "api_key:manage"
+
+// apiKeyPrefix starts every key so that they are easy to spot in logs and secret scanners
+const apiKeyPrefix = "gbk_"
+
+// apiKeyUsedInterval is how often the last use of a key is written, not on every request
+const apiKeyUsedInterval = 60
+
+// ErrInvalidAPIKey ...
+var ErrInvalidAPIKey = errors.New("the API key is invalid, expired or revoked")
+
+// ErrScopeNotGranted ...
+var ErrScopeNotGranted = errors.New("the scopes can not exceed the permissions your role is granted")
+
+// ErrExpiryInPast ...
+var ErrExpiryInPast = errors.New("the expiry of the API key should be in the future")
+
+// APIKey ...
+// The key itself is only returned once on creation (Key), the database only keeps its SHA-256 hash
+// and Prefix to tell the keys apart
+type APIKey struct {
+	ID         int64          `db:"id, primarykey, autoincrement" json:"id"`
+	Name       string         `db:"name" json:"name"`
+	Prefix     string         `db:"prefix" json:"prefix"`
+	Key        string         `db:"-" json:"key,omitempty"`
+	Scopes     pq.StringArray `db:"scopes" json:"scopes"`
+	ExpiresAt  *int64         `db:"expires_at" json:"expires_at"`
+	LastUsedAt *int64         `db:"last_used_at" json:"last_used_at"`
+	RevokedAt  *int64         `db:"revoked_at" json:"revoked_at"`
+	UpdatedAt  int64          `db:"updated_at" json:"updated_at"`
+	CreatedAt  int64          `db:"created_at" json:"created_at"`
+	User       *JSONRaw       `db:"user" json:"user"`
+}
+
+// APIKeyDetails is what a valid key authenticates as
+type APIKeyDetails struct {
+	ID             int64
+	OrganizationID int64
+	UserID         int64
+	Scopes         pq.StringArray
+}
+
+const apiKeyColumns = "k.id, k.name, k.prefix, k.scopes, k.expires_at, k.last_used_at, k.revoked_at, k.updated_at, k.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user"
+
+// APIKeyModel ...
+type APIKeyModel struct{}
+
+// hashSecret ...
+// The API keys and client secrets are random, a plain SHA-256 is enough to keep them unusable from a database dump
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomSecret returns size random bytes hex encoded after the prefix
+func randomSecret(prefix string, size int) (secret string, err error) {
+	buf := make([]byte, size)
+	if _, err = rand.Read(buf); err != nil {
+		return secret, err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// Create creates a key acting as the user in the organization, its scopes can not exceed the role of the user
+func (m APIKeyModel) Create(orgID, userID int64, role string, form forms.CreateAPIKeyForm) (apiKey APIKey, err error) {
+	if form.ExpiresAt != 0 && form.ExpiresAt <= time.Now().Unix() {
+		return apiKey, ErrExpiryInPast
+	}
+
+	for _, scope := range form.Scopes {
+		granted, err := permissionModel.Can(role, scope)
+		if err != nil {
+			return apiKey, err
+		}
+		if !granted || scope == PermissionManageAPIKeys {
+			return apiKey, ErrScopeNotGranted
+		}
+	}
+
+	key, err := randomSecret(apiKeyPrefix, 24)
+	if err != nil {
+		return apiKey, err
+	}
+
+	scopes := pq.StringArray{}
+	if form.Scopes != nil {
+		scopes = pq.StringArray(form.Scopes)
+	}
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		var id int64
+		err = tx.QueryRow("INSERT INTO public.api_key(organization_id, user_id, name, prefix, key_hash, scopes, expires_at) VALUES($1, $2, $3, $4, $5, $6, NULLIF($7, 0)) RETURNING id", orgID, userID, form.Name, key[:len(apiKeyPrefix)+8], hashSecret(key), scopes, form.ExpiresAt).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err = m.one(tx, orgID, id)
+		return err
+	})
+
+	apiKey.Key = key
+	return apiKey, err
+}
+
+// one ...
+func (m APIKeyModel) one(exec gorp.SqlExecutor, orgID, id int64) (apiKey APIKey, err error) {
+	err = exec.SelectOne(&apiKey, "SELECT "+apiKeyColumns+" FROM public.api_key k LEFT JOIN public.user u ON k.user_id = u.id WHERE k.organization_id=$1 AND k.id=$2 LIMIT 1", orgID, id)
+	return apiKey, err
+}
+
+// All lists the keys of the organization, the revoked ones included
+func (m APIKeyModel) All(orgID int64) (apiKeys []APIKey, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&apiKeys, "SELECT "+apiKeyColumns+" FROM public.api_key k LEFT JOIN public.user u ON k.user_id = u.id WHERE k.organization_id=$1 ORDER BY k.id DESC", orgID)
+		return err
+	})
+	return apiKeys, err
+}
+
+// Revoke revokes the key at once, it is kept in the list with its revocation time
+func (m APIKeyModel) Revoke(orgID, id int64) (apiKey APIKey, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Exec("UPDATE public.api_key SET revoked_at=COALESCE(revoked_at, extract(epoch from now())) WHERE organization_id=$1 AND id=$2", orgID, id)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err = m.one(tx, orgID, id)
+		return err
+	})
+	return apiKey, err
+}
+
+// Authenticate returns what the key acts as, ErrInvalidAPIKey when it is unknown, expired or revoked.
+// The organization is unknown until the key is found, so the lookup runs outside of a tenant transaction
+func (m APIKeyModel) Authenticate(key string) (details APIKeyDetails, err error) {
+	err = db.GetDB().QueryRow("SELECT id, organization_id, user_id, scopes FROM public.api_key WHERE key_hash=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > extract(epoch from now()))", hashSecret(key)).Scan(&details.ID, &details.OrganizationID, &details.UserID, &details.Scopes)
+	if err != nil {
+		return details, ErrInvalidAPIKey
+	}
+
+	//A failure to record the use should not fail the request
+	db.GetDB().Exec("UPDATE public.api_key SET last_used_at=extract(epoch from now()) WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < extract(epoch from now()) - $2)", details.ID, apiKeyUsedInterval)
+
+	return details, nil
+}
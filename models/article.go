@@ -5,6 +5,7 @@ import (
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
 )
 
 //Article ...
@@ -22,57 +23,70 @@ type Article struct {
 type ArticleModel struct{}
 
 //Create ...
-func (m ArticleModel) Create(userID int64, form forms.CreateArticleForm) (articleID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.article(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&articleID)
+func (m ArticleModel) Create(orgID, userID int64, form forms.CreateArticleForm) (articleID int64, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		err = tx.QueryRow("INSERT INTO public.article(organization_id, user_id, title, content) VALUES($1, $2, $3, $4) RETURNING id", orgID, userID, form.Title, form.Content).Scan(&articleID)
+		return err
+	})
 	return articleID, err
 }
 
 //One ...
-func (m ArticleModel) One(userID, id int64) (article Article, err error) {
-	err = db.GetDB().SelectOne(&article, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.article a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
+func (m ArticleModel) One(orgID, id int64) (article Article, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		err = tx.SelectOne(&article, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.article a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+		return err
+	})
 	return article, err
 }
 
 //All ...
-func (m ArticleModel) All(userID int64) (articles []DataList, err error) {
-	_, err = db.GetDB().Select(&articles, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.article AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.article a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
+func (m ArticleModel) All(orgID int64) (articles []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&articles, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.article AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.article a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 ORDER BY a.id DESC) d", orgID)
+		return err
+	})
 	return articles, err
 }
 
 //Update ...
-func (m ArticleModel) Update(userID int64, id int64, form forms.CreateArticleForm) (err error) {
+func (m ArticleModel) Update(orgID int64, id int64, form forms.CreateArticleForm) (err error) {
 	//METHOD 1
 	//Check the article by ID using this way
-	// _, err = m.One(userID, id)
+	// _, err = m.One(orgID, id)
 	// if err != nil {
 	// 	return err
 	// }
 
-	operation, err := db.GetDB().Exec("UPDATE public.article SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
-	if err != nil {
-		return err
-	}
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("UPDATE public.article SET title=$3, content=$4 WHERE id=$1 AND organization_id=$2", id, orgID, form.Title, form.Content)
+		if err != nil {
+			return err
+		}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
-	}
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("updated 0 records")
+		}
 
-	return err
+		return nil
+	})
 }
 
 //Delete ...
-func (m ArticleModel) Delete(userID, id int64) (err error) {
+func (m ArticleModel) Delete(orgID, id int64) (err error) {
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.article WHERE id=$1", id)
-	if err != nil {
-		return err
-	}
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.article WHERE id=$1 AND organization_id=$2", id, orgID)
+		if err != nil {
+			return err
+		}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
-	}
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
 
-	return err
+		return nil
+	})
 }
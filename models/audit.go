@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/Massad/gin-boilerplate/db"
+)
+
+// Audit events ...
+const (
+	AuditRefreshTokenReuse = "refresh_token.reuse"
+	AuditPasswordChanged   = "password.changed"
+)
+
+// auditLogLimit is how many of the latest events are listed
+const auditLogLimit = 50
+
+// AuditDetails are the free form details of an event stored as JSONB
+type AuditDetails map[string]interface{}
+
+// Value ...
+func (d AuditDetails) Value() (driver.Value, error) {
+	if d == nil {
+		d = AuditDetails{}
+	}
+	//Sent as a string, lib/pq would encode []byte as bytea
+	asBytes, err := json.Marshal(d)
+	return driver.Value(string(asBytes)), err
+}
+
+// Scan ...
+func (d *AuditDetails) Scan(src interface{}) error {
+	asBytes, ok := src.([]byte)
+	if !ok {
+		return errors.New("Scan source was not []bytes")
+	}
+	return json.Unmarshal(asBytes, d)
+}
+
+// AuditLog ...
+// A security event of a user, e.g. the reuse of a refresh token
+type AuditLog struct {
+	ID             int64        `db:"id, primarykey, autoincrement" json:"id"`
+	UserID         int64        `db:"user_id" json:"-"`
+	OrganizationID int64        `db:"organization_id" json:"organization_id,omitempty"`
+	Event          string       `db:"event" json:"event"`
+	IP             string       `db:"ip" json:"ip"`
+	UserAgent      string       `db:"user_agent" json:"user_agent"`
+	Details        AuditDetails `db:"details" json:"details"`
+	CreatedAt      int64        `db:"created_at" json:"created_at"`
+}
+
+// AuditModel ...
+type AuditModel struct{}
+
+var auditModel = new(AuditModel)
+
+// Record adds the event to the audit log, a failure is logged rather than failing the request it is about
+func (m AuditModel) Record(userID, orgID int64, event string, client SessionClient, details AuditDetails) {
+	_, err := db.GetDB().Exec("INSERT INTO public.audit_log(user_id, organization_id, event, ip, user_agent, details) VALUES($1, NULLIF($2, 0), $3, $4, $5, $6)", userID, orgID, event, client.IP, client.UserAgent, details)
+	if err != nil {
+		log.Printf("audit: could not record %s of user %d: %v", event, userID, err)
+	}
+}
+
+// All lists the latest events of the user
+func (m AuditModel) All(userID int64) (logs []AuditLog, err error) {
+	logs = []AuditLog{}
+	_, err = db.GetDB().Select(&logs, "SELECT id, user_id, COALESCE(organization_id, 0) AS organization_id, event, COALESCE(ip, '') AS ip, COALESCE(user_agent, '') AS user_agent, details, created_at FROM public.audit_log WHERE user_id=$1 ORDER BY created_at DESC, id DESC LIMIT $2", userID, auditLogLimit)
+	return logs, err
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -21,12 +22,20 @@ type TokenDetails struct {
 	RefreshUUID  string
 	AtExpires    int64
 	RtExpires    int64
+	SessionID    string
+	ClientID     string
+	Scopes       []string
 }
 
 //AccessDetails ...
+//ClientID and Scopes are only set for the tokens issued to an OAuth client
 type AccessDetails struct {
-	AccessUUID string
-	UserID     int64
+	AccessUUID     string
+	UserID         int64
+	OrganizationID int64
+	SessionID      string
+	ClientID       string
+	Scopes         []string
 }
 
 //Token ...
@@ -38,35 +47,45 @@ type Token struct {
 //AuthModel ...
 type AuthModel struct{}
 
+//ErrInvalidRefreshToken ...
+var ErrInvalidRefreshToken = errors.New("invalid authorization, please login again")
+
+//ErrRefreshTokenReused ...
+var ErrRefreshTokenReused = errors.New("this refresh token was already used, the session was ended to protect your account, please login again")
+
 //CreateToken ...
-func (m AuthModel) CreateToken(userID int64) (*TokenDetails, error) {
+//The tokens carry the organization the user works in, every request is scoped to it
+func (m AuthModel) CreateToken(userID, orgID int64) (*TokenDetails, error) {
+	return m.CreateScopedToken(userID, orgID, "", nil)
+}
+
+//CreateScopedToken ...
+//The tokens issued to an OAuth client also carry the client and the scopes granted to it (space separated, as in OAuth2)
+func (m AuthModel) CreateScopedToken(userID, orgID int64, clientID string, scopes []string) (*TokenDetails, error) {
+	return m.createToken(uuid.New().String(), userID, orgID, clientID, scopes)
+}
 
-	td := &TokenDetails{}
-	td.AtExpires = time.Now().Add(time.Minute * 15).Unix()
+//createToken ...
+//Every pair of tokens belongs to a session, the pairs of a session replace each other when refreshed
+func (m AuthModel) createToken(sessionID string, userID, orgID int64, clientID string, scopes []string) (*TokenDetails, error) {
+
+	now := time.Now()
+
+	td := &TokenDetails{SessionID: sessionID, ClientID: clientID, Scopes: scopes}
+	td.AtExpires = now.Add(accessTokenExpiration()).Unix()
 	td.AccessUUID = uuid.New().String()
 
-	td.RtExpires = time.Now().Add(time.Hour * 24 * 7).Unix()
+	td.RtExpires = now.Add(refreshTokenExpiration()).Unix()
 	td.RefreshUUID = uuid.New().String()
 
 	var err error
-	//Creating Access Token
-	atClaims := jwt.MapClaims{}
-	atClaims["authorized"] = true
-	atClaims["access_uuid"] = td.AccessUUID
-	atClaims["user_id"] = userID
-	atClaims["exp"] = td.AtExpires
-
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
-	td.AccessToken, err = at.SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+	//Creating Access Token, signed with the key ring so that the other services can verify it with the public keys
+	td.AccessToken, err = signAccessToken(newTokenClaims(td, tokenTypeAccess, userID, orgID, now))
 	if err != nil {
 		return nil, err
 	}
-	//Creating Refresh Token
-	rtClaims := jwt.MapClaims{}
-	rtClaims["refresh_uuid"] = td.RefreshUUID
-	rtClaims["user_id"] = userID
-	rtClaims["exp"] = td.RtExpires
-	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, rtClaims)
+	//Creating Refresh Token, only ever verified by us: it stays signed with the shared REFRESH_SECRET
+	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, newTokenClaims(td, tokenTypeRefresh, userID, orgID, now))
 	td.RefreshToken, err = rt.SignedString([]byte(os.Getenv("REFRESH_SECRET")))
 	if err != nil {
 		return nil, err
@@ -75,6 +94,7 @@ func (m AuthModel) CreateToken(userID int64) (*TokenDetails, error) {
 }
 
 //CreateAuth ...
+//Stores the tokens and ties them to their session, the previous pair of the session stops working
 func (m AuthModel) CreateAuth(userid int64, td *TokenDetails) error {
 	at := time.Unix(td.AtExpires, 0) //converting Unix to UTC(to Time object)
 	rt := time.Unix(td.RtExpires, 0)
@@ -88,7 +108,7 @@ func (m AuthModel) CreateAuth(userid int64, td *TokenDetails) error {
 	if errRefresh != nil {
 		return errRefresh
 	}
-	return nil
+	return sessionModel.attach(userid, td)
 }
 
 //ExtractToken ...
@@ -103,55 +123,93 @@ func (m AuthModel) ExtractToken(r *http.Request) string {
 }
 
 //VerifyToken ...
-func (m AuthModel) VerifyToken(r *http.Request) (*jwt.Token, error) {
-	tokenString := m.ExtractToken(r)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		//Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(os.Getenv("ACCESS_SECRET")), nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	return token, nil
+//Returns the claims of the access token of the request, the key is found by the kid of the token in the key ring
+func (m AuthModel) VerifyToken(r *http.Request) (*TokenClaims, error) {
+	return parseToken(m.ExtractToken(r), tokenTypeAccess, verificationKey)
 }
 
 //TokenValid ...
 func (m AuthModel) TokenValid(r *http.Request) error {
-	token, err := m.VerifyToken(r)
-	if err != nil {
-		return err
-	}
-	if _, ok := token.Claims.(jwt.Claims); !ok && !token.Valid {
-		return err
-	}
-	return nil
+	_, err := m.VerifyToken(r)
+	return err
 }
 
 //ExtractTokenMetadata ...
 func (m AuthModel) ExtractTokenMetadata(r *http.Request) (*AccessDetails, error) {
-	token, err := m.VerifyToken(r)
+	claims, err := m.VerifyToken(r)
 	if err != nil {
 		return nil, err
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
-		accessUUID, ok := claims["access_uuid"].(string)
-		if !ok {
-			return nil, err
-		}
-		userID, err := strconv.ParseInt(fmt.Sprintf("%.f", claims["user_id"]), 10, 64)
-		if err != nil {
-			return nil, err
+	return &AccessDetails{
+		AccessUUID:     claims.ID,
+		UserID:         claims.UserID(),
+		OrganizationID: claims.OrganizationID,
+		SessionID:      claims.SessionID,
+		ClientID:       claims.ClientID,
+		Scopes:         claims.Scopes,
+	}, nil
+}
+
+//refreshKey ...
+//The refresh tokens are only signed with REFRESH_SECRET
+func refreshKey(token *jwt.Token) (interface{}, error) {
+	//Make sure that the token method conform to "SigningMethodHMAC"
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return []byte(os.Getenv("REFRESH_SECRET")), nil
+}
+
+//Refresh ...
+//Deletes the refresh token and returns a new pair of tokens for the same user, organization, client and scopes.
+//clientID is the OAuth client the token was issued to, empty for the tokens of the login, client is the device of the request.
+//The refresh tokens of a session are a family: a token that was already rotated is presented again only when it was stolen,
+//so the whole session is revoked and the reuse recorded in the audit log
+func (m AuthModel) Refresh(refreshToken, clientID string, client SessionClient) (*TokenDetails, error) {
+	//verify the token, if there is an error the token must have expired
+	claims, err := parseToken(refreshToken, tokenTypeRefresh, refreshKey)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	//A token of an OAuth client is only refreshed by that client
+	if claims.ClientID != clientID {
+		return nil, ErrInvalidRefreshToken
+	}
+	refreshUUID, sessionID := claims.ID, claims.SessionID
+	userID, orgID := claims.UserID(), claims.OrganizationID
+
+	//Keep the active organization as long as the user is still a member
+	if _, err := organizationModel.Role(orgID, userID); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	//Delete the previous Refresh Token
+	deleted, err := m.DeleteAuth(refreshUUID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if deleted == 0 {
+		if sessionModel.rotated(sessionID, refreshUUID) {
+			if err = sessionModel.Revoke(userID, sessionID); err != nil {
+				return nil, err
+			}
+			auditModel.Record(userID, orgID, AuditRefreshTokenReuse, client, AuditDetails{"session_id": sessionID, "client_id": clientID})
+			return nil, ErrRefreshTokenReused
 		}
-		return &AccessDetails{
-			AccessUUID: accessUUID,
-			UserID:     userID,
-		}, nil
+		return nil, ErrInvalidRefreshToken
 	}
-	return nil, err
+
+	//Create new pairs of refresh and access tokens in the same session
+	td, err := m.createToken(sessionID, userID, orgID, clientID, claims.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	//save the tokens metadata to redis
+	err = m.CreateAuth(userID, td)
+	if err != nil {
+		return nil, err
+	}
+
+	return td, nil
 }
 
 //FetchAuth ...
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// Types of the tokens, a refresh token is never accepted as an access token and the reverse ...
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+// Lifetimes of the tokens, unless JWT_ACCESS_EXPIRATION and JWT_REFRESH_EXPIRATION are set ...
+const (
+	defaultAccessTokenExpiration  = 15 * time.Minute
+	defaultRefreshTokenExpiration = 7 * 24 * time.Hour
+)
+
+// defaultTokenIssuer is the issuer and audience of the tokens, unless JWT_ISSUER and JWT_AUDIENCE are set
+const defaultTokenIssuer = "gin-boilerplate"
+
+// tokenClockSkew is how far ahead of our clock the iat and nbf of a token issued by another instance may be
+const tokenClockSkew = 30 * time.Second
+
+// ErrInvalidTokenClaims ...
+var ErrInvalidTokenClaims = errors.New("the token claims are invalid")
+
+// accessTokenExpiration returns the duration of JWT_ACCESS_EXPIRATION (e.g. "15m")
+func accessTokenExpiration() time.Duration {
+	if expiration, err := time.ParseDuration(os.Getenv("JWT_ACCESS_EXPIRATION")); err == nil && expiration > 0 {
+		return expiration
+	}
+	return defaultAccessTokenExpiration
+}
+
+// refreshTokenExpiration returns the duration of JWT_REFRESH_EXPIRATION (e.g. "168h")
+func refreshTokenExpiration() time.Duration {
+	if expiration, err := time.ParseDuration(os.Getenv("JWT_REFRESH_EXPIRATION")); err == nil && expiration > 0 {
+		return expiration
+	}
+	return defaultRefreshTokenExpiration
+}
+
+// tokenIssuer returns JWT_ISSUER, the iss of the tokens
+func tokenIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return defaultTokenIssuer
+}
+
+// tokenAudience returns JWT_AUDIENCE, the aud of the tokens the other services check for
+func tokenAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+	return defaultTokenIssuer
+}
+
+// TokenClaims ...
+// The claims of the access and refresh tokens: sub is the user, jti the access or refresh UUID stored in Redis,
+// tenant the organization the user works in. ClientID and Scopes are only set for the tokens of an OAuth client
+type TokenClaims struct {
+	jwt.RegisteredClaims
+	Type           string   `json:"typ"`
+	OrganizationID int64    `json:"tenant"`
+	SessionID      string   `json:"sid"`
+	ClientID       string   `json:"client_id,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+}
+
+// newTokenClaims returns the claims of the token of the type in the pair of tokens
+func newTokenClaims(td *TokenDetails, tokenType string, userID, orgID int64, issuedAt time.Time) TokenClaims {
+	id, expires := td.AccessUUID, td.AtExpires
+	if tokenType == tokenTypeRefresh {
+		id, expires = td.RefreshUUID, td.RtExpires
+	}
+
+	return TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer(),
+			Subject:   strconv.FormatInt(userID, 10),
+			Audience:  jwt.ClaimStrings{tokenAudience()},
+			ExpiresAt: jwt.NewNumericDate(time.Unix(expires, 0)),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ID:        id,
+		},
+		Type:           tokenType,
+		OrganizationID: orgID,
+		SessionID:      td.SessionID,
+		ClientID:       td.ClientID,
+		Scopes:         td.Scopes,
+	}
+}
+
+// UserID returns the user of the sub claim
+func (c TokenClaims) UserID() int64 {
+	userID, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return userID
+}
+
+// Valid is called by the parser once the signature is verified, every claim is required
+func (c TokenClaims) Valid() error {
+	now := jwt.TimeFunc()
+
+	if !c.VerifyExpiresAt(now, true) {
+		return jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	}
+	if !c.VerifyIssuedAt(now.Add(tokenClockSkew), true) {
+		return jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	}
+	if !c.VerifyNotBefore(now.Add(tokenClockSkew), true) {
+		return jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	}
+	if c.Issuer != tokenIssuer() {
+		return jwt.NewValidationError("token has an unexpected issuer", jwt.ValidationErrorIssuer)
+	}
+	if !c.VerifyAudience(tokenAudience(), true) {
+		return jwt.NewValidationError("token has an unexpected audience", jwt.ValidationErrorAudience)
+	}
+	if c.ID == "" || c.SessionID == "" || c.UserID() <= 0 || c.OrganizationID <= 0 {
+		return jwt.NewValidationError(ErrInvalidTokenClaims.Error(), jwt.ValidationErrorClaimsInvalid)
+	}
+
+	return nil
+}
+
+// parseToken verifies the token with the key and returns its claims, as long as it is a token of the type
+func parseToken(tokenString, tokenType string, key jwt.Keyfunc) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, key)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.Type != tokenType {
+		return nil, ErrInvalidTokenClaims
+	}
+	return claims, nil
+}
@@ -2,77 +2,286 @@ package models
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 )
 
+// Customer address types ...
+const (
+	AddressBilling  = "billing"
+	AddressShipping = "shipping"
+)
+
+// ErrCustomerInUse ...
+var ErrCustomerInUse = errors.New("the customer has orders or invoices and can not be deleted")
+
+// ErrDefaultAddress ...
+var ErrDefaultAddress = errors.New("only one billing and one shipping address can be the default")
+
 // Customer ...
 type Customer struct {
-	ID        int64    `db:"id, primarykey, autoincrement" json:"id"`
-	UserID    int64    `db:"user_id" json:"-"`
-	Title     string   `db:"title" json:"title"`
-	Content   string   `db:"content" json:"content"`
-	UpdatedAt int64    `db:"updated_at" json:"updated_at"`
-	CreatedAt int64    `db:"created_at" json:"created_at"`
-	User      *JSONRaw `db:"user" json:"user"`
+	ID          int64             `db:"id, primarykey, autoincrement" json:"id"`
+	UserID      int64             `db:"user_id" json:"-"`
+	Name        string            `db:"name" json:"name"`
+	CompanyName string            `db:"company_name" json:"company_name"`
+	TaxID       string            `db:"tax_id" json:"tax_id"`
+	Email       string            `db:"email" json:"email"`
+	Phone       string            `db:"phone" json:"phone"`
+	Tags        pq.StringArray    `db:"tags" json:"tags"`
+	Notes       string            `db:"notes" json:"notes"`
+	UpdatedAt   int64             `db:"updated_at" json:"updated_at"`
+	CreatedAt   int64             `db:"created_at" json:"created_at"`
+	User        *JSONRaw          `db:"user" json:"user"`
+	Addresses   []CustomerAddress `db:"-" json:"addresses"`
+	Contacts    []CustomerContact `db:"-" json:"contacts"`
+}
+
+// CustomerAddress ...
+type CustomerAddress struct {
+	ID         int64  `db:"id, primarykey, autoincrement" json:"id"`
+	CustomerID int64  `db:"customer_id" json:"-"`
+	Type       string `db:"type" json:"type"`
+	Default    bool   `db:"is_default" json:"default"`
+	Line1      string `db:"line1" json:"line1"`
+	Line2      string `db:"line2" json:"line2"`
+	City       string `db:"city" json:"city"`
+	Region     string `db:"region" json:"region"`
+	PostalCode string `db:"postal_code" json:"postal_code"`
+	Country    string `db:"country" json:"country"`
+}
+
+// CustomerContact ...
+type CustomerContact struct {
+	ID         int64  `db:"id, primarykey, autoincrement" json:"id"`
+	CustomerID int64  `db:"customer_id" json:"-"`
+	Name       string `db:"name" json:"name"`
+	Role       string `db:"role" json:"role"`
+	Email      string `db:"email" json:"email"`
+	Phone      string `db:"phone" json:"phone"`
+}
+
+// String formats the address as printed on an envelope, one part per line
+func (a CustomerAddress) String() string {
+	lines := []string{a.Line1}
+	if a.Line2 != "" {
+		lines = append(lines, a.Line2)
+	}
+	lines = append(lines, strings.TrimSpace(a.PostalCode+" "+a.City))
+	if a.Region != "" {
+		lines = append(lines, a.Region)
+	}
+	return strings.Join(append(lines, a.Country), "\n")
+}
+
+// DefaultAddress returns the default address of the given type, if the customer has one
+func (c Customer) DefaultAddress(addressType string) (address CustomerAddress, ok bool) {
+	for _, address := range c.Addresses {
+		if address.Type == addressType && address.Default {
+			return address, true
+		}
+	}
+	return address, false
+}
+
+// BillingName is the company name, or the customer name for individuals
+func (c Customer) BillingName() string {
+	if c.CompanyName != "" {
+		return c.CompanyName
+	}
+	return c.Name
 }
 
 // CustomerModel ...
 type CustomerModel struct{}
 
-// Create ...
-func (m CustomerModel) Create(userID int64, form forms.CreateCustomerForm) (customerID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.customer(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&customerID)
-	return customerID, err
+var customerModel = new(CustomerModel)
+
+// customerColumns are the customer columns returned by One and All
+const customerColumns = "a.id, a.name, COALESCE(a.company_name, '') AS company_name, COALESCE(a.tax_id, '') AS tax_id, COALESCE(a.email, '') AS email, COALESCE(a.phone, '') AS phone, a.tags, COALESCE(a.notes, '') AS notes, a.updated_at, a.created_at"
+
+// customerAddressColumns are the address columns, ca is the address
+const customerAddressColumns = "ca.id, ca.type, ca.is_default, ca.line1, COALESCE(ca.line2, '') AS line2, ca.city, COALESCE(ca.region, '') AS region, COALESCE(ca.postal_code, '') AS postal_code, ca.country"
+
+// customerContactColumns are the contact columns, cc is the contact
+const customerContactColumns = "cc.id, cc.name, COALESCE(cc.role, '') AS role, COALESCE(cc.email, '') AS email, COALESCE(cc.phone, '') AS phone"
+
+// customerError turns the constraint violations into the customer errors
+func customerError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return ErrCustomerInUse
+	}
+	return err
+}
+
+// tags returns the form tags as a non null array
+func tags(form forms.CreateCustomerForm) pq.StringArray {
+	if form.Tags == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(form.Tags)
+}
+
+// saveChildren replaces the addresses and contacts of the customer,
+// the first address of each type becomes the default when none is marked
+func (m CustomerModel) saveChildren(exec gorp.SqlExecutor, customerID int64, form forms.CreateCustomerForm) (err error) {
+	defaults := map[string]int{}
+	for _, address := range form.Addresses {
+		if address.Default {
+			defaults[address.Type]++
+		}
+	}
+	for _, count := range defaults {
+		if count > 1 {
+			return ErrDefaultAddress
+		}
+	}
+
+	_, err = exec.Exec("DELETE FROM public.customer_address WHERE customer_id=$1", customerID)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range form.Addresses {
+		isDefault := address.Default
+		if defaults[address.Type] == 0 {
+			isDefault = true
+			defaults[address.Type] = 1
+		}
+
+		_, err = exec.Exec("INSERT INTO public.customer_address(customer_id, type, is_default, line1, line2, city, region, postal_code, country) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)", customerID, address.Type, isDefault, address.Line1, address.Line2, address.City, address.Region, address.PostalCode, address.Country)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = exec.Exec("DELETE FROM public.customer_contact WHERE customer_id=$1", customerID)
+	if err != nil {
+		return err
+	}
+
+	for _, contact := range form.Contacts {
+		_, err = exec.Exec("INSERT INTO public.customer_contact(customer_id, name, role, email, phone) VALUES($1, $2, $3, $4, $5)", customerID, contact.Name, contact.Role, contact.Email, contact.Phone)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Create inserts the customer with its addresses and contacts and returns it
+func (m CustomerModel) Create(orgID, userID int64, form forms.CreateCustomerForm) (customer Customer, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return customer, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow("INSERT INTO public.customer(organization_id, user_id, name, company_name, tax_id, email, phone, tags, notes) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id", orgID, userID, form.Name, form.CompanyName, form.TaxID, form.Email, form.Phone, tags(form), form.Notes).Scan(&customer.ID)
+	if err != nil {
+		return customer, err
+	}
+
+	err = m.saveChildren(tx, customer.ID, form)
+	if err != nil {
+		return customer, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return customer, err
+	}
+
+	return m.One(orgID, customer.ID)
+}
+
+// one ...
+func (m CustomerModel) one(exec gorp.SqlExecutor, orgID, id int64) (customer Customer, err error) {
+	err = exec.SelectOne(&customer, "SELECT "+customerColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.customer a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+	if err != nil {
+		return customer, err
+	}
+
+	_, err = exec.Select(&customer.Addresses, "SELECT "+customerAddressColumns+" FROM public.customer_address ca WHERE ca.customer_id=$1 ORDER BY ca.id", customer.ID)
+	if err != nil {
+		return customer, err
+	}
+
+	_, err = exec.Select(&customer.Contacts, "SELECT "+customerContactColumns+" FROM public.customer_contact cc WHERE cc.customer_id=$1 ORDER BY cc.id", customer.ID)
+	return customer, err
 }
 
 // One ...
-func (m CustomerModel) One(userID, id int64) (customer Customer, err error) {
-	err = db.GetDB().SelectOne(&customer, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.customer a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
+func (m CustomerModel) One(orgID, id int64) (customer Customer, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		customer, err = m.one(tx, orgID, id)
+		return err
+	})
 	return customer, err
 }
 
-// All ...
-func (m CustomerModel) All(userID int64) (customers []DataList, err error) {
-	_, err = db.GetDB().Select(&customers, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.customer AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.customer a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
+// All lists the customers, only the ones with the tag when it is not empty
+func (m CustomerModel) All(orgID int64, tag string) (customers []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&customers, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.customer AS a WHERE a.organization_id=$1 AND ($2 = '' OR $2 = ANY(a.tags)) LIMIT 1 ) n ) AS meta FROM ( SELECT "+customerColumns+", (SELECT COALESCE(json_agg(ca ORDER BY ca.id), '[]') FROM (SELECT "+customerAddressColumns+" FROM public.customer_address ca WHERE ca.customer_id = a.id) ca) AS addresses, (SELECT COALESCE(json_agg(cc ORDER BY cc.id), '[]') FROM (SELECT "+customerContactColumns+" FROM public.customer_contact cc WHERE cc.customer_id = a.id) cc) AS contacts, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.customer a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND ($2 = '' OR $2 = ANY(a.tags)) ORDER BY a.id DESC) d", orgID, tag)
+		return err
+	})
 	return customers, err
 }
 
-// Update ...
-func (m CustomerModel) Update(userID int64, id int64, form forms.CreateCustomerForm) (err error) {
-	//METHOD 1
-	//Check the customer by ID using this way
-	// _, err = m.One(userID, id)
-	// if err != nil {
-	// 	return err
-	// }
+// Update changes the customer and replaces its addresses and contacts
+func (m CustomerModel) Update(orgID int64, id int64, form forms.CreateCustomerForm) (err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	operation, err := db.GetDB().Exec("UPDATE public.customer SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
+	var customerID int64
+	err = tx.QueryRow("SELECT id FROM public.customer WHERE id=$1 AND organization_id=$2 FOR UPDATE", id, orgID).Scan(&customerID)
 	if err != nil {
 		return err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
+	_, err = tx.Exec("UPDATE public.customer SET name=$2, company_name=$3, tax_id=$4, email=$5, phone=$6, tags=$7, notes=$8 WHERE id=$1", id, form.Name, form.CompanyName, form.TaxID, form.Email, form.Phone, tags(form), form.Notes)
+	if err != nil {
+		return err
 	}
 
-	return err
+	err = m.saveChildren(tx, id, form)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Delete ...
-func (m CustomerModel) Delete(userID, id int64) (err error) {
+func (m CustomerModel) Delete(orgID, id int64) (err error) {
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.customer WHERE id=$1", id)
-	if err != nil {
-		return err
-	}
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.customer WHERE id=$1 AND organization_id=$2", id, orgID)
+		if err != nil {
+			return customerError(err)
+		}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
-	}
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
 
-	return err
+		return nil
+	})
 }
@@ -0,0 +1,340 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/mail"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Purposes of the email tokens, a token is only redeemed for the purpose it was issued for ...
+const (
+	EmailTokenVerify = "verify"
+	EmailTokenReset  = "reset"
+)
+
+// emailTokenExpirations is how long the link of each email is valid
+var emailTokenExpirations = map[string]time.Duration{
+	EmailTokenVerify: 24 * time.Hour,
+	EmailTokenReset:  time.Hour,
+}
+
+// Defaults of the throttle of the requested emails, unless EMAIL_REQUEST_MAX_ATTEMPTS and EMAIL_REQUEST_IP_MAX_ATTEMPTS are set ...
+const (
+	defaultEmailRequestMaxAttempts   = 3
+	defaultEmailRequestIPMaxAttempts = 20
+)
+
+// emailRequestWindow is how long the requests are counted after the first one
+const emailRequestWindow = time.Hour
+
+// ErrInvalidEmailToken ...
+var ErrInvalidEmailToken = errors.New("the link is invalid, expired or was already used, please request a new one")
+
+// ErrEmailUnverified ...
+var ErrEmailUnverified = errors.New("please verify your email first, we sent you a link to confirm it")
+
+// EmailThrottledError ...
+// Too many emails were requested for the address or from the IP, the next request is accepted once RetryAfter passed
+type EmailThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *EmailThrottledError) Error() string {
+	return "too many emails requested, please try again later"
+}
+
+// Seconds returns RetryAfter rounded up, as sent in the Retry-After header
+func (e *EmailThrottledError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// EmailModel ...
+type EmailModel struct{}
+
+var emailModel = new(EmailModel)
+
+// emailDeliveries are the requested emails still being sent in the background
+var emailDeliveries sync.WaitGroup
+
+// EmailVerificationRequired tells whether the users have to verify their email before they can log in (EMAIL_VERIFICATION_REQUIRED=TRUE)
+func EmailVerificationRequired() bool {
+	return strings.EqualFold(os.Getenv("EMAIL_VERIFICATION_REQUIRED"), "true")
+}
+
+// signEmailToken returns the signature of the token for the purpose, with EMAIL_TOKEN_SECRET or else ACCESS_SECRET
+func signEmailToken(purpose, random string) string {
+	secret := os.Getenv("EMAIL_TOKEN_SECRET")
+	if secret == "" {
+		secret = os.Getenv("ACCESS_SECRET")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose + "." + random))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issueToken returns a signed token for the user, stored in Redis until it expires or is redeemed.
+// Only the last token of each purpose is valid, a new one replaces the link of the previous email
+func (m EmailModel) issueToken(purpose string, userID int64) (token string, err error) {
+	random, err := randomSecret("", 32)
+	if err != nil {
+		return token, err
+	}
+	token = random + "." + signEmailToken(purpose, random)
+
+	expiration := emailTokenExpirations[purpose]
+	userKey := fmt.Sprintf("email:%s:user:%d", purpose, userID)
+
+	previous, _ := db.GetRedis().Get(userKey).Result()
+
+	pipe := db.GetRedis().TxPipeline()
+	if previous != "" {
+		pipe.Del("email:" + purpose + ":" + previous)
+	}
+	pipe.Set("email:"+purpose+":"+hashSecret(token), userID, expiration)
+	pipe.Set(userKey, hashSecret(token), expiration)
+	_, err = pipe.Exec()
+
+	return token, err
+}
+
+// tokenKey returns the key of the token once its signature is checked
+func (m EmailModel) tokenKey(purpose, token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(signEmailToken(purpose, parts[0]))) {
+		return "", ErrInvalidEmailToken
+	}
+	return "email:" + purpose + ":" + hashSecret(token), nil
+}
+
+// peekToken returns the user of the token without redeeming it
+func (m EmailModel) peekToken(purpose, token string) (userID int64, err error) {
+	key, err := m.tokenKey(purpose, token)
+	if err != nil {
+		return userID, err
+	}
+
+	userID, err = db.GetRedis().Get(key).Int64()
+	if err != nil {
+		return userID, ErrInvalidEmailToken
+	}
+	return userID, nil
+}
+
+// redeemToken returns the user of the token and deletes it, a token is only redeemed once
+func (m EmailModel) redeemToken(purpose, token string) (userID int64, err error) {
+	key, err := m.tokenKey(purpose, token)
+	if err != nil {
+		return userID, err
+	}
+
+	//Read and delete at once, a token is only redeemed a single time
+	pipe := db.GetRedis().TxPipeline()
+	get := pipe.Get(key)
+	pipe.Del(key)
+	if _, err = pipe.Exec(); err != nil {
+		return userID, ErrInvalidEmailToken
+	}
+
+	userID, err = strconv.ParseInt(get.Val(), 10, 64)
+	if err != nil {
+		return userID, ErrInvalidEmailToken
+	}
+
+	db.GetRedis().Del(fmt.Sprintf("email:%s:user:%d", purpose, userID))
+
+	return userID, nil
+}
+
+// link returns the URL of the frontend page for the token, at APP_URL
+func (m EmailModel) link(path, token string) string {
+	appURL := os.Getenv("APP_URL")
+	if appURL == "" {
+		appURL = "http://localhost"
+	}
+	return strings.TrimSuffix(appURL, "/") + path + "?token=" + token
+}
+
+// Verified tells whether the user confirmed their email
+func (m EmailModel) Verified(userID int64) (bool, error) {
+	count, err := db.GetDB().SelectInt("SELECT count(id) FROM public.user WHERE id=$1 AND verified_at IS NOT NULL", userID)
+	return count > 0, err
+}
+
+// SendVerification emails the user a link to confirm their email, nothing is sent once it is verified
+func (m EmailModel) SendVerification(user User) error {
+	verified, err := m.Verified(user.ID)
+	if err != nil || verified {
+		return err
+	}
+
+	token, err := m.issueToken(EmailTokenVerify, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return mail.GetMailer().Send(mail.Message{
+		To:      user.Email,
+		Subject: "Please verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email by opening the link below, it is valid for 24 hours:\n\n%s\n\nIf you did not create an account, you can ignore this email.\n",
+			user.Name, m.link("/verify-email", token)),
+	})
+}
+
+// throttle counts the request of an email for the purpose, by the hash of the address whether it is registered or not
+// and by the IP, and returns an EmailThrottledError once either asked for too many
+func (m EmailModel) throttle(purpose, email, ip string) error {
+	limits := map[string]int{
+		"email-request:" + purpose + ":email:" + hashSecret(strings.ToLower(strings.TrimSpace(email))): envInt("EMAIL_REQUEST_MAX_ATTEMPTS", defaultEmailRequestMaxAttempts),
+		"email-request:" + purpose + ":ip:" + hashSecret(ip):                                           envInt("EMAIL_REQUEST_IP_MAX_ATTEMPTS", defaultEmailRequestIPMaxAttempts),
+	}
+
+	var retryAfter time.Duration
+	for key, maxAttempts := range limits {
+		pipe := db.GetRedis().TxPipeline()
+		incr := pipe.Incr(key)
+		ttl := pipe.PTTL(key)
+		if _, err := pipe.Exec(); err != nil {
+			return err
+		}
+
+		//The window starts with the first request, the next ones do not extend it
+		window := ttl.Val()
+		if window < 0 {
+			window = emailRequestWindow
+			if err := db.GetRedis().Expire(key, window).Err(); err != nil {
+				return err
+			}
+		}
+
+		if int(incr.Val()) > maxAttempts && window > retryAfter {
+			retryAfter = window
+		}
+	}
+
+	if retryAfter > 0 {
+		return &EmailThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// deliver looks the user up and sends the email in the background, so that the response takes as long
+// whether the email is registered or not and does not wait for the mail server
+func (m EmailModel) deliver(email string, send func(user User) error) {
+	emailDeliveries.Add(1)
+
+	go func() {
+		defer emailDeliveries.Done()
+
+		var user User
+		err := db.GetDB().SelectOne(&user, "SELECT id, email, name FROM public.user WHERE email=LOWER($1) LIMIT 1", email)
+		if err == nil {
+			err = send(user)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err)
+		}
+	}()
+}
+
+// Wait blocks until the emails requested so far are sent
+func (m EmailModel) Wait() {
+	emailDeliveries.Wait()
+}
+
+// RequestVerification sends the verification email again, the response is the same whether the email is registered or not
+func (m EmailModel) RequestVerification(form forms.EmailRequestForm, ip string) error {
+	if err := m.throttle(EmailTokenVerify, form.Email, ip); err != nil {
+		return err
+	}
+
+	m.deliver(form.Email, m.SendVerification)
+	return nil
+}
+
+// Verify confirms the email of the user of the token
+func (m EmailModel) Verify(form forms.EmailTokenForm) error {
+	userID, err := m.redeemToken(EmailTokenVerify, form.Token)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetDB().Exec("UPDATE public.user SET verified_at=extract(epoch from now()) WHERE id=$1 AND verified_at IS NULL", userID)
+	return err
+}
+
+// sendReset emails the user a link to reset their password
+func (m EmailModel) sendReset(user User) error {
+	token, err := m.issueToken(EmailTokenReset, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return mail.GetMailer().Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nYou can choose a new password by opening the link below, it is valid for 1 hour:\n\n%s\n\nIf you did not ask to reset your password, you can ignore this email.\n",
+			user.Name, m.link("/reset-password", token)),
+	})
+}
+
+// ForgotPassword emails the user a link to reset their password, the response is the same whether the email is registered or not
+func (m EmailModel) ForgotPassword(form forms.EmailRequestForm, ip string) error {
+	if err := m.throttle(EmailTokenReset, form.Email, ip); err != nil {
+		return err
+	}
+
+	m.deliver(form.Email, m.sendReset)
+	return nil
+}
+
+// ResetPassword sets the new password of the user of the token and logs them out everywhere,
+// the email is verified with it since the link was received there
+func (m EmailModel) ResetPassword(form forms.ResetPasswordForm) error {
+	userID, err := m.peekToken(EmailTokenReset, form.Token)
+	if err != nil {
+		return err
+	}
+
+	user, err := userModel.One(userID)
+	if err != nil {
+		return err
+	}
+
+	//Checked before the token is redeemed, the user tries another password with the same link
+	if err = forms.GetPasswordPolicy().Check(form.Password, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	userID, err = m.redeemToken(EmailTokenReset, form.Token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(form.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetDB().Exec("UPDATE public.user SET password=$2, verified_at=COALESCE(verified_at, extract(epoch from now())) WHERE id=$1", userID, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	return sessionModel.RevokeAll(userID)
+}
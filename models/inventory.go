@@ -0,0 +1,386 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+)
+
+// Stock movement types ...
+const (
+	StockReceipt     = "receipt"
+	StockAdjustment  = "adjustment"
+	StockReservation = "reservation"
+	StockRelease     = "release"
+	StockShipment    = "shipment"
+)
+
+// stockLevelColumns sums the movements (m) into the stock held by the warehouse and the part of it reserved by orders,
+// shipping releases the reservation and takes the goods out of the warehouse
+const stockLevelColumns = "COALESCE(SUM(m.quantity) FILTER (WHERE m.type IN ('receipt', 'adjustment', 'shipment')), 0) AS on_hand, COALESCE(SUM(m.quantity) FILTER (WHERE m.type IN ('reservation', 'release')), 0) AS reserved"
+
+// ErrInsufficientStock ...
+var ErrInsufficientStock = errors.New("not enough stock available")
+
+// ErrWarehouseNotFound ...
+var ErrWarehouseNotFound = errors.New("warehouse not found")
+
+// ErrWarehouseCodeTaken ...
+var ErrWarehouseCodeTaken = errors.New("the warehouse code is already used")
+
+// ErrNegativeReceipt ...
+var ErrNegativeReceipt = errors.New("a receipt should have a positive quantity, record an adjustment to remove stock")
+
+// Warehouse ...
+type Warehouse struct {
+	ID        int64  `db:"id, primarykey, autoincrement" json:"id"`
+	UserID    int64  `db:"user_id" json:"-"`
+	Code      string `db:"code" json:"code"`
+	Name      string `db:"name" json:"name"`
+	UpdatedAt int64  `db:"updated_at" json:"updated_at"`
+	CreatedAt int64  `db:"created_at" json:"created_at"`
+}
+
+// StockLevel is the stock of the product, or one of its variants, in a warehouse
+type StockLevel struct {
+	WarehouseID int64  `db:"warehouse_id" json:"warehouse_id"`
+	Warehouse   string `db:"warehouse" json:"warehouse"`
+	VariantID   *int64 `db:"variant_id" json:"variant_id"`
+	OnHand      int64  `db:"on_hand" json:"on_hand"`
+	Reserved    int64  `db:"reserved" json:"reserved"`
+	Available   int64  `db:"available" json:"available"`
+}
+
+// Stock ...
+// OnHand is what the warehouses hold, Reserved the part of it promised to placed orders
+// and Available what can still be ordered, all computed from the same movements
+type Stock struct {
+	ProductID int64        `json:"product_id"`
+	OnHand    int64        `json:"on_hand"`
+	Reserved  int64        `json:"reserved"`
+	Available int64        `json:"available"`
+	Levels    []StockLevel `json:"levels"`
+}
+
+// stockMovement is a row of the stock ledger, the quantity is negative for what leaves
+type stockMovement struct {
+	ProductID   int64
+	VariantID   *int64
+	WarehouseID int64
+	OrderID     *int64
+	ShipmentID  *int64
+	Type        string
+	Quantity    int64
+	Note        string
+}
+
+// stockLine is a quantity of a product or variant, in a warehouse when it comes from the movements
+type stockLine struct {
+	ProductID   int64  `db:"product_id"`
+	VariantID   *int64 `db:"variant_id"`
+	WarehouseID int64  `db:"warehouse_id"`
+	SKU         string `db:"sku"`
+	Quantity    int64  `db:"quantity"`
+}
+
+// sameVariant tells if both are the product itself (nil) or the same variant
+func sameVariant(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// InventoryModel ...
+type InventoryModel struct{}
+
+var inventoryModel = new(InventoryModel)
+
+// CreateWarehouse ...
+func (m InventoryModel) CreateWarehouse(orgID, userID int64, form forms.CreateWarehouseForm) (warehouse Warehouse, err error) {
+	warehouse = Warehouse{UserID: userID, Code: form.Code, Name: form.Name}
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		err = tx.QueryRow("INSERT INTO public.warehouse(organization_id, user_id, code, name) VALUES($1, $2, $3, $4) RETURNING id, updated_at, created_at", orgID, userID, form.Code, form.Name).Scan(&warehouse.ID, &warehouse.UpdatedAt, &warehouse.CreatedAt)
+		return err
+	})
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return warehouse, ErrWarehouseCodeTaken
+	}
+
+	return warehouse, err
+}
+
+// Warehouses ...
+func (m InventoryModel) Warehouses(orgID int64) (warehouses []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&warehouses, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.warehouse AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.code, a.name, a.updated_at, a.created_at FROM public.warehouse a WHERE a.organization_id=$1 ORDER BY a.id) d", orgID)
+		return err
+	})
+	return warehouses, err
+}
+
+// levels returns the stock of the product per variant and warehouse, the warehouses in the order they were created
+func (m InventoryModel) levels(exec gorp.SqlExecutor, productID int64) (levels []StockLevel, err error) {
+	_, err = exec.Select(&levels, "SELECT l.warehouse_id, w.code AS warehouse, l.variant_id, l.on_hand, l.reserved, l.on_hand - l.reserved AS available FROM (SELECT m.warehouse_id, m.variant_id, "+stockLevelColumns+" FROM public.stock_movement m WHERE m.product_id=$1 GROUP BY m.warehouse_id, m.variant_id) l INNER JOIN public.warehouse w ON l.warehouse_id = w.id ORDER BY l.variant_id NULLS FIRST, l.warehouse_id", productID)
+	return levels, err
+}
+
+// lockProducts serializes the stock changes of the products until the transaction ends,
+// always in the same order so that two orders sharing products can not deadlock
+func (m InventoryModel) lockProducts(exec gorp.SqlExecutor, lines []stockLine) (err error) {
+	productIDs := []int64{}
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+
+	var locked []int64
+	_, err = exec.Select(&locked, "SELECT id FROM public.product WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(productIDs))
+	return err
+}
+
+// move appends the movement to the stock ledger of the organization, userID is who recorded it
+func (m InventoryModel) move(exec gorp.SqlExecutor, orgID, userID int64, movement stockMovement) (err error) {
+	_, err = exec.Exec("INSERT INTO public.stock_movement(organization_id, user_id, product_id, variant_id, warehouse_id, order_id, shipment_id, type, quantity, note) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)", orgID, userID, movement.ProductID, movement.VariantID, movement.WarehouseID, movement.OrderID, movement.ShipmentID, movement.Type, movement.Quantity, movement.Note)
+	return err
+}
+
+// reservations returns what the order still holds per product, variant and warehouse
+func (m InventoryModel) reservations(exec gorp.SqlExecutor, orderID int64) (reservations []stockLine, err error) {
+	_, err = exec.Select(&reservations, "SELECT m.product_id, m.variant_id, m.warehouse_id, SUM(m.quantity) AS quantity FROM public.stock_movement m WHERE m.order_id=$1 AND m.type IN ('reservation', 'release') GROUP BY m.product_id, m.variant_id, m.warehouse_id HAVING SUM(m.quantity) > 0 ORDER BY m.product_id, m.warehouse_id", orderID)
+	return reservations, err
+}
+
+// reserve sets aside the stock of the tracked products of the order, taking it from the warehouses in order,
+// and fails with ErrInsufficientStock when they do not hold enough of a product or variant
+func (m InventoryModel) reserve(exec gorp.SqlExecutor, orgID, userID, orderID int64) (err error) {
+	var lines []stockLine
+	_, err = exec.Select(&lines, "SELECT i.product_id, i.variant_id, COALESCE(v.sku, p.sku) AS sku, SUM(i.quantity) AS quantity FROM public.order_item i INNER JOIN public.product p ON i.product_id = p.id LEFT JOIN public.product_variant v ON i.variant_id = v.id WHERE i.order_id=$1 AND p.track_inventory GROUP BY i.product_id, i.variant_id, v.sku, p.sku ORDER BY i.product_id", orderID)
+	if err != nil || len(lines) == 0 {
+		return err
+	}
+
+	err = m.lockProducts(exec, lines)
+	if err != nil {
+		return err
+	}
+
+	levels := map[int64][]StockLevel{}
+	for _, line := range lines {
+		if _, ok := levels[line.ProductID]; !ok {
+			levels[line.ProductID], err = m.levels(exec, line.ProductID)
+			if err != nil {
+				return err
+			}
+		}
+
+		missing := line.Quantity
+		for _, level := range levels[line.ProductID] {
+			if missing == 0 {
+				break
+			}
+			if level.Available <= 0 || !sameVariant(level.VariantID, line.VariantID) {
+				continue
+			}
+
+			quantity := level.Available
+			if missing < quantity {
+				quantity = missing
+			}
+
+			err = m.move(exec, orgID, userID, stockMovement{ProductID: line.ProductID, VariantID: line.VariantID, WarehouseID: level.WarehouseID, OrderID: &orderID, Type: StockReservation, Quantity: quantity})
+			if err != nil {
+				return err
+			}
+			missing -= quantity
+		}
+
+		if missing > 0 {
+			return fmt.Errorf("%w: %d more %s needed", ErrInsufficientStock, missing, line.SKU)
+		}
+	}
+
+	return nil
+}
+
+// release gives back what the order still holds, once it is cancelled or refunded
+func (m InventoryModel) release(exec gorp.SqlExecutor, orgID, userID, orderID int64) (err error) {
+	reservations, err := m.reservations(exec, orderID)
+	if err != nil || len(reservations) == 0 {
+		return err
+	}
+
+	err = m.lockProducts(exec, reservations)
+	if err != nil {
+		return err
+	}
+
+	for _, reservation := range reservations {
+		err = m.move(exec, orgID, userID, stockMovement{ProductID: reservation.ProductID, VariantID: reservation.VariantID, WarehouseID: reservation.WarehouseID, OrderID: &orderID, Type: StockRelease, Quantity: -reservation.Quantity})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ship takes the tracked items of a shipment that left out of the warehouses holding their reservation,
+// once per shipment. The quantities ordered before the product was tracked have no reservation and are not moved
+func (m InventoryModel) ship(exec gorp.SqlExecutor, orgID, userID, shipmentID, orderID int64) (err error) {
+	shipped, err := exec.SelectInt("SELECT count(id) FROM public.stock_movement WHERE shipment_id=$1 AND type=$2", shipmentID, StockShipment)
+	if err != nil || shipped > 0 {
+		return err
+	}
+
+	var lines []stockLine
+	_, err = exec.Select(&lines, "SELECT i.product_id, i.variant_id, SUM(si.quantity) AS quantity FROM public.shipment_item si INNER JOIN public.order_item i ON si.order_item_id = i.id INNER JOIN public.product p ON i.product_id = p.id WHERE si.shipment_id=$1 AND p.track_inventory GROUP BY i.product_id, i.variant_id ORDER BY i.product_id", shipmentID)
+	if err != nil || len(lines) == 0 {
+		return err
+	}
+
+	err = m.lockProducts(exec, lines)
+	if err != nil {
+		return err
+	}
+
+	reservations, err := m.reservations(exec, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		missing := line.Quantity
+		for i := range reservations {
+			reservation := &reservations[i]
+			if missing == 0 {
+				break
+			}
+			if reservation.Quantity == 0 || reservation.ProductID != line.ProductID || !sameVariant(reservation.VariantID, line.VariantID) {
+				continue
+			}
+
+			quantity := reservation.Quantity
+			if missing < quantity {
+				quantity = missing
+			}
+
+			for _, movementType := range []string{StockRelease, StockShipment} {
+				err = m.move(exec, orgID, userID, stockMovement{ProductID: line.ProductID, VariantID: line.VariantID, WarehouseID: reservation.WarehouseID, OrderID: &orderID, ShipmentID: &shipmentID, Type: movementType, Quantity: -quantity})
+				if err != nil {
+					return err
+				}
+			}
+			reservation.Quantity -= quantity
+			missing -= quantity
+		}
+	}
+
+	return nil
+}
+
+// Stock ...
+func (m InventoryModel) Stock(orgID, productID int64) (stock Stock, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = productModel.one(tx, orgID, productID)
+		if err != nil {
+			return err
+		}
+
+		//The levels come from a single query so the totals always add up
+		stock.Levels, err = m.levels(tx, productID)
+		return err
+	})
+	if err != nil {
+		return stock, err
+	}
+	if stock.Levels == nil {
+		stock.Levels = []StockLevel{}
+	}
+
+	stock.ProductID = productID
+	for _, level := range stock.Levels {
+		stock.OnHand += level.OnHand
+		stock.Reserved += level.Reserved
+		stock.Available += level.Available
+	}
+
+	return stock, nil
+}
+
+// Move records a receipt or an adjustment of the product stock in a warehouse,
+// an adjustment can not remove the stock reserved by the orders
+func (m InventoryModel) Move(orgID, userID, productID int64, form forms.StockMovementForm) (stock Stock, err error) {
+	if form.Type == StockReceipt && form.Quantity < 0 {
+		return stock, ErrNegativeReceipt
+	}
+
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return stock, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var locked int64
+	err = tx.QueryRow("SELECT id FROM public.product WHERE id=$1 AND organization_id=$2 FOR UPDATE", productID, orgID).Scan(&locked)
+	if err != nil {
+		return stock, err
+	}
+
+	warehouses, err := tx.SelectInt("SELECT count(id) FROM public.warehouse WHERE id=$1 AND organization_id=$2", form.WarehouseID, orgID)
+	if err != nil {
+		return stock, err
+	}
+	if warehouses == 0 {
+		err = ErrWarehouseNotFound
+		return stock, err
+	}
+
+	variantID := optionalID(form.VariantID)
+	if variantID != nil {
+		var variants int64
+		variants, err = tx.SelectInt("SELECT count(id) FROM public.product_variant WHERE id=$1 AND product_id=$2", form.VariantID, productID)
+		if err != nil {
+			return stock, err
+		}
+		if variants == 0 {
+			err = ErrVariantNotFound
+			return stock, err
+		}
+	}
+
+	err = m.move(tx, orgID, userID, stockMovement{ProductID: productID, VariantID: variantID, WarehouseID: form.WarehouseID, Type: form.Type, Quantity: form.Quantity, Note: form.Note})
+	if err != nil {
+		return stock, err
+	}
+
+	if form.Quantity < 0 {
+		var levels []StockLevel
+		levels, err = m.levels(tx, productID)
+		if err != nil {
+			return stock, err
+		}
+
+		for _, level := range levels {
+			if level.WarehouseID == form.WarehouseID && sameVariant(level.VariantID, variantID) && level.Available < 0 {
+				err = fmt.Errorf("%w: only %d can be removed from the warehouse", ErrInsufficientStock, level.Available-form.Quantity)
+				return stock, err
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return stock, err
+	}
+
+	return m.Stock(orgID, productID)
+}
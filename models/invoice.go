@@ -1,78 +1,370 @@
 package models
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
 )
 
+// Invoice statuses ...
+const (
+	InvoiceStatusDraft  = "draft"
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusPaid   = "paid"
+	InvoiceStatusVoid   = "void"
+)
+
+// ErrInvoiceLocked ...
+var ErrInvoiceLocked = errors.New("only draft invoices can be changed")
+
+// ErrOrderNotInvoiceable ...
+var ErrOrderNotInvoiceable = errors.New("only placed, paid or fulfilled orders can be invoiced")
+
+// ErrOrderInvoiced ...
+var ErrOrderInvoiced = errors.New("the order already has an invoice, void it first")
+
 // Invoice ...
+// All the amounts are stored as integers in the minor unit of the invoice currency (e.g. cents)
 type Invoice struct {
-	ID        int64    `db:"id, primarykey, autoincrement" json:"id"`
-	UserID    int64    `db:"user_id" json:"-"`
-	Title     string   `db:"title" json:"title"`
-	Content   string   `db:"content" json:"content"`
-	UpdatedAt int64    `db:"updated_at" json:"updated_at"`
-	CreatedAt int64    `db:"created_at" json:"created_at"`
-	User      *JSONRaw `db:"user" json:"user"`
+	ID             int64         `db:"id, primarykey, autoincrement" json:"id"`
+	UserID         int64         `db:"user_id" json:"-"`
+	OrderID        int64         `db:"order_id" json:"order_id"`
+	CustomerID     int64         `db:"customer_id" json:"customer_id"`
+	Number         string        `db:"number" json:"number"`
+	Status         string        `db:"status" json:"status"`
+	Currency       string        `db:"currency" json:"currency"`
+	Subtotal       int64         `db:"subtotal" json:"subtotal"`
+	TaxTotal       int64         `db:"tax_total" json:"tax_total"`
+	Total          int64         `db:"total" json:"total"`
+	TaxBreakdown   TaxBreakdown  `db:"tax_breakdown" json:"tax_breakdown"`
+	BillingName    string        `db:"billing_name" json:"billing_name"`
+	BillingAddress string        `db:"billing_address" json:"billing_address"`
+	BillingTaxID   string        `db:"billing_tax_id" json:"billing_tax_id"`
+	Notes          string        `db:"notes" json:"notes"`
+	DueDate        string        `db:"due_date" json:"due_date"`
+	IssuedAt       int64         `db:"issued_at" json:"issued_at"`
+	UpdatedAt      int64         `db:"updated_at" json:"updated_at"`
+	CreatedAt      int64         `db:"created_at" json:"created_at"`
+	User           *JSONRaw      `db:"user" json:"user"`
+	Items          []InvoiceItem `db:"-" json:"items"`
+}
+
+// InvoiceItem is a snapshot of an order line at the time the invoice was generated
+type InvoiceItem struct {
+	ID          int64  `db:"id, primarykey, autoincrement" json:"id"`
+	InvoiceID   int64  `db:"invoice_id" json:"-"`
+	ProductID   int64  `db:"product_id" json:"product_id"`
+	Description string `db:"description" json:"description"`
+	Quantity    int64  `db:"quantity" json:"quantity"`
+	UnitPrice   int64  `db:"unit_price" json:"unit_price"`
+	TaxRate     int64  `db:"tax_rate" json:"tax_rate"`
+	Subtotal    int64  `db:"subtotal" json:"subtotal"`
+	Tax         int64  `db:"tax" json:"tax"`
+	Total       int64  `db:"total" json:"total"`
+}
+
+// TaxLine is the taxable amount and the tax collected for one tax rate (in basis points)
+type TaxLine struct {
+	Rate    int64 `json:"rate"`
+	Taxable int64 `json:"taxable"`
+	Tax     int64 `json:"tax"`
+}
+
+// TaxBreakdown ...
+type TaxBreakdown []TaxLine
+
+// Value ...
+func (t TaxBreakdown) Value() (driver.Value, error) {
+	if t == nil {
+		t = TaxBreakdown{}
+	}
+	//Sent as a string, lib/pq would encode []byte as bytea
+	asBytes, err := json.Marshal(t)
+	return driver.Value(string(asBytes)), err
+}
+
+// Scan ...
+func (t *TaxBreakdown) Scan(src interface{}) error {
+	asBytes, ok := src.([]byte)
+	if !ok {
+		return errors.New("Scan source was not []bytes")
+	}
+	return json.Unmarshal(asBytes, t)
+}
+
+// taxBreakdown groups the invoice items by tax rate
+func taxBreakdown(items []InvoiceItem) (breakdown TaxBreakdown) {
+	lines := map[int64]*TaxLine{}
+	for _, item := range items {
+		line, ok := lines[item.TaxRate]
+		if !ok {
+			line = &TaxLine{Rate: item.TaxRate}
+			lines[item.TaxRate] = line
+		}
+		line.Taxable += item.Subtotal
+		line.Tax += item.Tax
+	}
+
+	breakdown = TaxBreakdown{}
+	for _, line := range lines {
+		breakdown = append(breakdown, *line)
+	}
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Rate < breakdown[j].Rate })
+
+	return breakdown
 }
 
 // InvoiceModel ...
 type InvoiceModel struct{}
 
-// Create ...
-func (m InvoiceModel) Create(userID int64, form forms.CreateInvoiceForm) (invoiceID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.invoice(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&invoiceID)
-	return invoiceID, err
+var invoiceModel = new(InvoiceModel)
+
+// invoiceColumns are the invoice columns returned by One and All
+const invoiceColumns = "a.id, a.order_id, a.customer_id, COALESCE(a.number, '') AS number, a.status, a.currency, a.subtotal, a.tax_total, a.total, a.tax_breakdown, COALESCE(a.billing_name, '') AS billing_name, COALESCE(a.billing_address, '') AS billing_address, COALESCE(a.billing_tax_id, '') AS billing_tax_id, COALESCE(a.notes, '') AS notes, to_char(a.due_date, 'YYYY-MM-DD') AS due_date, COALESCE(a.issued_at, 0) AS issued_at, a.updated_at, a.created_at"
+
+// Generate creates a draft invoice from the order lines, the lines and the customer details are copied
+// so later changes to the order, the products or the customer never alter the invoice
+func (m InvoiceModel) Generate(orgID, userID, orderID int64, form forms.GenerateInvoiceForm) (invoice Invoice, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return invoice, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, err := orderModel.lockStatus(tx, orgID, orderID)
+	if err != nil {
+		return invoice, err
+	}
+	if status != OrderStatusPlaced && status != OrderStatusPaid && status != OrderStatusFulfilled {
+		err = ErrOrderNotInvoiceable
+		return invoice, err
+	}
+
+	invoiced, err := tx.SelectInt("SELECT count(id) FROM public.invoice WHERE order_id=$1 AND status <> $2", orderID, InvoiceStatusVoid)
+	if err != nil {
+		return invoice, err
+	}
+	if invoiced > 0 {
+		err = ErrOrderInvoiced
+		return invoice, err
+	}
+
+	order, err := orderModel.one(tx, orgID, orderID)
+	if err != nil {
+		return invoice, err
+	}
+
+	_, err = tx.Select(&invoice.Items, "SELECT i.product_id, COALESCE(p.name, '') AS description, i.quantity, i.unit_price, i.tax_rate, i.subtotal, i.tax, i.total FROM public.order_item i LEFT JOIN public.product p ON i.product_id = p.id WHERE i.order_id=$1 ORDER BY i.id", orderID)
+	if err != nil {
+		return invoice, err
+	}
+
+	customer, err := customerModel.one(tx, orgID, order.CustomerID)
+	if err != nil {
+		return invoice, err
+	}
+
+	invoice.BillingName = customer.BillingName()
+	invoice.BillingTaxID = customer.TaxID
+	if address, ok := customer.DefaultAddress(AddressBilling); ok {
+		invoice.BillingAddress = address.String()
+	}
+
+	invoice.DueDate = form.DueDate
+	if invoice.DueDate == "" {
+		invoice.DueDate = time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	}
+
+	invoice.TaxBreakdown = taxBreakdown(invoice.Items)
+
+	err = tx.QueryRow("INSERT INTO public.invoice(organization_id, user_id, order_id, customer_id, currency, subtotal, tax_total, total, tax_breakdown, billing_name, billing_address, billing_tax_id, notes, due_date) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id", orgID, userID, orderID, order.CustomerID, order.Currency, order.Subtotal, order.TaxTotal, order.Total, invoice.TaxBreakdown, invoice.BillingName, invoice.BillingAddress, invoice.BillingTaxID, form.Notes, invoice.DueDate).Scan(&invoice.ID)
+	if err != nil {
+		return invoice, err
+	}
+
+	for _, item := range invoice.Items {
+		_, err = tx.Exec("INSERT INTO public.invoice_item(invoice_id, product_id, description, quantity, unit_price, tax_rate, subtotal, tax, total) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)", invoice.ID, item.ProductID, item.Description, item.Quantity, item.UnitPrice, item.TaxRate, item.Subtotal, item.Tax, item.Total)
+		if err != nil {
+			return invoice, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return invoice, err
+	}
+
+	return m.One(orgID, invoice.ID)
 }
 
-// One ...
-func (m InvoiceModel) One(userID, id int64) (invoice Invoice, err error) {
-	err = db.GetDB().SelectOne(&invoice, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.invoice a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
-	return invoice, err
+// lockStatus returns the current status of the invoice and locks its row until the transaction ends
+func (m InvoiceModel) lockStatus(exec gorp.SqlExecutor, orgID, id int64) (status string, err error) {
+	status, err = exec.SelectStr("SELECT status FROM public.invoice WHERE id=$1 AND organization_id=$2 FOR UPDATE", id, orgID)
+	if err == nil && status == "" {
+		err = sql.ErrNoRows
+	}
+	return status, err
 }
 
-// All ...
-func (m InvoiceModel) All(userID int64) (invoices []DataList, err error) {
-	_, err = db.GetDB().Select(&invoices, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.invoice AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.invoice a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
-	return invoices, err
+// nextNumber returns the next invoice number of the organization for the given year.
+// The counter row stays locked until the caller transaction ends, so concurrent issues wait for each other
+// and a rolled back issue gives its number back: the numbering has no gaps
+func (m InvoiceModel) nextNumber(exec gorp.SqlExecutor, orgID int64, year int) (number string, err error) {
+	var sequence int64
+	err = exec.QueryRow("INSERT INTO public.invoice_sequence(organization_id, year, last_value) VALUES($1, $2, 1) ON CONFLICT (organization_id, year) DO UPDATE SET last_value = invoice_sequence.last_value + 1 RETURNING last_value", orgID, year).Scan(&sequence)
+	if err != nil {
+		return number, err
+	}
+	return fmt.Sprintf("INV-%d-%06d", year, sequence), nil
 }
 
-// Update ...
-func (m InvoiceModel) Update(userID int64, id int64, form forms.CreateInvoiceForm) (err error) {
-	//METHOD 1
-	//Check the invoice by ID using this way
-	// _, err = m.One(userID, id)
-	// if err != nil {
-	// 	return err
-	// }
+// Issue assigns the next sequential number to a draft invoice, after that the invoice can not be changed anymore
+func (m InvoiceModel) Issue(orgID, id int64) (invoice Invoice, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return invoice, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	operation, err := db.GetDB().Exec("UPDATE public.invoice SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
+	status, err := m.lockStatus(tx, orgID, id)
 	if err != nil {
-		return err
+		return invoice, err
+	}
+	if status != InvoiceStatusDraft {
+		err = ErrInvoiceLocked
+		return invoice, err
+	}
+
+	now := time.Now()
+
+	number, err := m.nextNumber(tx, orgID, now.Year())
+	if err != nil {
+		return invoice, err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
+	_, err = tx.Exec("UPDATE public.invoice SET number=$2, status=$3, issued_at=$4 WHERE id=$1", id, number, InvoiceStatusIssued, now.Unix())
+	if err != nil {
+		return invoice, err
 	}
 
-	return err
+	err = tx.Commit()
+	if err != nil {
+		return invoice, err
+	}
+
+	return m.One(orgID, id)
 }
 
-// Delete ...
-func (m InvoiceModel) Delete(userID, id int64) (err error) {
+// Void cancels an issued invoice, it keeps its number so the sequence stays complete
+func (m InvoiceModel) Void(orgID, id int64) (invoice Invoice, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return invoice, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.invoice WHERE id=$1", id)
+	status, err := m.lockStatus(tx, orgID, id)
 	if err != nil {
-		return err
+		return invoice, err
+	}
+	if status != InvoiceStatusIssued {
+		err = fmt.Errorf("%w: only issued invoices can be voided, drafts can be deleted", ErrInvoiceLocked)
+		return invoice, err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
+	_, err = tx.Exec("UPDATE public.invoice SET status=$2 WHERE id=$1", id, InvoiceStatusVoid)
+	if err != nil {
+		return invoice, err
 	}
 
-	return err
+	err = tx.Commit()
+	if err != nil {
+		return invoice, err
+	}
+
+	return m.One(orgID, id)
+}
+
+// one ...
+func (m InvoiceModel) one(exec gorp.SqlExecutor, orgID, id int64) (invoice Invoice, err error) {
+	err = exec.SelectOne(&invoice, "SELECT "+invoiceColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.invoice a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+	if err != nil {
+		return invoice, err
+	}
+
+	_, err = exec.Select(&invoice.Items, "SELECT i.id, COALESCE(i.product_id, 0) AS product_id, i.description, i.quantity, i.unit_price, i.tax_rate, i.subtotal, i.tax, i.total FROM public.invoice_item i WHERE i.invoice_id=$1 ORDER BY i.id", invoice.ID)
+	return invoice, err
+}
+
+// One ...
+func (m InvoiceModel) One(orgID, id int64) (invoice Invoice, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		invoice, err = m.one(tx, orgID, id)
+		return err
+	})
+	return invoice, err
+}
+
+// All ...
+func (m InvoiceModel) All(orgID int64) (invoices []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&invoices, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.invoice AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT "+invoiceColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.invoice a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 ORDER BY a.id DESC) d", orgID)
+		return err
+	})
+	return invoices, err
+}
+
+// Update changes the due date, billing details and notes of a draft invoice
+func (m InvoiceModel) Update(orgID int64, id int64, form forms.UpdateInvoiceForm) (err error) {
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("UPDATE public.invoice SET due_date=$3, billing_name=$4, billing_address=$5, billing_tax_id=$6, notes=$7 WHERE id=$1 AND organization_id=$2 AND status=$8", id, orgID, form.DueDate, form.BillingName, form.BillingAddress, form.BillingTaxID, form.Notes, InvoiceStatusDraft)
+		if err != nil {
+			return err
+		}
+
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("updated 0 records")
+		}
+
+		return nil
+	})
+}
+
+// Delete ...
+func (m InvoiceModel) Delete(orgID, id int64) (err error) {
+
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.invoice WHERE id=$1 AND organization_id=$2 AND status=$3", id, orgID, InvoiceStatusDraft)
+		if err != nil {
+			return err
+		}
+
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,262 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ErrInvoiceNotIssued ...
+var ErrInvoiceNotIssued = errors.New("only issued invoices can be downloaded")
+
+// invoicePDFExpiration is how long a rendered invoice stays in the cache
+const invoicePDFExpiration = 24 * time.Hour
+
+// Seller is the issuer printed on the invoices
+type Seller struct {
+	Name    string
+	Address string
+	TaxID   string
+}
+
+// SellerFromEnv reads the seller details from SELLER_NAME, SELLER_ADDRESS and SELLER_TAX_ID
+func SellerFromEnv() Seller {
+	return Seller{
+		Name:    os.Getenv("SELLER_NAME"),
+		Address: os.Getenv("SELLER_ADDRESS"),
+		TaxID:   os.Getenv("SELLER_TAX_ID"),
+	}
+}
+
+// currencyExponents are the currencies that do not use 2 decimals for their minor unit
+var currencyExponents = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// formatAmount formats an amount in minor units with its currency code, e.g. 3399 USD is "USD 33.99"
+func formatAmount(amount int64, currency string) string {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		exponent = 2
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if exponent == 0 {
+		return fmt.Sprintf("%s %s%d", currency, sign, amount)
+	}
+
+	divisor := int64(1)
+	for i := 0; i < exponent; i++ {
+		divisor *= 10
+	}
+
+	return fmt.Sprintf("%s %s%d.%0*d", currency, sign, amount/divisor, exponent, amount%divisor)
+}
+
+// formatRate formats a rate in basis points as a percentage, e.g. 2000 is "20.00%"
+func formatRate(rate int64) string {
+	return fmt.Sprintf("%d.%02d%%", rate/100, rate%100)
+}
+
+// RenderInvoicePDF renders the invoice to a PDF document.
+// The output only depends on the invoice and the seller: the document dates are the issue date
+// and the catalog is sorted, so the same invoice always gives the same bytes
+func RenderInvoicePDF(invoice Invoice, seller Seller) ([]byte, error) {
+	issuedAt := time.Unix(invoice.IssuedAt, 0).UTC()
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(issuedAt)
+	pdf.SetModificationDate(issuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle("Invoice "+invoice.Number, true)
+	pdf.SetAuthor(seller.Name, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(false, 20)
+	pdf.AliasNbPages("")
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%s - Page %d/{nb}", invoice.Number, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+
+	//Title and invoice details
+	pdf.SetFont("Helvetica", "B", 20)
+	title := "INVOICE"
+	if invoice.Status == InvoiceStatusVoid {
+		title = "INVOICE - VOID"
+	}
+	pdf.CellFormat(90, 10, title, "", 0, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	details := [][2]string{
+		{"Number", invoice.Number},
+		{"Issue date", issuedAt.Format("2006-01-02")},
+		{"Due date", invoice.DueDate},
+	}
+	for i, detail := range details {
+		pdf.SetXY(110, 20+float64(i)*5)
+		pdf.CellFormat(30, 5, detail[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 5, tr(detail[1]), "", 0, "R", false, 0, "")
+	}
+
+	//Seller and billing addresses side by side
+	top := 40.0
+	addressBlock := func(x float64, label, name, address, taxID string) float64 {
+		pdf.SetXY(x, top)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(80, 5, label, "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.MultiCell(80, 5, tr(name), "", "L", false)
+		pdf.SetFont("Helvetica", "", 10)
+		if address != "" {
+			pdf.SetX(x)
+			pdf.MultiCell(80, 5, tr(address), "", "L", false)
+		}
+		if taxID != "" {
+			pdf.SetX(x)
+			pdf.MultiCell(80, 5, tr("Tax ID: "+taxID), "", "L", false)
+		}
+		return pdf.GetY()
+	}
+	sellerBottom := addressBlock(20, "FROM", seller.Name, seller.Address, seller.TaxID)
+	billingBottom := addressBlock(110, "BILL TO", invoice.BillingName, invoice.BillingAddress, invoice.BillingTaxID)
+
+	y := sellerBottom
+	if billingBottom > y {
+		y = billingBottom
+	}
+
+	//Line items
+	_, pageHeight := pdf.GetPageSize()
+	bottom := pageHeight - 25
+
+	widths := []float64{75, 15, 27, 20, 33}
+	tableHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for i, header := range []string{"Description", "Qty", "Unit price", "Tax", "Amount"} {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 7, header, "B", 0, align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+
+	pdf.SetXY(20, y+10)
+	tableHeader()
+
+	for _, item := range invoice.Items {
+		lines := pdf.SplitLines([]byte(tr(item.Description)), widths[0]-2)
+		if len(lines) == 0 {
+			lines = [][]byte{{}}
+		}
+		height := float64(len(lines)) * 5
+
+		if pdf.GetY()+height > bottom {
+			pdf.AddPage()
+			tableHeader()
+		}
+
+		rowY := pdf.GetY()
+		for i, line := range lines {
+			pdf.SetXY(20, rowY+float64(i)*5)
+			pdf.CellFormat(widths[0], 5, string(line), "", 0, "L", false, 0, "")
+		}
+
+		pdf.SetXY(20+widths[0], rowY)
+		pdf.CellFormat(widths[1], 5, fmt.Sprintf("%d", item.Quantity), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 5, formatAmount(item.UnitPrice, invoice.Currency), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 5, formatRate(item.TaxRate), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 5, formatAmount(item.Subtotal, invoice.Currency), "", 0, "R", false, 0, "")
+
+		pdf.SetXY(20, rowY+height)
+		pdf.Line(20, rowY+height, 190, rowY+height)
+	}
+
+	//Taxes and totals, kept together on one page
+	totals := [][2]string{{"Subtotal", formatAmount(invoice.Subtotal, invoice.Currency)}}
+	for _, line := range invoice.TaxBreakdown {
+		totals = append(totals, [2]string{
+			fmt.Sprintf("Tax %s on %s", formatRate(line.Rate), formatAmount(line.Taxable, invoice.Currency)),
+			formatAmount(line.Tax, invoice.Currency),
+		})
+	}
+	totals = append(totals, [2]string{"Total", formatAmount(invoice.Total, invoice.Currency)})
+
+	if pdf.GetY()+5+float64(len(totals))*6 > bottom {
+		pdf.AddPage()
+	}
+
+	pdf.Ln(5)
+	for i, total := range totals {
+		if i == len(totals)-1 {
+			pdf.SetFont("Helvetica", "B", 10)
+		}
+		pdf.SetX(90)
+		pdf.CellFormat(67, 6, total[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(33, 6, total[1], "", 1, "R", false, 0, "")
+	}
+
+	if strings.TrimSpace(invoice.Notes) != "" {
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(0, 5, "Notes", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, tr(invoice.Notes), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	return buf.Bytes(), err
+}
+
+// PDF returns the rendered PDF of an issued invoice.
+// The bytes are cached by invoice id and updated_at, any change to the invoice renders it again
+func (m InvoiceModel) PDF(orgID, id int64) (document []byte, invoice Invoice, err error) {
+	invoice, err = m.One(orgID, id)
+	if err != nil {
+		return document, invoice, err
+	}
+
+	if invoice.Status == InvoiceStatusDraft {
+		return document, invoice, ErrInvoiceNotIssued
+	}
+
+	key := fmt.Sprintf("invoice:pdf:%d:%d", invoice.ID, invoice.UpdatedAt)
+
+	document, err = db.GetRedis().Get(key).Bytes()
+	if err == nil {
+		return document, invoice, nil
+	}
+
+	document, err = RenderInvoicePDF(invoice, SellerFromEnv())
+	if err != nil {
+		return document, invoice, err
+	}
+
+	//A cache failure should not fail the download
+	db.GetRedis().Set(key, document, invoicePDFExpiration)
+
+	return document, invoice, nil
+}
@@ -0,0 +1,404 @@
+package models
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// Signing algorithms of the access tokens, set with JWT_ALGORITHM ...
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// keyRingReload is how often the keys are read again, to pick up the keys rotated by the other instances
+const keyRingReload = time.Minute
+
+// keyRingRefresh is how often a token with an unknown kid reads the keys again,
+// the tokens with a made up kid do not reach the database more often than that
+const keyRingRefresh = 5 * time.Second
+
+// defaultKeyRotation is how long a key signs the tokens before a new one replaces it, unless JWT_KEY_ROTATION is set
+const defaultKeyRotation = 30 * 24 * time.Hour
+
+// ErrUnknownSigningKey ...
+var ErrUnknownSigningKey = errors.New("the token is signed with an unknown key")
+
+// legacyTokensUntil returns the time of JWT_LEGACY_HS256_UNTIL (RFC 3339) until which the access tokens
+// signed with ACCESS_SECRET are still accepted after switching to an asymmetric algorithm, zero when it is not set
+func legacyTokensUntil() time.Time {
+	until, err := time.Parse(time.RFC3339, os.Getenv("JWT_LEGACY_HS256_UNTIL"))
+	if err != nil {
+		return time.Time{}
+	}
+	return until
+}
+
+// jwtAlgorithm returns the algorithm of JWT_ALGORITHM, HS256 with ACCESS_SECRET by default
+func jwtAlgorithm() string {
+	switch algorithm := os.Getenv("JWT_ALGORITHM"); algorithm {
+	case AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA:
+		return algorithm
+	default:
+		return AlgorithmHS256
+	}
+}
+
+// keyRotation returns the duration of JWT_KEY_ROTATION (e.g. "720h")
+func keyRotation() time.Duration {
+	if rotation, err := time.ParseDuration(os.Getenv("JWT_KEY_ROTATION")); err == nil && rotation > 0 {
+		return rotation
+	}
+	return defaultKeyRotation
+}
+
+// signingKey is a private key of the ring, stored PKCS #8 PEM encoded
+type signingKey struct {
+	Kid        string `db:"kid"`
+	Algorithm  string `db:"algorithm"`
+	PrivateKey string `db:"private_key"`
+	ExpiresAt  int64  `db:"expires_at"`
+	CreatedAt  int64  `db:"created_at"`
+	private    crypto.PrivateKey
+	public     crypto.PublicKey
+}
+
+// parse decodes the PEM of the private key
+func (k *signingKey) parse() error {
+	block, _ := pem.Decode([]byte(k.PrivateKey))
+	if block == nil {
+		return fmt.Errorf("the signing key %s is not PEM encoded", k.Kid)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		k.public = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		k.public = &key.PublicKey
+	case ed25519.PrivateKey:
+		k.public = key.Public()
+	default:
+		return fmt.Errorf("the signing key %s is of an unsupported type", k.Kid)
+	}
+	k.private = private
+
+	return nil
+}
+
+// generateKey creates a new key for the algorithm, it signs the tokens until the rotation
+// and stays valid for verification until the last of those tokens expired
+func generateKey(algorithm string) (key *signingKey, err error) {
+	var private crypto.PrivateKey
+
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("no key is generated for %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := randomSecret("", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key = &signingKey{
+		Kid:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ExpiresAt:  now.Add(keyRotation() + accessTokenExpiration()).Unix(),
+		CreatedAt:  now.Unix(),
+	}
+
+	return key, key.parse()
+}
+
+// keyRing caches the keys that are still valid by their kid
+var keyRing = struct {
+	sync.Mutex
+	keys     map[string]*signingKey
+	loadedAt time.Time
+}{keys: map[string]*signingKey{}}
+
+// loadKeys returns the keys of the ring, read again once they are older than maxAge:
+// keyRingReload, keyRingRefresh for an unknown kid and 0 right after a rotation
+func loadKeys(maxAge time.Duration) (map[string]*signingKey, error) {
+	keyRing.Lock()
+	defer keyRing.Unlock()
+
+	if maxAge > 0 && time.Since(keyRing.loadedAt) < maxAge {
+		return keyRing.keys, nil
+	}
+
+	var records []*signingKey
+	_, err := db.GetDB().Select(&records, "SELECT kid, algorithm, private_key, expires_at, created_at FROM public.signing_key WHERE expires_at > extract(epoch from now())")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*signingKey{}
+	for _, key := range records {
+		if err = key.parse(); err != nil {
+			return nil, err
+		}
+		keys[key.Kid] = key
+	}
+
+	keyRing.keys = keys
+	keyRing.loadedAt = time.Now()
+
+	return keys, nil
+}
+
+// currentKey returns the newest key of the algorithm that is not due for rotation yet, nil when there is none
+func currentKey(keys map[string]*signingKey, algorithm string) (current *signingKey) {
+	rotatedBefore := time.Now().Add(-keyRotation()).Unix()
+
+	for _, key := range keys {
+		if key.Algorithm != algorithm || key.CreatedAt <= rotatedBefore {
+			continue
+		}
+		if current == nil || key.CreatedAt > current.CreatedAt {
+			current = key
+		}
+	}
+	return current
+}
+
+// activeKey returns the key signing the access tokens, a new one is created when it is due for rotation
+func activeKey() (*signingKey, error) {
+	keys, err := loadKeys(keyRingReload)
+	if err != nil {
+		return nil, err
+	}
+	if key := currentKey(keys, jwtAlgorithm()); key != nil {
+		return key, nil
+	}
+
+	return rotateKey(jwtAlgorithm())
+}
+
+// rotateKey creates the new key of the algorithm and deletes the expired ones,
+// the instances rotating at the same time wait for each other so that only one key is created
+func rotateKey(algorithm string) (key *signingKey, err error) {
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock(hashtext('signing_key'))")
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := tx.SelectInt("SELECT count(id) FROM public.signing_key WHERE algorithm=$1 AND created_at > $2", algorithm, time.Now().Add(-keyRotation()).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	if rotated == 0 {
+		key, err = generateKey(algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec("INSERT INTO public.signing_key(kid, algorithm, private_key, expires_at) VALUES($1, $2, $3, $4)", key.Kid, key.Algorithm, key.PrivateKey, key.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM public.signing_key WHERE expires_at <= extract(epoch from now())")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	keys, err := loadKeys(0)
+	if err != nil {
+		return nil, err
+	}
+	if key = currentKey(keys, algorithm); key == nil {
+		return nil, fmt.Errorf("no %s signing key after the rotation", algorithm)
+	}
+	return key, nil
+}
+
+// signAccessToken signs the claims with the active key of the ring, its kid in the header,
+// or with ACCESS_SECRET when the algorithm is HS256
+func signAccessToken(claims jwt.Claims) (string, error) {
+	if jwtAlgorithm() == AlgorithmHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+	}
+
+	key, err := activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.private)
+}
+
+// verificationKey returns the key an access token is verified with, by its kid.
+// The tokens without kid are the ones signed with ACCESS_SECRET, accepted while the algorithm is HS256,
+// or until JWT_LEGACY_HS256_UNTIL so that switching to an asymmetric algorithm does not log everyone out
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		secret := os.Getenv("ACCESS_SECRET")
+		if token.Method != jwt.SigningMethodHS256 || secret == "" {
+			return nil, ErrUnknownSigningKey
+		}
+		if jwtAlgorithm() != AlgorithmHS256 && !time.Now().Before(legacyTokensUntil()) {
+			return nil, ErrUnknownSigningKey
+		}
+		return []byte(secret), nil
+	}
+
+	keys, err := loadKeys(keyRingReload)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[kid]
+	if !ok {
+		//Rotated by another instance since the ring was loaded
+		if keys, err = loadKeys(keyRingRefresh); err != nil {
+			return nil, err
+		}
+		if key, ok = keys[kid]; !ok {
+			return nil, ErrUnknownSigningKey
+		}
+	}
+
+	//The algorithm of the key, never the one the token claims
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.public, nil
+}
+
+// JWK ...
+// A public key of the ring as a JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet ...
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// fixedBytes returns the big-endian bytes of the number padded to size, as the EC coordinates are encoded
+func fixedBytes(number *big.Int, size int) []byte {
+	buf := make([]byte, size)
+	bytes := number.Bytes()
+	copy(buf[size-len(bytes):], bytes)
+	return buf
+}
+
+// JWKS lists the public keys the access tokens are verified with, newest first:
+// the active key and the rotated ones still valid for the tokens they signed
+func (m AuthModel) JWKS() (set JWKSet, err error) {
+	set.Keys = []JWK{}
+
+	if jwtAlgorithm() != AlgorithmHS256 {
+		//Publish the key before the first token is signed with it
+		if _, err = activeKey(); err != nil {
+			return set, err
+		}
+	}
+
+	keys, err := loadKeys(keyRingReload)
+	if err != nil {
+		return set, err
+	}
+
+	sorted := []*signingKey{}
+	for _, key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt > sorted[j].CreatedAt
+	})
+
+	for _, key := range sorted {
+		jwk := JWK{Kid: key.Kid, Use: "sig", Alg: key.Algorithm}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			jwk.Kty = "EC"
+			jwk.Crv = public.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(fixedBytes(public.X, 32))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(fixedBytes(public.Y, 32))
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
@@ -0,0 +1,264 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/go-redis/redis/v7"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Audit events of the lockout ...
+const (
+	AuditLoginLocked   = "login.locked"
+	AuditLoginUnlocked = "login.unlocked"
+)
+
+// Defaults of the lockout, unless LOGIN_MAX_ATTEMPTS, LOGIN_IP_MAX_ATTEMPTS and LOGIN_LOCKOUT are set ...
+const (
+	defaultLoginMaxAttempts   = 5
+	defaultLoginIPMaxAttempts = 50
+	defaultLoginLockout       = 15 * time.Minute
+)
+
+// Free failures before the back-off starts: a typo of the user, the users behind the same NAT ...
+const (
+	loginEmailFreeAttempts = 1
+	loginIPFreeAttempts    = 10
+)
+
+// loginBackoff is the first delay after the free failures, it doubles with every failure until the lockout
+const loginBackoff = time.Second
+
+// loginFailureWindow is how long the failures are counted after the last one
+const loginFailureWindow = time.Hour
+
+// ErrUnknownMember ...
+var ErrUnknownMember = errors.New("the user is not a member of this organization")
+
+// LoginLockedError ...
+// The login is refused without checking the password until RetryAfter passed
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, please try again later"
+}
+
+// Seconds returns RetryAfter rounded up, as sent in the Retry-After header
+func (e *LoginLockedError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// loginCounter counts the failures of an email or an IP
+type loginCounter struct {
+	key          string
+	freeAttempts int
+	maxAttempts  int
+}
+
+// LockoutModel ...
+// Counts the failed logins per email and per IP in Redis: after the free failures every failure delays
+// the next attempt twice as long, and the maximum of failures locks the login out
+type LockoutModel struct{}
+
+var lockoutModel = new(LockoutModel)
+
+// dummyPassword is compared when the email has no account, so that the response takes as long as for a wrong password
+var dummyPassword struct {
+	once sync.Once
+	hash []byte
+}
+
+// compareDummyPassword spends the time of a bcrypt comparison
+func compareDummyPassword(password string) {
+	dummyPassword.once.Do(func() {
+		dummyPassword.hash, _ = bcrypt.GenerateFromPassword([]byte("gin-boilerplate-dummy-password"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyPassword.hash, []byte(password))
+}
+
+// envInt returns the positive integer of the variable or the default
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// loginLockout returns the duration of LOGIN_LOCKOUT (e.g. "15m")
+func loginLockout() time.Duration {
+	if lockout, err := time.ParseDuration(os.Getenv("LOGIN_LOCKOUT")); err == nil && lockout > 0 {
+		return lockout
+	}
+	return defaultLoginLockout
+}
+
+// emailCounter counts by the hash of the email, known or not, so that the lockout does not tell which emails have an account
+func emailCounter(email string) loginCounter {
+	return loginCounter{
+		key:          "login:email:" + hashSecret(strings.ToLower(strings.TrimSpace(email))),
+		freeAttempts: loginEmailFreeAttempts,
+		maxAttempts:  envInt("LOGIN_MAX_ATTEMPTS", defaultLoginMaxAttempts),
+	}
+}
+
+// ipCounter ...
+func ipCounter(ip string) loginCounter {
+	return loginCounter{
+		key:          "login:ip:" + hashSecret(ip),
+		freeAttempts: loginIPFreeAttempts,
+		maxAttempts:  envInt("LOGIN_IP_MAX_ATTEMPTS", defaultLoginIPMaxAttempts),
+	}
+}
+
+// counters returns the counters of the attempt, the IP is unknown outside of a request
+func (m LockoutModel) counters(email, ip string) []loginCounter {
+	counters := []loginCounter{emailCounter(email)}
+	if ip != "" {
+		counters = append(counters, ipCounter(ip))
+	}
+	return counters
+}
+
+// reserveScript refuses the attempt while a counter waits for its next attempt, or else counts it as a failure
+// of every counter at once and delays the next attempt: the attempts sent in parallel are all counted before
+// any password is compared. KEYS are the failures and locked keys of each counter, ARGV the failure window
+// and the back-off in milliseconds, then the free and maximum attempts of each counter
+var reserveScript = redis.NewScript(`
+local retry = 0
+for i = 2, #KEYS, 2 do
+	local ttl = redis.call('PTTL', KEYS[i])
+	if ttl > retry then retry = ttl end
+end
+if retry > 0 then return {retry} end
+
+local window, backoff, lockout = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local result = {0}
+for i = 1, #KEYS, 2 do
+	local counter = (i + 1) / 2
+	local free, max = tonumber(ARGV[2 + counter * 2]), tonumber(ARGV[3 + counter * 2])
+	local failures = redis.call('INCR', KEYS[i])
+	redis.call('PEXPIRE', KEYS[i], window)
+
+	local delay = 0
+	if failures >= max then
+		delay = lockout
+	elseif failures > free then
+		delay = math.min(backoff * 2 ^ (failures - free - 1), lockout)
+	end
+	if delay > 0 then
+		redis.call('SET', KEYS[i + 1], failures, 'PX', math.floor(delay))
+	end
+	result[counter + 1] = failures
+end
+return result
+`)
+
+// refundScript gives the attempt back to a counter, and lifts the delay the attempt set on it
+var refundScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) == ARGV[1] then
+	redis.call('DEL', KEYS[2])
+end
+if tonumber(redis.call('GET', KEYS[1]) or '0') > 0 then
+	redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+// LoginAttempt ...
+// An attempt counted as a failure until the password or the code is checked, Succeed gives it back
+type LoginAttempt struct {
+	counters []loginCounter
+	failures []int
+}
+
+// Attempt reserves the login of the email from the IP, or returns a LoginLockedError while either waits for its next attempt
+func (m LockoutModel) Attempt(email, ip string) (*LoginAttempt, error) {
+	return m.reserve(m.counters(email, ip)...)
+}
+
+// reserve counts the attempt against the counters, the first one is the account the attempt is for
+func (m LockoutModel) reserve(counters ...loginCounter) (*LoginAttempt, error) {
+	keys := []string{}
+	args := []interface{}{loginFailureWindow.Milliseconds(), loginBackoff.Milliseconds(), loginLockout().Milliseconds()}
+	for _, counter := range counters {
+		keys = append(keys, counter.key+":failures", counter.key+":locked")
+		args = append(args, counter.freeAttempts, counter.maxAttempts)
+	}
+
+	values, err := reserveScript.Run(db.GetRedis(), keys, args...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result, _ := values.([]interface{})
+	if len(result) != len(counters)+1 {
+		retryAfter, _ := result[0].(int64)
+		return nil, &LoginLockedError{RetryAfter: time.Duration(retryAfter) * time.Millisecond}
+	}
+
+	attempt := &LoginAttempt{counters: counters}
+	for _, value := range result[1:] {
+		failures, _ := value.(int64)
+		attempt.failures = append(attempt.failures, int(failures))
+	}
+	return attempt, nil
+}
+
+// Locked tells whether the failure of the attempt locked the account out
+func (a *LoginAttempt) Locked() bool {
+	return a.failures[0] == a.counters[0].maxAttempts
+}
+
+// Succeed forgets the failures of the account once the user logged in,
+// the other counters (the IP) only get the attempt back and keep their previous failures
+func (a *LoginAttempt) Succeed() error {
+	account := a.counters[0].key
+	if err := db.GetRedis().Del(account+":failures", account+":locked").Err(); err != nil {
+		return err
+	}
+
+	for i, counter := range a.counters[1:] {
+		keys := []string{counter.key + ":failures", counter.key + ":locked"}
+		if err := refundScript.Run(db.GetRedis(), keys, a.failures[i+1]).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed forgets the failures of the email, the failures of the IP stay counted
+func (m LockoutModel) Succeed(email string) error {
+	key := emailCounter(email).key
+	return db.GetRedis().Del(key+":failures", key+":locked").Err()
+}
+
+// Unlock lets a member of the organization log in again right away, once their identity is confirmed by an admin
+func (m LockoutModel) Unlock(orgID, adminID, userID int64, client SessionClient) error {
+	if _, err := organizationModel.Role(orgID, userID); err != nil {
+		if errors.Is(err, ErrNotMember) {
+			return ErrUnknownMember
+		}
+		return err
+	}
+
+	user, err := userModel.One(userID)
+	if err != nil {
+		return err
+	}
+
+	if err = m.Succeed(user.Email); err != nil {
+		return err
+	}
+
+	auditModel.Record(userID, orgID, AuditLoginUnlocked, client, AuditDetails{"unlocked_by": adminID})
+	return nil
+}
@@ -0,0 +1,349 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-redis/redis/v7"
+)
+
+// TOTP parameters (RFC 6238), the defaults every authenticator app supports ...
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	//totpSkew is how many periods before and after the current one are accepted, for the clock drift of the phone
+	totpSkew = 1
+)
+
+// recoveryCodes is how many recovery codes the user gets when activating two-factor authentication
+const recoveryCodes = 10
+
+// mfaChallengeExpiration is how long the user has to enter their code after the password
+const mfaChallengeExpiration = 5 * time.Minute
+
+// mfaChallengeAttempts is how many wrong codes lock the user out, whatever the challenge they were sent for
+const mfaChallengeAttempts = 5
+
+// ErrMFAEnabled ...
+var ErrMFAEnabled = errors.New("two-factor authentication is already enabled")
+
+// ErrMFANotEnrolled ...
+var ErrMFANotEnrolled = errors.New("two-factor authentication is not enabled, please enroll first")
+
+// ErrInvalidMFACode ...
+var ErrInvalidMFACode = errors.New("the code is invalid or was already used")
+
+// ErrInvalidMFAChallenge ...
+var ErrInvalidMFAChallenge = errors.New("the login expired, please log in again")
+
+// base32NoPadding is the encoding of the secrets in the otpauth URIs
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAEnrollment is what the authenticator app is set up with
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAModel ...
+type MFAModel struct{}
+
+var mfaModel = new(MFAModel)
+
+// totpStep returns the TOTP code of the step (the count of periods since the epoch)
+func totpStep(secret string, step int64) (string, error) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	//Dynamic truncation (RFC 4226 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, code%1000000), nil
+}
+
+// TOTP returns the code of the secret at the time, as the authenticator app shows it
+func TOTP(secret string, at time.Time) (string, error) {
+	return totpStep(secret, at.Unix()/totpPeriod)
+}
+
+// verifyTOTP returns the step the code matches, around the current one and after lastStep so that a code is only used once
+func verifyTOTP(secret, code string, lastStep int64) (step int64, ok bool) {
+	current := time.Now().Unix() / totpPeriod
+
+	for step = current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := totpStep(secret, step)
+		if err == nil && hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// Enabled tells whether the user has activated two-factor authentication
+func (m MFAModel) Enabled(userID int64) (bool, error) {
+	count, err := db.GetDB().SelectInt("SELECT count(user_id) FROM public.user_mfa WHERE user_id=$1 AND enabled_at IS NOT NULL", userID)
+	return count > 0, err
+}
+
+// Enroll creates a new secret for the user, two-factor authentication is only enabled once a code of it is verified (Activate)
+func (m MFAModel) Enroll(userID int64) (enrollment MFAEnrollment, err error) {
+	enabled, err := m.Enabled(userID)
+	if err != nil {
+		return enrollment, err
+	}
+	if enabled {
+		return enrollment, ErrMFAEnabled
+	}
+
+	user, err := userModel.One(userID)
+	if err != nil {
+		return enrollment, err
+	}
+
+	key := make([]byte, 20)
+	if _, err = rand.Read(key); err != nil {
+		return enrollment, err
+	}
+	enrollment.Secret = base32NoPadding.EncodeToString(key)
+
+	_, err = db.GetDB().Exec("INSERT INTO public.user_mfa(user_id, secret) VALUES($1, $2) ON CONFLICT (user_id) DO UPDATE SET secret=EXCLUDED.secret, last_step=0, enabled_at=NULL", userID, enrollment.Secret)
+	if err != nil {
+		return enrollment, err
+	}
+
+	issuer := os.Getenv("MFA_ISSUER")
+	if issuer == "" {
+		issuer = "Gin Boilerplate"
+	}
+
+	query := url.Values{}
+	query.Set("secret", enrollment.Secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	enrollment.URI = "otpauth://totp/" + url.PathEscape(issuer+":"+user.Email) + "?" + query.Encode()
+
+	return enrollment, nil
+}
+
+// Activate enables two-factor authentication once the code of the enrolled secret is verified,
+// and returns the recovery codes, only shown this once
+func (m MFAModel) Activate(userID int64, form forms.MFACodeForm) (codes []string, err error) {
+	var mfa struct {
+		Secret   string        `db:"secret"`
+		LastStep int64         `db:"last_step"`
+		Enabled  sql.NullInt64 `db:"enabled_at"`
+	}
+	err = db.GetDB().SelectOne(&mfa, "SELECT secret, last_step, enabled_at FROM public.user_mfa WHERE user_id=$1", userID)
+	if err == sql.ErrNoRows {
+		return codes, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return codes, err
+	}
+	if mfa.Enabled.Valid {
+		return codes, ErrMFAEnabled
+	}
+
+	step, ok := verifyTOTP(mfa.Secret, form.Code, mfa.LastStep)
+	if !ok {
+		return codes, ErrInvalidMFACode
+	}
+
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return codes, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec("UPDATE public.user_mfa SET enabled_at=extract(epoch from now()), last_step=$2 WHERE user_id=$1", userID, step)
+	if err != nil {
+		return codes, err
+	}
+
+	_, err = tx.Exec("DELETE FROM public.user_recovery_code WHERE user_id=$1", userID)
+	if err != nil {
+		return codes, err
+	}
+
+	for i := 0; i < recoveryCodes; i++ {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return codes, err
+		}
+
+		_, err = tx.Exec("INSERT INTO public.user_recovery_code(user_id, code_hash) VALUES($1, $2)", userID, hashSecret(code))
+		if err != nil {
+			return codes, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	err = tx.Commit()
+	return codes, err
+}
+
+// randomRecoveryCode returns 80 random bits as four groups of base32, e.g. ABCD-EFGH-IJKL-MNOP
+func randomRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := base32NoPadding.EncodeToString(buf)
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+// Verify checks a TOTP code or, failing that, redeems one of the recovery codes of the user
+func (m MFAModel) Verify(userID int64, code string) error {
+	var mfa struct {
+		Secret   string `db:"secret"`
+		LastStep int64  `db:"last_step"`
+	}
+	err := db.GetDB().SelectOne(&mfa, "SELECT secret, last_step FROM public.user_mfa WHERE user_id=$1 AND enabled_at IS NOT NULL", userID)
+	if err == sql.ErrNoRows {
+		return ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	if step, ok := verifyTOTP(mfa.Secret, code, mfa.LastStep); ok {
+		//Only move forward, a concurrent use of the same code matches no row
+		operation, err := db.GetDB().Exec("UPDATE public.user_mfa SET last_step=$2 WHERE user_id=$1 AND last_step < $2", userID, step)
+		if err != nil {
+			return err
+		}
+		if updated, _ := operation.RowsAffected(); updated == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	operation, err := db.GetDB().Exec("UPDATE public.user_recovery_code SET used_at=extract(epoch from now()) WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL", userID, hashSecret(code))
+	if err != nil {
+		return err
+	}
+	if used, _ := operation.RowsAffected(); used == 0 {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// Disable turns two-factor authentication off once a code is verified, the recovery codes are deleted with it
+func (m MFAModel) Disable(userID int64, form forms.MFACodeForm) error {
+	err := m.Verify(userID, form.Code)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetDB().Exec("DELETE FROM public.user_mfa WHERE user_id=$1", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetDB().Exec("DELETE FROM public.user_recovery_code WHERE user_id=$1", userID)
+	return err
+}
+
+// Challenge returns the token the user completes the login with by sending a code (CompleteLogin)
+func (m MFAModel) Challenge(userID int64) (challenge string, err error) {
+	challenge, err = randomSecret("", 32)
+	if err != nil {
+		return challenge, err
+	}
+
+	err = db.GetRedis().Set("mfa:challenge:"+hashSecret(challenge), userID, mfaChallengeExpiration).Err()
+	return challenge, err
+}
+
+// mfaCounter counts the wrong codes of the user over all their challenges, a new password login does not start over
+func mfaCounter(userID int64) loginCounter {
+	return loginCounter{
+		key:          fmt.Sprintf("login:mfa:%d", userID),
+		freeAttempts: mfaChallengeAttempts - 1,
+		maxAttempts:  mfaChallengeAttempts,
+	}
+}
+
+// CompleteLogin checks the code for the challenge of the login and then logs the user in,
+// the challenge ends after a success or once the wrong codes locked the user out
+func (m MFAModel) CompleteLogin(form forms.MFALoginForm, client SessionClient) (user User, token Token, err error) {
+	key := "mfa:challenge:" + hashSecret(form.MFAToken)
+
+	userID, err := db.GetRedis().Get(key).Int64()
+	if err == redis.Nil {
+		return user, token, ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return user, token, err
+	}
+
+	//The code counts as wrong before it is checked, the codes sent at once are all counted
+	attempt, err := lockoutModel.reserve(mfaCounter(userID))
+	if err != nil {
+		return user, token, err
+	}
+
+	err = m.Verify(userID, form.Code)
+	if errors.Is(err, ErrInvalidMFACode) && attempt.Locked() {
+		db.GetRedis().Del(key)
+	}
+	if err != nil {
+		return user, token, err
+	}
+
+	attempt.Succeed()
+
+	//A challenge only logs in once
+	deleted, err := db.GetRedis().Del(key).Result()
+	if err != nil {
+		return user, token, err
+	}
+	if deleted == 0 {
+		return user, token, ErrInvalidMFAChallenge
+	}
+
+	user, err = userModel.One(userID)
+	if err != nil {
+		return user, token, err
+	}
+
+	token, err = userModel.issueToken(user, client)
+	return user, token, err
+}
@@ -0,0 +1,414 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+)
+
+// PermissionManageOAuthClients is never granted to an OAuth token or API key
+const PermissionManageOAuthClients = "oauth_client:manage"
+
+// oauthCodeExpiration is how long an authorization code can be exchanged, it is single use
+const oauthCodeExpiration = 10 * time.Minute
+
+// OAuth2 errors of the authorization and token endpoints (RFC 6749 4.1.2.1 and 5.2) ...
+var (
+	ErrInvalidClient       = errors.New("invalid_client")
+	ErrInvalidGrant        = errors.New("invalid_grant")
+	ErrInvalidScope        = errors.New("invalid_scope")
+	ErrUnauthorizedClient  = errors.New("unauthorized_client")
+	ErrInvalidRedirectURI  = errors.New("the redirect URI is not registered for this client")
+	ErrRedirectURIRequired = errors.New("a public client needs at least one redirect URI")
+)
+
+// OAuthClient ...
+// The secret is only returned once on creation to the confidential clients, the database only keeps its SHA-256 hash
+type OAuthClient struct {
+	ID           int64          `db:"id, primarykey, autoincrement" json:"id"`
+	Name         string         `db:"name" json:"name"`
+	ClientID     string         `db:"client_id" json:"client_id"`
+	ClientSecret string         `db:"-" json:"client_secret,omitempty"`
+	Confidential bool           `db:"confidential" json:"confidential"`
+	RedirectURIs pq.StringArray `db:"redirect_uris" json:"redirect_uris"`
+	Scopes       pq.StringArray `db:"scopes" json:"scopes"`
+	UpdatedAt    int64          `db:"updated_at" json:"updated_at"`
+	CreatedAt    int64          `db:"created_at" json:"created_at"`
+	User         *JSONRaw       `db:"user" json:"user"`
+}
+
+// OAuthConsent is what the user is asked to approve on the consent screen
+type OAuthConsent struct {
+	Client      string   `json:"client"`
+	ClientID    string   `json:"client_id"`
+	RedirectURI string   `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+}
+
+// OAuthToken is the successful response of the token endpoint (RFC 6749 5.1)
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// oauthClientRecord is a client as the token endpoint authenticates it
+type oauthClientRecord struct {
+	OrganizationID int64          `db:"organization_id"`
+	Name           string         `db:"name"`
+	UserID         int64          `db:"user_id"`
+	ClientID       string         `db:"client_id"`
+	SecretHash     *string        `db:"secret_hash"`
+	RedirectURIs   pq.StringArray `db:"redirect_uris"`
+	Scopes         pq.StringArray `db:"scopes"`
+}
+
+// oauthCode is what an authorization code stands for until it is exchanged
+type oauthCode struct {
+	ClientID       string   `json:"client_id"`
+	UserID         int64    `json:"user_id"`
+	OrganizationID int64    `json:"organization_id"`
+	RedirectURI    string   `json:"redirect_uri"`
+	Scopes         []string `json:"scopes"`
+	CodeChallenge  string   `json:"code_challenge"`
+}
+
+const oauthClientColumns = "c.id, c.name, c.client_id, c.secret_hash IS NOT NULL AS confidential, c.redirect_uris, c.scopes, c.updated_at, c.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user"
+
+// OAuthModel ...
+type OAuthModel struct{}
+
+// grantable returns ErrScopeNotGranted when a scope is not granted to the role or can not be delegated at all
+func grantable(role string, scopes []string) error {
+	for _, scope := range scopes {
+		granted, err := permissionModel.Can(role, scope)
+		if err != nil {
+			return err
+		}
+		if !granted || scope == PermissionManageAPIKeys || scope == PermissionManageOAuthClients {
+			return ErrScopeNotGranted
+		}
+	}
+	return nil
+}
+
+// subset tells whether every scope is one of the allowed ones
+func subset(scopes, allowed []string) bool {
+	for _, scope := range scopes {
+		found := false
+		for _, a := range allowed {
+			if a == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateClient registers a client of the organization, its scopes can not exceed the role of the user registering it
+func (m OAuthModel) CreateClient(orgID, userID int64, role string, form forms.CreateOAuthClientForm) (client OAuthClient, err error) {
+	if !form.Confidential && len(form.RedirectURIs) == 0 {
+		return client, ErrRedirectURIRequired
+	}
+
+	err = grantable(role, form.Scopes)
+	if err != nil {
+		return client, err
+	}
+
+	clientID, err := randomSecret("gbc_", 16)
+	if err != nil {
+		return client, err
+	}
+
+	var secret string
+	var secretHash *string
+	if form.Confidential {
+		secret, err = randomSecret("gbs_", 32)
+		if err != nil {
+			return client, err
+		}
+		hash := hashSecret(secret)
+		secretHash = &hash
+	}
+
+	redirectURIs := pq.StringArray{}
+	if form.RedirectURIs != nil {
+		redirectURIs = pq.StringArray(form.RedirectURIs)
+	}
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		var id int64
+		err = tx.QueryRow("INSERT INTO public.oauth_client(organization_id, user_id, name, client_id, secret_hash, redirect_uris, scopes) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id", orgID, userID, form.Name, clientID, secretHash, redirectURIs, pq.StringArray(form.Scopes)).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		return tx.SelectOne(&client, "SELECT "+oauthClientColumns+" FROM public.oauth_client c LEFT JOIN public.user u ON c.user_id = u.id WHERE c.organization_id=$1 AND c.id=$2", orgID, id)
+	})
+
+	client.ClientSecret = secret
+	return client, err
+}
+
+// Clients lists the clients registered by the organization
+func (m OAuthModel) Clients(orgID int64) (clients []OAuthClient, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&clients, "SELECT "+oauthClientColumns+" FROM public.oauth_client c LEFT JOIN public.user u ON c.user_id = u.id WHERE c.organization_id=$1 ORDER BY c.id DESC", orgID)
+		return err
+	})
+	return clients, err
+}
+
+// client returns the client with the ID, ErrInvalidClient when there is none.
+// The clients are authorized by the users of any organization, so the lookup runs outside of a tenant transaction
+func (m OAuthModel) client(clientID string) (client oauthClientRecord, err error) {
+	err = db.GetDB().SelectOne(&client, "SELECT organization_id, name, user_id, client_id, secret_hash, redirect_uris, scopes FROM public.oauth_client WHERE client_id=$1", clientID)
+	if err != nil {
+		return client, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// authenticate returns the client when the secret matches, a public client has none to send
+func (m OAuthModel) authenticate(clientID, secret string) (client oauthClientRecord, err error) {
+	client, err = m.client(clientID)
+	if err != nil {
+		return client, err
+	}
+
+	if client.SecretHash == nil {
+		if secret != "" {
+			return client, ErrInvalidClient
+		}
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(*client.SecretHash)) != 1 {
+		return client, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// Consent checks the authorization request and returns what the user is asked to approve
+func (m OAuthModel) Consent(role string, form forms.AuthorizeForm) (consent OAuthConsent, err error) {
+	client, err := m.client(form.ClientID)
+	if err != nil {
+		return consent, err
+	}
+
+	//The redirect URI is matched exactly, never redirect anywhere else
+	if !subset([]string{form.RedirectURI}, client.RedirectURIs) {
+		return consent, ErrInvalidRedirectURI
+	}
+
+	scopes := strings.Fields(form.Scope)
+	if len(scopes) == 0 || !subset(scopes, client.Scopes) {
+		return consent, ErrInvalidScope
+	}
+	err = grantable(role, scopes)
+	if errors.Is(err, ErrScopeNotGranted) {
+		return consent, ErrInvalidScope
+	}
+	if err != nil {
+		return consent, err
+	}
+
+	consent.Client = client.Name
+	consent.ClientID = client.ClientID
+	consent.RedirectURI = form.RedirectURI
+	consent.Scopes = scopes
+
+	return consent, nil
+}
+
+// Authorize answers the authorization request of the user in the organization with the URL to redirect them to,
+// carrying a single use authorization code when approved and access_denied otherwise
+func (m OAuthModel) Authorize(orgID, userID int64, role string, form forms.AuthorizeForm) (redirect string, err error) {
+	consent, err := m.Consent(role, form)
+	if err != nil {
+		return redirect, err
+	}
+
+	redirectURL, err := url.Parse(consent.RedirectURI)
+	if err != nil {
+		return redirect, ErrInvalidRedirectURI
+	}
+	query := redirectURL.Query()
+	if form.State != "" {
+		query.Set("state", form.State)
+	}
+
+	if !form.Approve {
+		query.Set("error", "access_denied")
+		redirectURL.RawQuery = query.Encode()
+		return redirectURL.String(), nil
+	}
+
+	code, err := randomSecret("", 32)
+	if err != nil {
+		return redirect, err
+	}
+
+	data, err := json.Marshal(oauthCode{
+		ClientID:       consent.ClientID,
+		UserID:         userID,
+		OrganizationID: orgID,
+		RedirectURI:    consent.RedirectURI,
+		Scopes:         consent.Scopes,
+		CodeChallenge:  form.CodeChallenge,
+	})
+	if err != nil {
+		return redirect, err
+	}
+
+	err = db.GetRedis().Set("oauth:code:"+hashSecret(code), data, oauthCodeExpiration).Err()
+	if err != nil {
+		return redirect, err
+	}
+
+	query.Set("code", code)
+	redirectURL.RawQuery = query.Encode()
+
+	return redirectURL.String(), nil
+}
+
+// Token issues the tokens of a grant, the errors are the OAuth2 error codes of RFC 6749 5.2.
+// device is the one of the request, recorded when a refresh token is reused
+func (m OAuthModel) Token(form forms.OAuthTokenForm, device SessionClient) (token OAuthToken, err error) {
+	client, err := m.authenticate(form.ClientID, form.ClientSecret)
+	if err != nil {
+		return token, err
+	}
+
+	switch form.GrantType {
+	case "authorization_code":
+		return m.exchangeCode(client, form)
+	case "client_credentials":
+		return m.clientCredentials(client, form)
+	default:
+		return m.refresh(client, form, device)
+	}
+}
+
+// exchangeCode redeems an authorization code once, checking its PKCE verifier (RFC 7636 4.6)
+func (m OAuthModel) exchangeCode(client oauthClientRecord, form forms.OAuthTokenForm) (token OAuthToken, err error) {
+	key := "oauth:code:" + hashSecret(form.Code)
+
+	//Read and delete at once, a code is only redeemed a single time
+	pipe := db.GetRedis().TxPipeline()
+	get := pipe.Get(key)
+	pipe.Del(key)
+	if _, err = pipe.Exec(); err != nil {
+		return token, ErrInvalidGrant
+	}
+
+	var code oauthCode
+	if err = json.Unmarshal([]byte(get.Val()), &code); err != nil {
+		return token, ErrInvalidGrant
+	}
+
+	if code.ClientID != client.ClientID || code.RedirectURI != form.RedirectURI {
+		return token, ErrInvalidGrant
+	}
+
+	challenge := sha256.Sum256([]byte(form.CodeVerifier))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != code.CodeChallenge {
+		return token, ErrInvalidGrant
+	}
+
+	//The user may have left the organization since approving
+	if _, err = organizationModel.Role(code.OrganizationID, code.UserID); err != nil {
+		return token, ErrInvalidGrant
+	}
+
+	return m.issue(code.UserID, code.OrganizationID, client.ClientID, code.Scopes, true)
+}
+
+// clientCredentials issues an access token to a confidential client acting on its own,
+// as the user who registered it in its organization and limited to its scopes
+func (m OAuthModel) clientCredentials(client oauthClientRecord, form forms.OAuthTokenForm) (token OAuthToken, err error) {
+	if client.SecretHash == nil {
+		return token, ErrUnauthorizedClient
+	}
+
+	scopes := []string(client.Scopes)
+	if form.Scope != "" {
+		scopes = strings.Fields(form.Scope)
+		if !subset(scopes, client.Scopes) {
+			return token, ErrInvalidScope
+		}
+	}
+
+	if _, err = organizationModel.Role(client.OrganizationID, client.UserID); err != nil {
+		return token, ErrInvalidClient
+	}
+
+	//No refresh token, the client asks for a new access token with its credentials (RFC 6749 4.4.3)
+	return m.issue(client.UserID, client.OrganizationID, client.ClientID, scopes, false)
+}
+
+// refresh rotates a refresh token issued to the client
+func (m OAuthModel) refresh(client oauthClientRecord, form forms.OAuthTokenForm, device SessionClient) (token OAuthToken, err error) {
+	tokenDetails, err := authModel.Refresh(form.RefreshToken, client.ClientID, device)
+	if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) {
+		return token, ErrInvalidGrant
+	}
+	if err != nil {
+		return token, err
+	}
+
+	return oauthToken(tokenDetails, true), nil
+}
+
+// issue creates and stores the tokens of the client
+func (m OAuthModel) issue(userID, orgID int64, clientID string, scopes []string, withRefresh bool) (token OAuthToken, err error) {
+	tokenDetails, err := authModel.CreateScopedToken(userID, orgID, clientID, scopes)
+	if err != nil {
+		return token, err
+	}
+
+	//Without a refresh token the session ends with the access token
+	if !withRefresh {
+		tokenDetails.RtExpires = tokenDetails.AtExpires
+	}
+
+	err = authModel.CreateAuth(userID, tokenDetails)
+	if err != nil {
+		return token, err
+	}
+
+	if !withRefresh {
+		authModel.DeleteAuth(tokenDetails.RefreshUUID)
+	}
+
+	return oauthToken(tokenDetails, withRefresh), nil
+}
+
+// oauthToken ...
+func oauthToken(tokenDetails *TokenDetails, withRefresh bool) (token OAuthToken) {
+	token.AccessToken = tokenDetails.AccessToken
+	token.TokenType = "Bearer"
+	token.ExpiresIn = tokenDetails.AtExpires - time.Now().Unix()
+	token.Scope = strings.Join(tokenDetails.Scopes, " ")
+	if withRefresh {
+		token.RefreshToken = tokenDetails.RefreshToken
+	}
+	return token
+}
@@ -0,0 +1,585 @@
+package models
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// Provider types ...
+const (
+	ProviderOIDC   = "oidc"
+	ProviderGitHub = "github"
+)
+
+// oidcStateExpiration is how long the user has to log in at the provider
+const oidcStateExpiration = 10 * time.Minute
+
+// oidcKeysExpiration is how long the discovery document and signing keys of an issuer are kept in memory
+const oidcKeysExpiration = time.Hour
+
+// ErrUnknownProvider ...
+var ErrUnknownProvider = errors.New("this login provider is not configured")
+
+// ErrInvalidState ...
+var ErrInvalidState = errors.New("the login expired or was already used, please try again")
+
+// ErrInvalidIDToken ...
+var ErrInvalidIDToken = errors.New("the identity of the provider could not be verified")
+
+// ErrEmailNotVerified ...
+var ErrEmailNotVerified = errors.New("the provider did not verify your email, please verify it there first")
+
+// oidcClient is used for every call to the providers
+var oidcClient = &http.Client{Timeout: 10 * time.Second}
+
+// OIDCProvider ...
+// An OpenID Connect issuer (Google, or any other through its discovery document) or GitHub, which only has OAuth2
+type OIDCProvider struct {
+	Name         string
+	Type         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	Scopes       []string
+}
+
+// OIDCProvidersFromEnv reads the providers listed in OIDC_PROVIDERS (e.g. "google,github"), each configured with
+// OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URI and, except for google and github, OIDC_<NAME>_ISSUER
+func OIDCProvidersFromEnv() map[string]OIDCProvider {
+	providers := map[string]OIDCProvider{}
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+
+		provider := OIDCProvider{
+			Name:         name,
+			Type:         ProviderOIDC,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURI:  os.Getenv(prefix + "REDIRECT_URI"),
+			Scopes:       []string{"openid", "email", "profile"},
+		}
+
+		switch name {
+		case "google":
+			if provider.Issuer == "" {
+				provider.Issuer = "https://accounts.google.com"
+			}
+		case "github":
+			provider.Type = ProviderGitHub
+			provider.Scopes = []string{"read:user", "user:email"}
+		}
+
+		providers[name] = provider
+	}
+
+	return providers
+}
+
+// oidcIssuer is the discovery document of an issuer with its signing keys
+type oidcIssuer struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	keys                  map[string]*rsa.PublicKey
+	fetchedAt             time.Time
+}
+
+// oidcIssuers caches the issuers by their URL
+var oidcIssuers = struct {
+	sync.Mutex
+	issuers map[string]*oidcIssuer
+}{issuers: map[string]*oidcIssuer{}}
+
+// getJSON decodes the JSON response of a GET request
+func getJSON(uri string, header http.Header, v interface{}) error {
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return err
+	}
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oidcClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", uri, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// discover returns the issuer with its signing keys, fetched again once expired or when refresh is set (an unknown key ID)
+func discover(issuerURL string, refresh bool) (*oidcIssuer, error) {
+	oidcIssuers.Lock()
+	defer oidcIssuers.Unlock()
+
+	issuer, ok := oidcIssuers.issuers[issuerURL]
+	if ok && !refresh && time.Since(issuer.fetchedAt) < oidcKeysExpiration {
+		return issuer, nil
+	}
+
+	issuer = &oidcIssuer{}
+	err := getJSON(strings.TrimSuffix(issuerURL, "/")+"/.well-known/openid-configuration", nil, issuer)
+	if err != nil {
+		return nil, err
+	}
+	//The document must be the one of the configured issuer (OpenID Connect Discovery 4.3)
+	if issuer.Issuer != issuerURL {
+		return nil, fmt.Errorf("the discovery document is for %s, not %s", issuer.Issuer, issuerURL)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	err = getJSON(issuer.JWKSURI, nil, &jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer.keys = map[string]*rsa.PublicKey{}
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(key.N)
+		e, errE := base64.RawURLEncoding.DecodeString(key.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		issuer.keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	issuer.fetchedAt = time.Now()
+
+	oidcIssuers.issuers[issuerURL] = issuer
+	return issuer, nil
+}
+
+// oidcState is what the state of a login stands for until the provider redirects back
+type oidcState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// OIDCIdentity is the user as the provider knows them
+type OIDCIdentity struct {
+	Subject string
+	Email   string
+	Name    string
+}
+
+// OIDCModel ...
+type OIDCModel struct{}
+
+// provider returns the configured provider, ErrUnknownProvider when there is none with the name
+func (m OIDCModel) provider(name string) (provider OIDCProvider, err error) {
+	provider, ok := OIDCProvidersFromEnv()[name]
+	if !ok || provider.ClientID == "" {
+		return provider, ErrUnknownProvider
+	}
+	if provider.Type == ProviderOIDC && provider.Issuer == "" {
+		return provider, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// Providers lists the names of the configured providers
+func (m OIDCModel) Providers() (names []string) {
+	names = []string{}
+	for name := range OIDCProvidersFromEnv() {
+		if _, err := m.provider(name); err == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AuthURL starts a login at the provider: it returns the URL to send the user to,
+// the state, nonce and PKCE verifier of the login are kept in Redis until the callback
+func (m OIDCModel) AuthURL(name string) (authURL string, err error) {
+	provider, err := m.provider(name)
+	if err != nil {
+		return authURL, err
+	}
+
+	endpoint := "https://github.com/login/oauth/authorize"
+	if provider.Type == ProviderOIDC {
+		issuer, err := discover(provider.Issuer, false)
+		if err != nil {
+			return authURL, err
+		}
+		endpoint = issuer.AuthorizationEndpoint
+	}
+
+	state, err := randomSecret("", 24)
+	if err != nil {
+		return authURL, err
+	}
+	login := oidcState{Provider: provider.Name}
+	if login.Nonce, err = randomSecret("", 24); err != nil {
+		return authURL, err
+	}
+	if login.CodeVerifier, err = randomSecret("", 32); err != nil {
+		return authURL, err
+	}
+
+	data, err := json.Marshal(login)
+	if err != nil {
+		return authURL, err
+	}
+	err = db.GetRedis().Set("oidc:state:"+state, data, oidcStateExpiration).Err()
+	if err != nil {
+		return authURL, err
+	}
+
+	challenge := sha256.Sum256([]byte(login.CodeVerifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientID)
+	query.Set("redirect_uri", provider.RedirectURI)
+	query.Set("scope", strings.Join(provider.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", login.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	return endpoint + "?" + query.Encode(), nil
+}
+
+// exchange redeems the code at the token endpoint of the provider
+func (m OIDCModel) exchange(provider OIDCProvider, endpoint, code, verifier string) (tokens struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}, err error) {
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("redirect_uri", provider.RedirectURI)
+	values.Set("client_id", provider.ClientID)
+	values.Set("client_secret", provider.ClientSecret)
+	values.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return tokens, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oidcClient.Do(req)
+	if err != nil {
+		return tokens, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return tokens, ErrInvalidIDToken
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&tokens)
+	return tokens, err
+}
+
+// idTokenClaims ...
+// The claims of an ID token (OpenID Connect Core 1.0, section 2), exp, iat and sub are required by Valid,
+// iss, aud and nonce are checked by verifyIDToken against the provider and the login
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// Valid is called by the parser once the signature is verified
+func (c idTokenClaims) Valid() error {
+	now := jwt.TimeFunc()
+
+	if !c.VerifyExpiresAt(now, true) {
+		return jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	}
+	if !c.VerifyIssuedAt(now.Add(tokenClockSkew), true) {
+		return jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	}
+	if !c.VerifyNotBefore(now.Add(tokenClockSkew), false) {
+		return jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	}
+	if c.Subject == "" {
+		return jwt.NewValidationError("token has no subject", jwt.ValidationErrorClaimsInvalid)
+	}
+
+	return nil
+}
+
+// verifyIDToken checks the signature of the ID token with the keys of the issuer, its issuer, audience, expiry and nonce
+func (m OIDCModel) verifyIDToken(provider OIDCProvider, issuer *oidcIssuer, idToken, nonce string) (identity OIDCIdentity, err error) {
+	claims := &idTokenClaims{}
+	token, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := issuer.keys[kid]; ok {
+			return key, nil
+		}
+		//The issuer may have rotated its keys since they were fetched
+		refreshed, err := discover(provider.Issuer, true)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := refreshed.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	})
+	if err != nil || !token.Valid {
+		return identity, ErrInvalidIDToken
+	}
+
+	if claims.Issuer != issuer.Issuer || !claims.VerifyAudience(provider.ClientID, true) {
+		return identity, ErrInvalidIDToken
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return identity, ErrInvalidIDToken
+	}
+
+	identity.Subject = claims.Subject
+	identity.Email = claims.Email
+	identity.Name = claims.Name
+
+	if !claims.EmailVerified || identity.Email == "" {
+		return identity, ErrEmailNotVerified
+	}
+
+	return identity, nil
+}
+
+// githubIdentity reads the user and their primary verified email from the GitHub API
+func (m OIDCModel) githubIdentity(accessToken string) (identity OIDCIdentity, err error) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+accessToken)
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err = getJSON("https://api.github.com/user", header, &user); err != nil {
+		return identity, ErrInvalidIDToken
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err = getJSON("https://api.github.com/user/emails", header, &emails); err != nil {
+		return identity, ErrInvalidIDToken
+	}
+
+	identity.Subject = fmt.Sprintf("%d", user.ID)
+	identity.Name = user.Name
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+
+	for _, email := range emails {
+		if email.Primary && email.Verified {
+			identity.Email = email.Email
+			return identity, nil
+		}
+	}
+
+	return identity, ErrEmailNotVerified
+}
+
+// Callback finishes the login at the provider: the state is redeemed once, the code exchanged and the identity verified,
+// then the user linked to it is logged in with the usual pair of tokens, or the challenge of their two-factor authentication
+func (m OIDCModel) Callback(name string, form forms.OIDCCallbackForm, client SessionClient) (user User, token Token, challenge string, err error) {
+	provider, err := m.provider(name)
+	if err != nil {
+		return user, token, challenge, err
+	}
+
+	key := "oidc:state:" + form.State
+
+	//Read and delete at once, a state is only redeemed a single time
+	pipe := db.GetRedis().TxPipeline()
+	get := pipe.Get(key)
+	pipe.Del(key)
+	if _, err = pipe.Exec(); err != nil {
+		return user, token, challenge, ErrInvalidState
+	}
+
+	var login oidcState
+	if err = json.Unmarshal([]byte(get.Val()), &login); err != nil || login.Provider != provider.Name {
+		return user, token, challenge, ErrInvalidState
+	}
+
+	var identity OIDCIdentity
+
+	if provider.Type == ProviderGitHub {
+		tokens, err := m.exchange(provider, "https://github.com/login/oauth/access_token", form.Code, login.CodeVerifier)
+		if err != nil {
+			return user, token, challenge, ErrInvalidIDToken
+		}
+		identity, err = m.githubIdentity(tokens.AccessToken)
+		if err != nil {
+			return user, token, challenge, err
+		}
+	} else {
+		issuer, err := discover(provider.Issuer, false)
+		if err != nil {
+			return user, token, challenge, err
+		}
+		tokens, err := m.exchange(provider, issuer.TokenEndpoint, form.Code, login.CodeVerifier)
+		if err != nil {
+			return user, token, challenge, ErrInvalidIDToken
+		}
+		identity, err = m.verifyIDToken(provider, issuer, tokens.IDToken, login.Nonce)
+		if err != nil {
+			return user, token, challenge, err
+		}
+	}
+
+	user, err = m.link(provider.Name, identity)
+	if err != nil {
+		return user, token, challenge, err
+	}
+
+	token, challenge, err = userModel.login(user, client)
+	return user, token, challenge, err
+}
+
+// link returns the user of the identity: the one it was linked to before, else the user with its verified email,
+// else a new user without a password (and their own organization, as on register)
+func (m OIDCModel) link(provider string, identity OIDCIdentity) (user User, err error) {
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return user, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.SelectOne(&user, "SELECT u.id, u.email, u.name FROM public.user u INNER JOIN public.user_identity ui ON ui.user_id = u.id WHERE ui.provider=$1 AND ui.subject=$2", provider, identity.Subject)
+	if err == nil {
+		_, err = tx.Exec("UPDATE public.user_identity SET email=LOWER($1) WHERE provider=$2 AND subject=$3", identity.Email, provider, identity.Subject)
+		if err != nil {
+			return user, err
+		}
+		err = tx.Commit()
+		return user, err
+	}
+	if err != sql.ErrNoRows {
+		return user, err
+	}
+
+	var unverified int64
+	err = tx.SelectOne(&user, "SELECT id, email, name FROM public.user WHERE email=LOWER($1) LIMIT 1", identity.Email)
+	if err == nil {
+		unverified, err = tx.SelectInt("SELECT count(id) FROM public.user WHERE id=$1 AND verified_at IS NULL", user.ID)
+	}
+	if err == nil && unverified > 0 {
+		err = m.reclaim(tx, user.ID)
+	}
+	if err == sql.ErrNoRows {
+		if identity.Name == "" {
+			identity.Name = strings.Split(identity.Email, "@")[0]
+		}
+		user = User{Email: strings.ToLower(identity.Email), Name: identity.Name}
+
+		err = tx.QueryRow("INSERT INTO public.user(email, name) VALUES(LOWER($1), $2) RETURNING id", identity.Email, identity.Name).Scan(&user.ID)
+		if err != nil {
+			return user, err
+		}
+
+		_, err = organizationModel.create(tx, user.ID, identity.Name)
+	}
+	if err != nil {
+		return user, err
+	}
+
+	_, err = tx.Exec("INSERT INTO public.user_identity(user_id, provider, subject, email) VALUES($1, $2, $3, LOWER($4))", user.ID, provider, identity.Subject, identity.Email)
+	if err != nil {
+		return user, err
+	}
+
+	//The provider verified the email the user is linked by
+	_, err = tx.Exec("UPDATE public.user SET verified_at=extract(epoch from now()) WHERE id=$1 AND verified_at IS NULL", user.ID)
+	if err != nil {
+		return user, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return user, err
+	}
+
+	if unverified > 0 {
+		err = sessionModel.RevokeAll(user.ID)
+	}
+	return user, err
+}
+
+// reclaim takes back an account registered with the email but never verified, maybe by someone else than the owner
+// of the email who now signs in with the provider: whatever was set up without the email is dropped, the password,
+// the two-factor authentication and the API keys, and the sessions end once the identity is linked
+func (m OIDCModel) reclaim(tx gorp.SqlExecutor, userID int64) (err error) {
+	_, err = tx.Exec("UPDATE public.user SET password=NULL WHERE id=$1", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM public.user_mfa WHERE user_id=$1", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM public.user_recovery_code WHERE user_id=$1", userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE public.api_key SET revoked_at=COALESCE(revoked_at, extract(epoch from now())) WHERE user_id=$1", userID)
+	return err
+}
@@ -2,77 +2,308 @@ package models
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 )
 
+// ErrCustomerNotFound ...
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// ErrProductNotFound ...
+var ErrProductNotFound = errors.New("one or more products were not found or are not active")
+
+// ErrVariantNotFound ...
+var ErrVariantNotFound = errors.New("one or more variants were not found, are not active or belong to another product")
+
 // Order ...
+// All the amounts are stored as integers in the minor unit of the order currency (e.g. cents)
 type Order struct {
-	ID        int64    `db:"id, primarykey, autoincrement" json:"id"`
-	UserID    int64    `db:"user_id" json:"-"`
-	Title     string   `db:"title" json:"title"`
-	Content   string   `db:"content" json:"content"`
-	UpdatedAt int64    `db:"updated_at" json:"updated_at"`
-	CreatedAt int64    `db:"created_at" json:"created_at"`
-	User      *JSONRaw `db:"user" json:"user"`
+	ID         int64       `db:"id, primarykey, autoincrement" json:"id"`
+	UserID     int64       `db:"user_id" json:"-"`
+	CustomerID int64       `db:"customer_id" json:"customer_id"`
+	Number     string      `db:"number" json:"number"`
+	Status     string      `db:"status" json:"status"`
+	Currency   string      `db:"currency" json:"currency"`
+	Subtotal   int64       `db:"subtotal" json:"subtotal"`
+	TaxTotal   int64       `db:"tax_total" json:"tax_total"`
+	Total      int64       `db:"total" json:"total"`
+	Notes      string      `db:"notes" json:"notes"`
+	UpdatedAt  int64       `db:"updated_at" json:"updated_at"`
+	CreatedAt  int64       `db:"created_at" json:"created_at"`
+	User       *JSONRaw    `db:"user" json:"user"`
+	Items      []OrderItem `db:"-" json:"items"`
+}
+
+// OrderItem ...
+// VariantID is null when the product itself is ordered and TaxRate is in basis points (2000 = 20%).
+// Allocated is the quantity on shipments, Shipped the part of it that left the warehouse
+// and Remaining what still needs a shipment
+type OrderItem struct {
+	ID        int64  `db:"id, primarykey, autoincrement" json:"id"`
+	OrderID   int64  `db:"order_id" json:"-"`
+	ProductID int64  `db:"product_id" json:"product_id"`
+	VariantID *int64 `db:"variant_id" json:"variant_id"`
+	Quantity  int64  `db:"quantity" json:"quantity"`
+	UnitPrice int64  `db:"unit_price" json:"unit_price"`
+	TaxRate   int64  `db:"tax_rate" json:"tax_rate"`
+	Subtotal  int64  `db:"subtotal" json:"subtotal"`
+	Tax       int64  `db:"tax" json:"tax"`
+	Total     int64  `db:"total" json:"total"`
+	Allocated int64  `db:"allocated" json:"allocated"`
+	Shipped   int64  `db:"shipped" json:"shipped"`
+	Remaining int64  `db:"remaining" json:"remaining"`
+}
+
+// basisPoints returns the rounded (half up) share of amount for the given rate in basis points
+// without overflowing int64 for large amounts
+func basisPoints(amount, rate int64) int64 {
+	return amount/10000*rate + (amount%10000*rate+5000)/10000
+}
+
+// optionalID turns the zero ID of an optional form reference into null
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// calculate fills the line subtotal, tax and total from its quantity, unit price and tax rate
+func (item *OrderItem) calculate() {
+	item.Subtotal = item.Quantity * item.UnitPrice
+	item.Tax = basisPoints(item.Subtotal, item.TaxRate)
+	item.Total = item.Subtotal + item.Tax
+}
+
+// calculate computes every line and sums them up into the order totals
+func (order *Order) calculate() {
+	order.Subtotal, order.TaxTotal, order.Total = 0, 0, 0
+
+	for i := range order.Items {
+		order.Items[i].calculate()
+
+		order.Subtotal += order.Items[i].Subtotal
+		order.TaxTotal += order.Items[i].Tax
+		order.Total += order.Items[i].Total
+	}
 }
 
 // OrderModel ...
 type OrderModel struct{}
 
-// Create ...
-func (m OrderModel) Create(userID int64, form forms.CreateOrderForm) (orderID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.order(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&orderID)
-	return orderID, err
+var orderModel = new(OrderModel)
+
+// fromForm validates the references of the form against the organization records and builds the order with its totals
+func (m OrderModel) fromForm(exec gorp.SqlExecutor, orgID int64, form forms.CreateOrderForm) (order Order, err error) {
+	customers, err := exec.SelectInt("SELECT count(id) FROM public.customer WHERE organization_id=$1 AND id=$2", orgID, form.CustomerID)
+	if err != nil {
+		return order, err
+	}
+	if customers == 0 {
+		return order, ErrCustomerNotFound
+	}
+
+	productIDs := []int64{}
+	seen := map[int64]bool{}
+	//The variants are checked as (variant, product) pairs so a variant can not be ordered under another product
+	variantIDs, variantProductIDs := []int64{}, []int64{}
+	seenVariants := map[int64]bool{}
+	for _, item := range form.Items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			productIDs = append(productIDs, item.ProductID)
+		}
+		if item.VariantID != 0 && !seenVariants[item.VariantID] {
+			seenVariants[item.VariantID] = true
+			variantIDs = append(variantIDs, item.VariantID)
+			variantProductIDs = append(variantProductIDs, item.ProductID)
+		}
+	}
+
+	products, err := exec.SelectInt("SELECT count(id) FROM public.product WHERE organization_id=$1 AND active AND id = ANY($2)", orgID, pq.Array(productIDs))
+	if err != nil {
+		return order, err
+	}
+	if products != int64(len(productIDs)) {
+		return order, ErrProductNotFound
+	}
+
+	if len(variantIDs) > 0 {
+		var variants int64
+		variants, err = exec.SelectInt("SELECT count(v.id) FROM public.product_variant v INNER JOIN unnest($2::int[], $3::int[]) AS f(variant_id, product_id) ON v.id = f.variant_id AND v.product_id = f.product_id WHERE v.organization_id=$1 AND v.active", orgID, pq.Array(variantIDs), pq.Array(variantProductIDs))
+		if err != nil {
+			return order, err
+		}
+		if variants != int64(len(variantIDs)) {
+			return order, ErrVariantNotFound
+		}
+	}
+
+	order.CustomerID = form.CustomerID
+	order.Currency = form.Currency
+	order.Notes = form.Notes
+
+	for _, item := range form.Items {
+		order.Items = append(order.Items, OrderItem{
+			ProductID: item.ProductID,
+			VariantID: optionalID(item.VariantID),
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			TaxRate:   item.TaxRate,
+		})
+	}
+
+	order.calculate()
+
+	return order, nil
+}
+
+// insertItems ...
+func (m OrderModel) insertItems(exec gorp.SqlExecutor, orderID int64, items []OrderItem) (err error) {
+	for _, item := range items {
+		_, err = exec.Exec("INSERT INTO public.order_item(order_id, product_id, variant_id, quantity, unit_price, tax_rate, subtotal, tax, total) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)", orderID, item.ProductID, item.VariantID, item.Quantity, item.UnitPrice, item.TaxRate, item.Subtotal, item.Tax, item.Total)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Create inserts the order with its items in a single transaction and returns the full order
+func (m OrderModel) Create(orgID, userID int64, form forms.CreateOrderForm) (order Order, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return order, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err = m.fromForm(tx, orgID, form)
+	if err != nil {
+		return order, err
+	}
+	order.UserID = userID
+
+	//Reserve the ID first so the order number can be stored with the same insert
+	err = tx.QueryRow("SELECT nextval('public.order_id_seq')").Scan(&order.ID)
+	if err != nil {
+		return order, err
+	}
+	order.Number = fmt.Sprintf("ORD-%06d", order.ID)
+
+	_, err = tx.Exec("INSERT INTO public.order(id, organization_id, user_id, customer_id, number, currency, subtotal, tax_total, total, notes) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)", order.ID, orgID, userID, order.CustomerID, order.Number, order.Currency, order.Subtotal, order.TaxTotal, order.Total, order.Notes)
+	if err != nil {
+		return order, err
+	}
+
+	err = m.insertItems(tx, order.ID, order.Items)
+	if err != nil {
+		return order, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return order, err
+	}
+
+	return m.One(orgID, order.ID)
+}
+
+// one ...
+func (m OrderModel) one(exec gorp.SqlExecutor, orgID, id int64) (order Order, err error) {
+	err = exec.SelectOne(&order, "SELECT a.id, a.customer_id, a.number, a.status, a.currency, a.subtotal, a.tax_total, a.total, COALESCE(a.notes, '') AS notes, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.order a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+	if err != nil {
+		return order, err
+	}
+
+	_, err = exec.Select(&order.Items, "SELECT i.id, i.product_id, i.variant_id, i.quantity, i.unit_price, i.tax_rate, i.subtotal, i.tax, i.total, p.allocated, p.shipped, i.quantity - p.allocated AS remaining FROM public.order_item i, LATERAL (SELECT COALESCE(SUM(si.quantity), 0) AS allocated, COALESCE(SUM(si.quantity) FILTER (WHERE s.status = ANY($2)), 0) AS shipped FROM public.shipment_item si INNER JOIN public.shipment s ON si.shipment_id = s.id WHERE si.order_item_id = i.id) p WHERE i.order_id=$1 ORDER BY i.id", order.ID, pq.Array(shippedStatuses))
+	return order, err
 }
 
 // One ...
-func (m OrderModel) One(userID, id int64) (order Order, err error) {
-	err = db.GetDB().SelectOne(&order, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.order a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
+func (m OrderModel) One(orgID, id int64) (order Order, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		order, err = m.one(tx, orgID, id)
+		return err
+	})
 	return order, err
 }
 
 // All ...
-func (m OrderModel) All(userID int64) (orders []DataList, err error) {
-	_, err = db.GetDB().Select(&orders, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.order AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.order a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
+func (m OrderModel) All(orgID int64) (orders []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&orders, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.order AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.customer_id, a.number, a.status, a.currency, a.subtotal, a.tax_total, a.total, COALESCE(a.notes, '') AS notes, a.updated_at, a.created_at, (SELECT COALESCE(json_agg(json_build_object('id', i.id, 'product_id', i.product_id, 'variant_id', i.variant_id, 'quantity', i.quantity, 'unit_price', i.unit_price, 'tax_rate', i.tax_rate, 'subtotal', i.subtotal, 'tax', i.tax, 'total', i.total) ORDER BY i.id), '[]') FROM public.order_item i WHERE i.order_id = a.id) AS items, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.order a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 ORDER BY a.id DESC) d", orgID)
+		return err
+	})
 	return orders, err
 }
 
-// Update ...
-func (m OrderModel) Update(userID int64, id int64, form forms.CreateOrderForm) (err error) {
-	//METHOD 1
-	//Check the order by ID using this way
-	// _, err = m.One(userID, id)
-	// if err != nil {
-	// 	return err
-	// }
-
-	operation, err := db.GetDB().Exec("UPDATE public.order SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
+// Update replaces the order customer, currency, notes and items and recalculates its totals
+func (m OrderModel) Update(orgID int64, id int64, form forms.CreateOrderForm) (err error) {
+	tx, err := db.BeginTenant(orgID)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
+	//The status can only be changed through Transition, and only draft orders can be edited
+	status, err := m.lockStatus(tx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if status != OrderStatusDraft {
+		err = ErrOrderLocked
+		return err
 	}
 
-	return err
-}
+	order, err := m.fromForm(tx, orgID, form)
+	if err != nil {
+		return err
+	}
 
-// Delete ...
-func (m OrderModel) Delete(userID, id int64) (err error) {
+	_, err = tx.Exec("UPDATE public.order SET customer_id=$3, currency=$4, subtotal=$5, tax_total=$6, total=$7, notes=$8 WHERE id=$1 AND organization_id=$2", id, orgID, order.CustomerID, order.Currency, order.Subtotal, order.TaxTotal, order.Total, order.Notes)
+	if err != nil {
+		return err
+	}
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.order WHERE id=$1", id)
+	_, err = tx.Exec("DELETE FROM public.order_item WHERE order_id=$1", id)
 	if err != nil {
 		return err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
+	err = m.insertItems(tx, id, order.Items)
+	if err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
+}
+
+// Delete ...
+func (m OrderModel) Delete(orgID, id int64) (err error) {
+
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.order WHERE id=$1 AND organization_id=$2 AND status=$3", id, orgID, OrderStatusDraft)
+		if err != nil {
+			return err
+		}
+
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
+
+		return nil
+	})
 }
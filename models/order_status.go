@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+)
+
+// Order statuses ...
+const (
+	OrderStatusDraft     = "draft"
+	OrderStatusPlaced    = "placed"
+	OrderStatusPaid      = "paid"
+	OrderStatusFulfilled = "fulfilled"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// orderTransitions lists for each status the statuses an order can move to, anything else is rejected
+var orderTransitions = map[string][]string{
+	OrderStatusDraft:     {OrderStatusPlaced, OrderStatusCancelled},
+	OrderStatusPlaced:    {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusRefunded},
+}
+
+// ErrOrderTransition ...
+var ErrOrderTransition = errors.New("illegal order status transition")
+
+// ErrOrderLocked ...
+var ErrOrderLocked = errors.New("only draft orders can be changed")
+
+// OrderStatusHistory ...
+type OrderStatusHistory struct {
+	ID         int64    `db:"id, primarykey, autoincrement" json:"id"`
+	OrderID    int64    `db:"order_id" json:"order_id"`
+	FromStatus string   `db:"from_status" json:"from"`
+	ToStatus   string   `db:"to_status" json:"to"`
+	Reason     string   `db:"reason" json:"reason"`
+	CreatedAt  int64    `db:"created_at" json:"created_at"`
+	User       *JSONRaw `db:"user" json:"user"`
+}
+
+// CanTransitionOrder ...
+func CanTransitionOrder(from, to string) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transitionError explains the rejected move and the allowed ones
+func transitionError(from, to string) error {
+	allowed := orderTransitions[from]
+	if len(allowed) == 0 {
+		return fmt.Errorf("%w: a %s order can not be changed anymore", ErrOrderTransition, from)
+	}
+	return fmt.Errorf("%w: an order can not move from %s to %s (allowed: %s)", ErrOrderTransition, from, to, strings.Join(allowed, ", "))
+}
+
+// lockStatus returns the current status of the order and locks its row until the transaction ends
+func (m OrderModel) lockStatus(exec gorp.SqlExecutor, orgID, id int64) (status string, err error) {
+	status, err = exec.SelectStr("SELECT status FROM public.order WHERE id=$1 AND organization_id=$2 FOR UPDATE", id, orgID)
+	if err == nil && status == "" {
+		err = sql.ErrNoRows
+	}
+	return status, err
+}
+
+// transition moves the order to the given status within the caller transaction and records it in the history with the user who moved it
+func (m OrderModel) transition(exec gorp.SqlExecutor, orgID, userID, id int64, to, reason string) (err error) {
+	from, err := m.lockStatus(exec, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if !CanTransitionOrder(from, to) {
+		return transitionError(from, to)
+	}
+
+	_, err = exec.Exec("UPDATE public.order SET status=$2 WHERE id=$1", id, to)
+	if err != nil {
+		return err
+	}
+
+	_, err = exec.Exec("INSERT INTO public.order_status_history(order_id, user_id, from_status, to_status, reason) VALUES($1, $2, $3, $4, $5)", id, userID, from, to, reason)
+	if err != nil {
+		return err
+	}
+
+	//Placing the order sets its stock aside until it is shipped, cancelled or refunded
+	switch to {
+	case OrderStatusPlaced:
+		return inventoryModel.reserve(exec, orgID, userID, id)
+	case OrderStatusCancelled, OrderStatusRefunded:
+		return inventoryModel.release(exec, orgID, userID, id)
+	}
+
+	return nil
+}
+
+// Transition ...
+func (m OrderModel) Transition(orgID, userID, id int64, form forms.OrderTransitionForm) (order Order, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return order, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = m.transition(tx, orgID, userID, id, form.Status, form.Reason)
+	if err != nil {
+		return order, err
+	}
+
+	//The order may have been shipped before it was paid
+	if form.Status == OrderStatusPaid {
+		err = shipmentModel.fulfillOrder(tx, orgID, userID, id)
+		if err != nil {
+			return order, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return order, err
+	}
+
+	return m.One(orgID, id)
+}
+
+// History ...
+func (m OrderModel) History(orgID, id int64) (history []OrderStatusHistory, err error) {
+	_, err = m.One(orgID, id)
+	if err != nil {
+		return history, err
+	}
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&history, "SELECT h.id, h.order_id, h.from_status, h.to_status, COALESCE(h.reason, '') AS reason, h.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.order_status_history h LEFT JOIN public.user u ON h.user_id = u.id WHERE h.order_id=$1 ORDER BY h.id", id)
+		return err
+	})
+	return history, err
+}
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+)
+
+// Membership roles ...
+const (
+	RoleOwner    = "owner"
+	RoleAdmin    = "admin"
+	RoleMember   = "member"
+	RoleBilling  = "billing"
+	RoleReadOnly = "read-only"
+)
+
+// ErrNotMember ...
+var ErrNotMember = errors.New("you are not a member of this organization")
+
+// ErrNotAdmin ...
+var ErrNotAdmin = errors.New("only the owners and admins of the organization can invite members")
+
+// ErrAlreadyMember ...
+var ErrAlreadyMember = errors.New("this user is already a member of the organization")
+
+// ErrAlreadyInvited ...
+var ErrAlreadyInvited = errors.New("this email already has a pending invitation to the organization")
+
+// Organization ...
+// Role is the role of the logged in user in the organization
+type Organization struct {
+	ID        int64  `db:"id, primarykey, autoincrement" json:"id"`
+	Name      string `db:"name" json:"name"`
+	Role      string `db:"role" json:"role"`
+	UpdatedAt int64  `db:"updated_at" json:"updated_at"`
+	CreatedAt int64  `db:"created_at" json:"created_at"`
+}
+
+// Member ...
+type Member struct {
+	UserID    int64  `db:"user_id" json:"user_id"`
+	Name      string `db:"name" json:"name"`
+	Email     string `db:"email" json:"email"`
+	Role      string `db:"role" json:"role"`
+	CreatedAt int64  `db:"created_at" json:"created_at"`
+}
+
+// Invitation ...
+type Invitation struct {
+	ID             int64    `db:"id, primarykey, autoincrement" json:"id"`
+	OrganizationID int64    `db:"organization_id" json:"organization_id"`
+	Organization   string   `db:"organization" json:"organization"`
+	Email          string   `db:"email" json:"email"`
+	Role           string   `db:"role" json:"role"`
+	CreatedAt      int64    `db:"created_at" json:"created_at"`
+	InvitedBy      *JSONRaw `db:"invited_by" json:"invited_by"`
+}
+
+// OrganizationModel ...
+type OrganizationModel struct{}
+
+var organizationModel = new(OrganizationModel)
+
+// create inserts the organization with the user as its owner
+func (m OrganizationModel) create(exec gorp.SqlExecutor, userID int64, name string) (organization Organization, err error) {
+	organization = Organization{Name: name, Role: RoleOwner}
+
+	err = exec.QueryRow("INSERT INTO public.organization(name) VALUES($1) RETURNING id, updated_at, created_at", name).Scan(&organization.ID, &organization.UpdatedAt, &organization.CreatedAt)
+	if err != nil {
+		return organization, err
+	}
+
+	_, err = exec.Exec("INSERT INTO public.membership(organization_id, user_id, role) VALUES($1, $2, $3)", organization.ID, userID, RoleOwner)
+	return organization, err
+}
+
+// Create ...
+func (m OrganizationModel) Create(userID int64, form forms.CreateOrganizationForm) (organization Organization, err error) {
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return organization, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	organization, err = m.create(tx, userID, form.Name)
+	if err != nil {
+		return organization, err
+	}
+
+	err = tx.Commit()
+	return organization, err
+}
+
+// All lists the organizations the user is a member of
+func (m OrganizationModel) All(userID int64) (organizations []Organization, err error) {
+	_, err = db.GetDB().Select(&organizations, "SELECT o.id, o.name, ms.role, o.updated_at, o.created_at FROM public.organization o INNER JOIN public.membership ms ON ms.organization_id = o.id WHERE ms.user_id=$1 ORDER BY ms.id", userID)
+	return organizations, err
+}
+
+// Role returns the role of the user in the organization, ErrNotMember when the user is not part of it
+func (m OrganizationModel) Role(orgID, userID int64) (role string, err error) {
+	role, err = db.GetDB().SelectStr("SELECT role FROM public.membership WHERE organization_id=$1 AND user_id=$2", orgID, userID)
+	if err == nil && role == "" {
+		err = ErrNotMember
+	}
+	return role, err
+}
+
+// Default returns the organization the user works in after logging in, the first one joined.
+// A user without any organization gets a new one named after them
+func (m OrganizationModel) Default(user User) (orgID int64, err error) {
+	orgID, err = db.GetDB().SelectInt("SELECT organization_id FROM public.membership WHERE user_id=$1 ORDER BY id LIMIT 1", user.ID)
+	if err != nil || orgID != 0 {
+		return orgID, err
+	}
+
+	organization, err := m.Create(user.ID, forms.CreateOrganizationForm{Name: user.Name})
+	return organization.ID, err
+}
+
+// Members ...
+func (m OrganizationModel) Members(orgID int64) (members []Member, err error) {
+	_, err = db.GetDB().Select(&members, "SELECT u.id AS user_id, u.name, u.email, ms.role, ms.created_at FROM public.membership ms INNER JOIN public.user u ON ms.user_id = u.id WHERE ms.organization_id=$1 ORDER BY ms.id", orgID)
+	return members, err
+}
+
+// Invite invites the email to join the organization, only the owners and admins can invite
+func (m OrganizationModel) Invite(orgID, userID int64, form forms.InviteMemberForm) (invitation Invitation, err error) {
+	role, err := m.Role(orgID, userID)
+	if err != nil {
+		return invitation, err
+	}
+	if role != RoleOwner && role != RoleAdmin {
+		return invitation, ErrNotAdmin
+	}
+
+	members, err := db.GetDB().SelectInt("SELECT count(ms.id) FROM public.membership ms INNER JOIN public.user u ON ms.user_id = u.id WHERE ms.organization_id=$1 AND u.email=LOWER($2)", orgID, form.Email)
+	if err != nil {
+		return invitation, err
+	}
+	if members > 0 {
+		return invitation, ErrAlreadyMember
+	}
+
+	if form.Role == "" {
+		form.Role = RoleMember
+	}
+
+	err = db.GetDB().QueryRow("INSERT INTO public.invitation(organization_id, email, role, invited_by) VALUES($1, LOWER($2), $3, $4) RETURNING id", orgID, form.Email, form.Role, userID).Scan(&invitation.ID)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return invitation, ErrAlreadyInvited
+	}
+	if err != nil {
+		return invitation, err
+	}
+
+	err = db.GetDB().SelectOne(&invitation, "SELECT i.id, i.organization_id, o.name AS organization, i.email, i.role, i.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS invited_by FROM public.invitation i INNER JOIN public.organization o ON i.organization_id = o.id LEFT JOIN public.user u ON i.invited_by = u.id WHERE i.id=$1", invitation.ID)
+	return invitation, err
+}
+
+// Invitations lists the pending invitations sent to the email of the user, once they verified it is theirs
+func (m OrganizationModel) Invitations(userID int64) (invitations []Invitation, err error) {
+	_, err = db.GetDB().Select(&invitations, "SELECT i.id, i.organization_id, o.name AS organization, i.email, i.role, i.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS invited_by FROM public.invitation i INNER JOIN public.organization o ON i.organization_id = o.id LEFT JOIN public.user u ON i.invited_by = u.id WHERE i.accepted_at IS NULL AND i.email = (SELECT LOWER(email) FROM public.user WHERE id=$1 AND verified_at IS NOT NULL) ORDER BY i.id", userID)
+	return invitations, err
+}
+
+// Accept makes the user a member of the organization of the invitation sent to their verified email
+func (m OrganizationModel) Accept(userID, id int64) (organization Organization, err error) {
+	tx, err := db.GetDB().Begin()
+	if err != nil {
+		return organization, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var invitation struct {
+		OrganizationID int64
+		Role           string
+	}
+	err = tx.QueryRow("SELECT organization_id, role FROM public.invitation WHERE id=$1 AND accepted_at IS NULL AND email = (SELECT LOWER(email) FROM public.user WHERE id=$2 AND verified_at IS NOT NULL) FOR UPDATE", id, userID).Scan(&invitation.OrganizationID, &invitation.Role)
+	if err != nil {
+		return organization, err
+	}
+
+	_, err = tx.Exec("INSERT INTO public.membership(organization_id, user_id, role) VALUES($1, $2, $3)", invitation.OrganizationID, userID, invitation.Role)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		err = ErrAlreadyMember
+		return organization, err
+	}
+	if err != nil {
+		return organization, err
+	}
+
+	_, err = tx.Exec("UPDATE public.invitation SET accepted_at=extract(epoch from now()) WHERE id=$1", id)
+	if err != nil {
+		return organization, err
+	}
+
+	err = tx.SelectOne(&organization, "SELECT o.id, o.name, ms.role, o.updated_at, o.created_at FROM public.organization o INNER JOIN public.membership ms ON ms.organization_id = o.id WHERE o.id=$1 AND ms.user_id=$2", invitation.OrganizationID, userID)
+	if err != nil {
+		return organization, err
+	}
+
+	err = tx.Commit()
+	return organization, err
+}
+
+// Switch issues a new pair of tokens carrying the given organization as the active one, in a new session on the device of client
+// so that the previous organization stays usable (e.g. in another tab)
+func (m OrganizationModel) Switch(userID, orgID int64, client SessionClient) (token Token, err error) {
+	_, err = m.Role(orgID, userID)
+	if err != nil {
+		return token, err
+	}
+
+	tokenDetails, err := authModel.CreateToken(userID, orgID)
+	if err != nil {
+		return token, err
+	}
+
+	err = authModel.CreateAuth(userID, tokenDetails)
+	if err != nil {
+		return token, err
+	}
+
+	err = sessionModel.describe(tokenDetails.SessionID, client)
+	if err != nil {
+		return token, err
+	}
+
+	token.AccessToken = tokenDetails.AccessToken
+	token.RefreshToken = tokenDetails.RefreshToken
+
+	return token, nil
+}
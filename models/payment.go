@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+)
+
+// ErrInvoiceNotPayable ...
+var ErrInvoiceNotPayable = errors.New("payments can only be recorded against issued invoices")
+
+// ErrOverpayment ...
+var ErrOverpayment = errors.New("the payment is larger than the invoice balance")
+
+// ErrRefundExceedsPaid ...
+var ErrRefundExceedsPaid = errors.New("the refund is larger than the amount paid")
+
+// Payment is one entry of the invoice ledger, a negative amount is a refund
+type Payment struct {
+	ID        int64  `db:"id, primarykey, autoincrement" json:"id"`
+	UserID    int64  `db:"user_id" json:"-"`
+	InvoiceID int64  `db:"invoice_id" json:"invoice_id"`
+	Amount    int64  `db:"amount" json:"amount"`
+	Method    string `db:"method" json:"method"`
+	Reference string `db:"reference" json:"reference"`
+	PaidOn    string `db:"paid_on" json:"paid_on"`
+	CreatedAt int64  `db:"created_at" json:"created_at"`
+}
+
+// Balance of an invoice computed from its payments
+type Balance struct {
+	InvoiceID int64  `db:"invoice_id" json:"invoice_id"`
+	Status    string `db:"status" json:"status"`
+	Currency  string `db:"currency" json:"currency"`
+	Total     int64  `db:"total" json:"total"`
+	Paid      int64  `db:"paid" json:"paid"`
+	Refunded  int64  `db:"refunded" json:"refunded"`
+	Balance   int64  `db:"balance" json:"balance"`
+}
+
+// PaymentModel ...
+type PaymentModel struct{}
+
+// balance sums the ledger of the invoice
+func (m PaymentModel) balance(exec gorp.SqlExecutor, orgID, invoiceID int64) (balance Balance, err error) {
+	err = exec.SelectOne(&balance, "SELECT a.id AS invoice_id, a.status, a.currency, a.total, COALESCE(SUM(p.amount) FILTER (WHERE p.amount > 0), 0) AS paid, COALESCE(-SUM(p.amount) FILTER (WHERE p.amount < 0), 0) AS refunded, a.total - COALESCE(SUM(p.amount), 0) AS balance FROM public.invoice a LEFT JOIN public.payment p ON p.invoice_id = a.id WHERE a.id=$1 AND a.organization_id=$2 GROUP BY a.id", invoiceID, orgID)
+	return balance, err
+}
+
+// Create records a payment or a refund against an issued invoice.
+// The invoice row is locked so concurrent payments are checked against the same balance,
+// the invoice becomes paid when its balance reaches zero and goes back to issued after a refund
+func (m PaymentModel) Create(orgID, userID, invoiceID int64, form forms.CreatePaymentForm) (payment Payment, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return payment, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, err := invoiceModel.lockStatus(tx, orgID, invoiceID)
+	if err != nil {
+		return payment, err
+	}
+	if status != InvoiceStatusIssued && status != InvoiceStatusPaid {
+		err = ErrInvoiceNotPayable
+		return payment, err
+	}
+
+	balance, err := m.balance(tx, orgID, invoiceID)
+	if err != nil {
+		return payment, err
+	}
+
+	if form.Amount > balance.Balance {
+		err = ErrOverpayment
+		return payment, err
+	}
+	if -form.Amount > balance.Paid-balance.Refunded {
+		err = ErrRefundExceedsPaid
+		return payment, err
+	}
+
+	paidOn := form.PaidOn
+	if paidOn == "" {
+		paidOn = time.Now().Format("2006-01-02")
+	}
+
+	err = tx.QueryRow("INSERT INTO public.payment(organization_id, user_id, invoice_id, amount, method, reference, paid_on) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id", orgID, userID, invoiceID, form.Amount, form.Method, form.Reference, paidOn).Scan(&payment.ID)
+	if err != nil {
+		return payment, err
+	}
+
+	newStatus := InvoiceStatusIssued
+	if balance.Balance-form.Amount == 0 {
+		newStatus = InvoiceStatusPaid
+	}
+
+	if newStatus != status {
+		_, err = tx.Exec("UPDATE public.invoice SET status=$2 WHERE id=$1", invoiceID, newStatus)
+		if err != nil {
+			return payment, err
+		}
+	}
+
+	err = tx.SelectOne(&payment, "SELECT id, invoice_id, amount, method, COALESCE(reference, '') AS reference, to_char(paid_on, 'YYYY-MM-DD') AS paid_on, created_at FROM public.payment WHERE id=$1", payment.ID)
+	if err != nil {
+		return payment, err
+	}
+
+	err = tx.Commit()
+	return payment, err
+}
+
+// All returns the ledger of the invoice in the order it was recorded
+func (m PaymentModel) All(orgID, invoiceID int64) (payments []Payment, err error) {
+	_, err = invoiceModel.One(orgID, invoiceID)
+	if err != nil {
+		return payments, err
+	}
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&payments, "SELECT id, invoice_id, amount, method, COALESCE(reference, '') AS reference, to_char(paid_on, 'YYYY-MM-DD') AS paid_on, created_at FROM public.payment WHERE invoice_id=$1 AND organization_id=$2 ORDER BY id", invoiceID, orgID)
+		return err
+	})
+	return payments, err
+}
+
+// Balance ...
+func (m PaymentModel) Balance(orgID, invoiceID int64) (balance Balance, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		balance, err = m.balance(tx, orgID, invoiceID)
+		return err
+	})
+	return balance, err
+}
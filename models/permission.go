@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+)
+
+// permissionsExpiration is how long the permissions of a role stay cached,
+// changes to the role_permission table apply to the running servers after it
+const permissionsExpiration = time.Hour
+
+// PermissionModel ...
+// A permission is "<resource>:<action>" (e.g. invoice:write), the roles are granted theirs in the role_permission table
+type PermissionModel struct{}
+
+var permissionModel = new(PermissionModel)
+
+// Permissions returns the permissions granted to the role, cached in Redis
+func (m PermissionModel) Permissions(role string) (permissions []string, err error) {
+	key := "role:permissions:" + role
+
+	permissions, err = db.GetRedis().SMembers(key).Result()
+	if err == nil && len(permissions) > 0 {
+		return permissions, nil
+	}
+
+	_, err = db.GetDB().Select(&permissions, "SELECT permission FROM public.role_permission WHERE role=$1 ORDER BY permission", role)
+	if err != nil || len(permissions) == 0 {
+		return permissions, err
+	}
+
+	members := make([]interface{}, len(permissions))
+	for i, permission := range permissions {
+		members[i] = permission
+	}
+
+	//A cache failure should not fail the request
+	pipe := db.GetRedis().TxPipeline()
+	pipe.Del(key)
+	pipe.SAdd(key, members...)
+	pipe.Expire(key, permissionsExpiration)
+	pipe.Exec()
+
+	return permissions, nil
+}
+
+// Can tells whether the role is granted the permission
+func (m PermissionModel) Can(role, permission string) (bool, error) {
+	permissions, err := m.Permissions(role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, granted := range permissions {
+		if granted == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
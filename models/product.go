@@ -1,78 +1,274 @@
 package models
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 )
 
+// ErrSKUTaken ...
+var ErrSKUTaken = errors.New("the SKU is already used by another product or variant")
+
+// ErrProductInUse ...
+var ErrProductInUse = errors.New("the product is used by orders or has stock movements, deactivate it instead")
+
 // Product ...
+// Price is in the minor unit of the product currency (e.g. cents)
 type Product struct {
-	ID        int64    `db:"id, primarykey, autoincrement" json:"id"`
-	UserID    int64    `db:"user_id" json:"-"`
-	Title     string   `db:"title" json:"title"`
-	Content   string   `db:"content" json:"content"`
-	UpdatedAt int64    `db:"updated_at" json:"updated_at"`
-	CreatedAt int64    `db:"created_at" json:"created_at"`
-	User      *JSONRaw `db:"user" json:"user"`
+	ID             int64             `db:"id, primarykey, autoincrement" json:"id"`
+	UserID         int64             `db:"user_id" json:"-"`
+	SKU            string            `db:"sku" json:"sku"`
+	Name           string            `db:"name" json:"name"`
+	Description    string            `db:"description" json:"description"`
+	Price          int64             `db:"price" json:"price"`
+	Currency       string            `db:"currency" json:"currency"`
+	Active         bool              `db:"active" json:"active"`
+	TrackInventory bool              `db:"track_inventory" json:"track_inventory"`
+	Attributes     ProductAttributes `db:"attributes" json:"attributes"`
+	UpdatedAt      int64             `db:"updated_at" json:"updated_at"`
+	CreatedAt      int64             `db:"created_at" json:"created_at"`
+	User           *JSONRaw          `db:"user" json:"user"`
+	Variants       []ProductVariant  `db:"-" json:"variants"`
+}
+
+// ProductVariant ...
+// Price is the override of the product price (null when there is none) and EffectivePrice the price that applies
+type ProductVariant struct {
+	ID             int64  `db:"id, primarykey, autoincrement" json:"id"`
+	ProductID      int64  `db:"product_id" json:"-"`
+	SKU            string `db:"sku" json:"sku"`
+	Size           string `db:"size" json:"size"`
+	Color          string `db:"color" json:"color"`
+	Price          *int64 `db:"price" json:"price"`
+	EffectivePrice int64  `db:"effective_price" json:"effective_price"`
+	Active         bool   `db:"active" json:"active"`
+}
+
+// ProductAttributes are free form product properties stored as JSONB
+type ProductAttributes map[string]interface{}
+
+// Value ...
+func (a ProductAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		a = ProductAttributes{}
+	}
+	//Sent as a string, lib/pq would encode []byte as bytea
+	asBytes, err := json.Marshal(a)
+	return driver.Value(string(asBytes)), err
+}
+
+// Scan ...
+func (a *ProductAttributes) Scan(src interface{}) error {
+	asBytes, ok := src.([]byte)
+	if !ok {
+		return errors.New("Scan source was not []bytes")
+	}
+	return json.Unmarshal(asBytes, a)
 }
 
 // ProductModel ...
 type ProductModel struct{}
 
+var productModel = new(ProductModel)
+
+// productColumns are the product columns returned by One and All
+const productColumns = "a.id, a.sku, a.name, COALESCE(a.description, '') AS description, a.price, a.currency, a.active, a.track_inventory, a.attributes, a.updated_at, a.created_at"
+
+// productVariantColumns are the variant columns, v is the variant and a its product
+const productVariantColumns = "v.id, v.sku, COALESCE(v.size, '') AS size, COALESCE(v.color, '') AS color, v.price, COALESCE(v.price, a.price) AS effective_price, v.active"
+
+// isActive defaults an optional active flag to true
+func isActive(active *bool) bool {
+	return active == nil || *active
+}
+
+// productError turns the constraint violations into the product errors
+func productError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return ErrSKUTaken
+		case "23503":
+			return ErrProductInUse
+		}
+	}
+	return err
+}
+
+// checkSKUs makes sure the product and variant SKUs are not used twice in the organization,
+// the unique constraints still catch the concurrent requests
+func (m ProductModel) checkSKUs(exec gorp.SqlExecutor, orgID, productID int64, form forms.CreateProductForm) (err error) {
+	skus := []string{form.SKU}
+	seen := map[string]bool{form.SKU: true}
+
+	for _, variant := range form.Variants {
+		if seen[variant.SKU] {
+			return ErrSKUTaken
+		}
+		seen[variant.SKU] = true
+		skus = append(skus, variant.SKU)
+	}
+
+	taken, err := exec.SelectInt("SELECT (SELECT count(id) FROM public.product WHERE organization_id=$1 AND id <> $2 AND sku = ANY($3)) + (SELECT count(id) FROM public.product_variant WHERE organization_id=$1 AND product_id <> $2 AND sku = ANY($3))", orgID, productID, pq.Array(skus))
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrSKUTaken
+	}
+
+	return nil
+}
+
+// saveVariants replaces the variants of the product, the variants keeping their SKU keep their ID
+func (m ProductModel) saveVariants(exec gorp.SqlExecutor, orgID, userID, productID int64, variants []forms.ProductVariantForm) (err error) {
+	skus := []string{}
+	for _, variant := range variants {
+		skus = append(skus, variant.SKU)
+	}
+
+	_, err = exec.Exec("DELETE FROM public.product_variant WHERE product_id=$1 AND NOT (sku = ANY($2))", productID, pq.Array(skus))
+	if err != nil {
+		return err
+	}
+
+	for _, variant := range variants {
+		//Only a variant of this product is updated, a SKU taken by another product since checkSKUs returns no row
+		var id int64
+		err = exec.QueryRow("INSERT INTO public.product_variant(organization_id, user_id, product_id, sku, size, color, price, active) VALUES($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (organization_id, sku) DO UPDATE SET size=EXCLUDED.size, color=EXCLUDED.color, price=EXCLUDED.price, active=EXCLUDED.active WHERE product_variant.product_id = EXCLUDED.product_id RETURNING id", orgID, userID, productID, variant.SKU, variant.Size, variant.Color, variant.Price, isActive(variant.Active)).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSKUTaken
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Create ...
-func (m ProductModel) Create(userID int64, form forms.CreateProductForm) (productID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.product(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&productID)
-	return productID, err
+func (m ProductModel) Create(orgID, userID int64, form forms.CreateProductForm) (product Product, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return product, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			err = productError(err)
+		}
+	}()
+
+	err = m.checkSKUs(tx, orgID, 0, form)
+	if err != nil {
+		return product, err
+	}
+
+	err = tx.QueryRow("INSERT INTO public.product(organization_id, user_id, sku, name, description, price, currency, active, track_inventory, attributes) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id", orgID, userID, form.SKU, form.Name, form.Description, form.Price, form.Currency, isActive(form.Active), form.TrackInventory, ProductAttributes(form.Attributes)).Scan(&product.ID)
+	if err != nil {
+		return product, err
+	}
+
+	err = m.saveVariants(tx, orgID, userID, product.ID, form.Variants)
+	if err != nil {
+		return product, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return product, err
+	}
+
+	return m.One(orgID, product.ID)
+}
+
+// one ...
+func (m ProductModel) one(exec gorp.SqlExecutor, orgID, id int64) (product Product, err error) {
+	err = exec.SelectOne(&product, "SELECT "+productColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.product a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+	if err != nil {
+		return product, err
+	}
+
+	_, err = exec.Select(&product.Variants, "SELECT "+productVariantColumns+" FROM public.product_variant v INNER JOIN public.product a ON v.product_id = a.id WHERE v.product_id=$1 ORDER BY v.id", product.ID)
+	return product, err
 }
 
 // One ...
-func (m ProductModel) One(userID, id int64) (product Product, err error) {
-	err = db.GetDB().SelectOne(&product, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.product a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
+func (m ProductModel) One(orgID, id int64) (product Product, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		product, err = m.one(tx, orgID, id)
+		return err
+	})
 	return product, err
 }
 
 // All ...
-func (m ProductModel) All(userID int64) (products []DataList, err error) {
-	_, err = db.GetDB().Select(&products, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.product AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.product a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
+func (m ProductModel) All(orgID int64) (products []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&products, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.product AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT "+productColumns+", (SELECT COALESCE(json_agg(pv ORDER BY pv.id), '[]') FROM (SELECT "+productVariantColumns+" FROM public.product_variant v WHERE v.product_id = a.id) pv) AS variants, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.product a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 ORDER BY a.id DESC) d", orgID)
+		return err
+	})
 	return products, err
 }
 
-// Update ...
-func (m ProductModel) Update(userID int64, id int64, form forms.CreateProductForm) (err error) {
-	//METHOD 1
-	//Check the product by ID using this way
-	// _, err = m.One(userID, id)
-	// if err != nil {
-	// 	return err
-	// }
-
-	operation, err := db.GetDB().Exec("UPDATE public.product SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
+// Update changes the product and replaces its variants
+func (m ProductModel) Update(orgID, userID int64, id int64, form forms.CreateProductForm) (err error) {
+	tx, err := db.BeginTenant(orgID)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			err = productError(err)
+		}
+	}()
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
+	var productID int64
+	err = tx.QueryRow("SELECT id FROM public.product WHERE id=$1 AND organization_id=$2 FOR UPDATE", id, orgID).Scan(&productID)
+	if err != nil {
+		return err
 	}
 
-	return err
-}
-
-// Delete ...
-func (m ProductModel) Delete(userID, id int64) (err error) {
+	err = m.checkSKUs(tx, orgID, id, form)
+	if err != nil {
+		return err
+	}
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.product WHERE id=$1", id)
+	_, err = tx.Exec("UPDATE public.product SET sku=$2, name=$3, description=$4, price=$5, currency=$6, active=$7, track_inventory=$8, attributes=$9 WHERE id=$1", id, form.SKU, form.Name, form.Description, form.Price, form.Currency, isActive(form.Active), form.TrackInventory, ProductAttributes(form.Attributes))
 	if err != nil {
 		return err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
+	err = m.saveVariants(tx, orgID, userID, id, form.Variants)
+	if err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
+}
+
+// Delete ...
+func (m ProductModel) Delete(orgID, id int64) (err error) {
+
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.product WHERE id=$1 AND organization_id=$2", id, orgID)
+		if err != nil {
+			return productError(err)
+		}
+
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,252 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/go-redis/redis/v7"
+)
+
+// sessionSeenInterval is how often the last seen time of a session is updated, at most
+const sessionSeenInterval = time.Minute
+
+// ErrSessionNotFound ...
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionClient is the device a session is started from
+type SessionClient struct {
+	IP        string
+	UserAgent string
+}
+
+// Session ...
+// A login of the user on a device, kept in Redis with the tokens it was last refreshed to until the refresh token expires
+type Session struct {
+	ID        string `json:"id"`
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	ClientID  string `json:"client_id,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	LastSeen  int64  `json:"last_seen"`
+	Current   bool   `json:"current"`
+}
+
+// SessionModel ...
+type SessionModel struct{}
+
+var sessionModel = new(SessionModel)
+
+// sessionKey ...
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// userSessionsKey is the set of the sessions of the user, the expired ones are removed when listed
+func userSessionsKey(userID int64) string {
+	return "user:sessions:" + strconv.FormatInt(userID, 10)
+}
+
+// deviceName returns a readable name of the device of the user agent, e.g. "Firefox on Windows"
+func deviceName(userAgent string) string {
+	var browser, os string
+
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		os = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		os = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		os = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		os = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	case userAgent != "":
+		return strings.SplitN(userAgent, " ", 2)[0]
+	default:
+		return "Unknown device"
+	}
+}
+
+// attach ties the pair of tokens to its session, the previous pair of the session is deleted so that only the last one works
+func (m SessionModel) attach(userID int64, td *TokenDetails) error {
+	key := sessionKey(td.SessionID)
+
+	previous, err := db.GetRedis().HMGet(key, "access_uuid", "refresh_uuid").Result()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	pipe := db.GetRedis().TxPipeline()
+	for _, uuid := range previous {
+		if uuid, ok := uuid.(string); ok && uuid != td.AccessUUID && uuid != td.RefreshUUID {
+			pipe.Del(uuid)
+		}
+	}
+	pipe.HSet(key, "user_id", userID, "access_uuid", td.AccessUUID, "refresh_uuid", td.RefreshUUID, "client_id", td.ClientID, "last_seen", now)
+	pipe.HSetNX(key, "created_at", now)
+	pipe.ExpireAt(key, time.Unix(td.RtExpires, 0))
+	pipe.SAdd(userSessionsKey(userID), td.SessionID)
+	_, err = pipe.Exec()
+
+	return err
+}
+
+// rotated tells whether the refresh token of the session was replaced already, the session is still active with a newer one
+func (m SessionModel) rotated(sessionID, refreshUUID string) bool {
+	current, err := db.GetRedis().HGet(sessionKey(sessionID), "refresh_uuid").Result()
+	return err == nil && current != "" && current != refreshUUID
+}
+
+// describe records the device the session was started from
+func (m SessionModel) describe(sessionID string, client SessionClient) error {
+	return db.GetRedis().HSet(sessionKey(sessionID), "ip", client.IP, "user_agent", client.UserAgent, "device", deviceName(client.UserAgent)).Err()
+}
+
+// touchScript updates the session only while it exists, a revoked session is not brought back without its expiry
+var touchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HSET', KEYS[1], 'last_seen', ARGV[1], 'ip', ARGV[2])
+end
+return 0
+`)
+
+// Touch updates the last seen time and IP of the session, at most once a minute
+func (m SessionModel) Touch(sessionID, ip string) {
+	if sessionID == "" {
+		return
+	}
+
+	first, err := db.GetRedis().SetNX(sessionKey(sessionID)+":seen", 1, sessionSeenInterval).Result()
+	if err != nil || !first {
+		return
+	}
+
+	touchScript.Run(db.GetRedis(), []string{sessionKey(sessionID)}, time.Now().Unix(), ip)
+}
+
+// All lists the active sessions of the user, the most recently seen first, current is the session of the request
+func (m SessionModel) All(userID int64, current string) (sessions []Session, err error) {
+	ids, err := db.GetRedis().SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return sessions, err
+	}
+
+	sessions = []Session{}
+	for _, id := range ids {
+		values, err := db.GetRedis().HGetAll(sessionKey(id)).Result()
+		if err != nil {
+			return sessions, err
+		}
+		//The session expired with its refresh token
+		if len(values) == 0 {
+			db.GetRedis().SRem(userSessionsKey(userID), id)
+			continue
+		}
+
+		session := Session{
+			ID:        id,
+			Device:    values["device"],
+			IP:        values["ip"],
+			UserAgent: values["user_agent"],
+			ClientID:  values["client_id"],
+			Current:   id == current,
+		}
+		session.CreatedAt, _ = strconv.ParseInt(values["created_at"], 10, 64)
+		session.LastSeen, _ = strconv.ParseInt(values["last_seen"], 10, 64)
+		if session.Device == "" {
+			session.Device = "Unknown device"
+			if session.ClientID != "" {
+				session.Device = "OAuth client " + session.ClientID
+			}
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen > sessions[j].LastSeen
+	})
+
+	return sessions, nil
+}
+
+// Revoke ends the session of the user: both of its tokens stop working at once
+func (m SessionModel) Revoke(userID int64, sessionID string) error {
+	key := sessionKey(sessionID)
+
+	values, err := db.GetRedis().HMGet(key, "user_id", "access_uuid", "refresh_uuid").Result()
+	if err != nil {
+		return err
+	}
+	if owner, _ := values[0].(string); owner != strconv.FormatInt(userID, 10) {
+		return ErrSessionNotFound
+	}
+
+	pipe := db.GetRedis().TxPipeline()
+	for _, uuid := range values[1:] {
+		if uuid, ok := uuid.(string); ok {
+			pipe.Del(uuid)
+		}
+	}
+	pipe.Del(key, key+":seen")
+	pipe.SRem(userSessionsKey(userID), sessionID)
+	_, err = pipe.Exec()
+
+	return err
+}
+
+// RevokeAll ends every session of the user, to log out everywhere
+func (m SessionModel) RevokeAll(userID int64) error {
+	return m.RevokeOthers(userID, "")
+}
+
+// RevokeOthers ends every session of the user but the one kept, e.g. the one changing the password
+func (m SessionModel) RevokeOthers(userID int64, keep string) error {
+	ids, err := db.GetRedis().SMembers(userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if id == keep {
+			continue
+		}
+		err = m.Revoke(userID, id)
+		if err == ErrSessionNotFound {
+			err = db.GetRedis().SRem(userSessionsKey(userID), id).Err()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,77 +2,424 @@ package models
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 )
 
+// Shipment statuses ...
+const (
+	ShipmentStatusPending        = "pending"
+	ShipmentStatusInTransit      = "in_transit"
+	ShipmentStatusOutForDelivery = "out_for_delivery"
+	ShipmentStatusDelivered      = "delivered"
+	ShipmentStatusException      = "exception"
+	ShipmentStatusReturned       = "returned"
+)
+
+// shippedStatuses are the statuses of a shipment that left the warehouse
+var shippedStatuses = []string{ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered}
+
+// isShipped tells if the shipment status is one of the shippedStatuses
+func isShipped(status string) bool {
+	for _, shipped := range shippedStatuses {
+		if status == shipped {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrOrderNotShippable ...
+var ErrOrderNotShippable = errors.New("only placed or paid orders can be shipped")
+
+// ErrShipmentLocked ...
+var ErrShipmentLocked = errors.New("only pending shipments can be changed")
+
+// ErrOverShipment ...
+var ErrOverShipment = errors.New("the shipped quantities can not exceed the ordered quantities")
+
+// ErrNothingToShip ...
+var ErrNothingToShip = errors.New("all the order items are already on a shipment")
+
+// ErrOrderItemNotFound ...
+var ErrOrderItemNotFound = errors.New("one or more items do not belong to the order")
+
 // Shipment ...
 type Shipment struct {
-	ID        int64    `db:"id, primarykey, autoincrement" json:"id"`
-	UserID    int64    `db:"user_id" json:"-"`
-	Title     string   `db:"title" json:"title"`
-	Content   string   `db:"content" json:"content"`
-	UpdatedAt int64    `db:"updated_at" json:"updated_at"`
-	CreatedAt int64    `db:"created_at" json:"created_at"`
-	User      *JSONRaw `db:"user" json:"user"`
+	ID             int64            `db:"id, primarykey, autoincrement" json:"id"`
+	UserID         int64            `db:"user_id" json:"-"`
+	OrderID        int64            `db:"order_id" json:"order_id"`
+	Carrier        string           `db:"carrier" json:"carrier"`
+	ServiceLevel   string           `db:"service_level" json:"service_level"`
+	TrackingNumber string           `db:"tracking_number" json:"tracking_number"`
+	Status         string           `db:"status" json:"status"`
+	Destination    *JSONRaw         `db:"destination" json:"destination"`
+	UpdatedAt      int64            `db:"updated_at" json:"updated_at"`
+	CreatedAt      int64            `db:"created_at" json:"created_at"`
+	User           *JSONRaw         `db:"user" json:"user"`
+	Items          []ShipmentItem   `db:"-" json:"items"`
+	Parcels        []ShipmentParcel `db:"-" json:"parcels"`
+	Events         []ShipmentEvent  `db:"-" json:"events"`
+}
+
+// ShipmentItem is the quantity of an order line carried by the shipment
+type ShipmentItem struct {
+	ID          int64 `db:"id, primarykey, autoincrement" json:"id"`
+	ShipmentID  int64 `db:"shipment_id" json:"-"`
+	OrderItemID int64 `db:"order_item_id" json:"order_item_id"`
+	ProductID   int64 `db:"product_id" json:"product_id"`
+	Quantity    int64 `db:"quantity" json:"quantity"`
+}
+
+// ShipmentParcel ...
+// Weight is in grams and the dimensions in millimeters
+type ShipmentParcel struct {
+	ID         int64 `db:"id, primarykey, autoincrement" json:"id"`
+	ShipmentID int64 `db:"shipment_id" json:"-"`
+	Weight     int64 `db:"weight" json:"weight"`
+	Length     int64 `db:"length" json:"length"`
+	Width      int64 `db:"width" json:"width"`
+	Height     int64 `db:"height" json:"height"`
+}
+
+// ShipmentEvent is one entry of the tracking timeline, events are never changed once recorded
+type ShipmentEvent struct {
+	ID          int64  `db:"id, primarykey, autoincrement" json:"id"`
+	ShipmentID  int64  `db:"shipment_id" json:"-"`
+	Status      string `db:"status" json:"status"`
+	Description string `db:"description" json:"description"`
+	Location    string `db:"location" json:"location"`
+	OccurredAt  int64  `db:"occurred_at" json:"occurred_at"`
+	CreatedAt   int64  `db:"created_at" json:"created_at"`
 }
 
 // ShipmentModel ...
 type ShipmentModel struct{}
 
+var shipmentModel = new(ShipmentModel)
+
+// shipmentColumns are the shipment columns returned by One and All
+const shipmentColumns = "a.id, a.order_id, a.carrier, COALESCE(a.service_level, '') AS service_level, COALESCE(a.tracking_number, '') AS tracking_number, a.status, json_build_object('name', a.recipient_name, 'line1', a.address_line1, 'line2', COALESCE(a.address_line2, ''), 'city', a.city, 'region', COALESCE(a.region, ''), 'postal_code', COALESCE(a.postal_code, ''), 'country', a.country) AS destination, a.updated_at, a.created_at"
+
+// insertParcels ...
+func (m ShipmentModel) insertParcels(exec gorp.SqlExecutor, shipmentID int64, parcels []forms.ParcelForm) (err error) {
+	for _, parcel := range parcels {
+		_, err = exec.Exec("INSERT INTO public.shipment_parcel(shipment_id, weight, length, width, height) VALUES($1, $2, $3, $4, $5)", shipmentID, parcel.Weight, parcel.Length, parcel.Width, parcel.Height)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocateItems puts the order lines on the shipment, without items it takes everything that is not on a shipment yet.
+// The caller must hold the order row lock so concurrent shipments of the same order are checked one after the other
+func (m ShipmentModel) allocateItems(exec gorp.SqlExecutor, orderID, shipmentID int64, items []forms.ShipmentItemForm) (err error) {
+	var lines []struct {
+		ID        int64 `db:"id"`
+		Remaining int64 `db:"remaining"`
+	}
+	_, err = exec.Select(&lines, "SELECT i.id, i.quantity - COALESCE((SELECT SUM(si.quantity) FROM public.shipment_item si WHERE si.order_item_id = i.id AND si.shipment_id <> $2), 0) AS remaining FROM public.order_item i WHERE i.order_id=$1 ORDER BY i.id", orderID, shipmentID)
+	if err != nil {
+		return err
+	}
+
+	remaining := map[int64]int64{}
+	for _, line := range lines {
+		remaining[line.ID] = line.Remaining
+	}
+
+	quantities := map[int64]int64{}
+	order := []int64{}
+
+	if len(items) == 0 {
+		for _, line := range lines {
+			if line.Remaining > 0 {
+				quantities[line.ID] = line.Remaining
+				order = append(order, line.ID)
+			}
+		}
+		if len(order) == 0 {
+			return ErrNothingToShip
+		}
+	}
+
+	for _, item := range items {
+		if _, ok := remaining[item.OrderItemID]; !ok {
+			return ErrOrderItemNotFound
+		}
+		if _, ok := quantities[item.OrderItemID]; !ok {
+			order = append(order, item.OrderItemID)
+		}
+		quantities[item.OrderItemID] += item.Quantity
+	}
+
+	for _, orderItemID := range order {
+		if quantities[orderItemID] > remaining[orderItemID] {
+			return ErrOverShipment
+		}
+
+		_, err = exec.Exec("INSERT INTO public.shipment_item(shipment_id, order_item_id, quantity) VALUES($1, $2, $3)", shipmentID, orderItemID, quantities[orderItemID])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockStatus returns the current status and the order of the shipment and locks its row until the transaction ends
+func (m ShipmentModel) lockStatus(exec gorp.SqlExecutor, orgID, id int64) (status string, orderID int64, err error) {
+	err = exec.QueryRow("SELECT status, order_id FROM public.shipment WHERE id=$1 AND organization_id=$2 FOR UPDATE", id, orgID).Scan(&status, &orderID)
+	return status, orderID, err
+}
+
 // Create ...
-func (m ShipmentModel) Create(userID int64, form forms.CreateShipmentForm) (shipmentID int64, err error) {
-	err = db.GetDB().QueryRow("INSERT INTO public.shipment(user_id, title, content) VALUES($1, $2, $3) RETURNING id", userID, form.Title, form.Content).Scan(&shipmentID)
-	return shipmentID, err
+func (m ShipmentModel) Create(orgID, userID int64, form forms.CreateShipmentForm) (shipment Shipment, err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return shipment, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, err := orderModel.lockStatus(tx, orgID, form.OrderID)
+	if err != nil {
+		return shipment, err
+	}
+	if status != OrderStatusPlaced && status != OrderStatusPaid {
+		err = ErrOrderNotShippable
+		return shipment, err
+	}
+
+	address := form.Destination
+
+	err = tx.QueryRow("INSERT INTO public.shipment(organization_id, user_id, order_id, carrier, service_level, tracking_number, recipient_name, address_line1, address_line2, city, region, postal_code, country) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id", orgID, userID, form.OrderID, form.Carrier, form.ServiceLevel, form.TrackingNumber, address.Name, address.Line1, address.Line2, address.City, address.Region, address.PostalCode, address.Country).Scan(&shipment.ID)
+	if err != nil {
+		return shipment, err
+	}
+
+	err = m.insertParcels(tx, shipment.ID, form.Parcels)
+	if err != nil {
+		return shipment, err
+	}
+
+	err = m.allocateItems(tx, form.OrderID, shipment.ID, form.Items)
+	if err != nil {
+		return shipment, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return shipment, err
+	}
+
+	return m.One(orgID, shipment.ID)
+}
+
+// one ...
+func (m ShipmentModel) one(exec gorp.SqlExecutor, orgID, id int64) (shipment Shipment, err error) {
+	err = exec.SelectOne(&shipment, "SELECT "+shipmentColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.shipment a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 AND a.id=$2 LIMIT 1", orgID, id)
+	if err != nil {
+		return shipment, err
+	}
+
+	_, err = exec.Select(&shipment.Items, "SELECT si.id, si.order_item_id, i.product_id, si.quantity FROM public.shipment_item si LEFT JOIN public.order_item i ON si.order_item_id = i.id WHERE si.shipment_id=$1 ORDER BY si.order_item_id", shipment.ID)
+	if err != nil {
+		return shipment, err
+	}
+
+	_, err = exec.Select(&shipment.Parcels, "SELECT id, weight, length, width, height FROM public.shipment_parcel WHERE shipment_id=$1 ORDER BY id", shipment.ID)
+	if err != nil {
+		return shipment, err
+	}
+
+	_, err = exec.Select(&shipment.Events, "SELECT id, status, COALESCE(description, '') AS description, COALESCE(location, '') AS location, occurred_at, created_at FROM public.shipment_event WHERE shipment_id=$1 ORDER BY occurred_at, id", shipment.ID)
+	return shipment, err
 }
 
 // One ...
-func (m ShipmentModel) One(userID, id int64) (shipment Shipment, err error) {
-	err = db.GetDB().SelectOne(&shipment, "SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.shipment a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 AND a.id=$2 LIMIT 1", userID, id)
+func (m ShipmentModel) One(orgID, id int64) (shipment Shipment, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		shipment, err = m.one(tx, orgID, id)
+		return err
+	})
 	return shipment, err
 }
 
 // All ...
-func (m ShipmentModel) All(userID int64) (shipments []DataList, err error) {
-	_, err = db.GetDB().Select(&shipments, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.shipment AS a WHERE a.user_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT a.id, a.title, a.content, a.updated_at, a.created_at, json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.shipment a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.user_id=$1 ORDER BY a.id DESC) d", userID)
+func (m ShipmentModel) All(orgID int64) (shipments []DataList, err error) {
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) (err error) {
+		_, err = tx.Select(&shipments, "SELECT COALESCE(array_to_json(array_agg(row_to_json(d))), '[]') AS data, (SELECT row_to_json(n) FROM ( SELECT count(a.id) AS total FROM public.shipment AS a WHERE a.organization_id=$1 LIMIT 1 ) n ) AS meta FROM ( SELECT "+shipmentColumns+", json_build_object('id', u.id, 'name', u.name, 'email', u.email) AS user FROM public.shipment a LEFT JOIN public.user u ON a.user_id = u.id WHERE a.organization_id=$1 ORDER BY a.id DESC) d", orgID)
+		return err
+	})
 	return shipments, err
 }
 
-// Update ...
-func (m ShipmentModel) Update(userID int64, id int64, form forms.CreateShipmentForm) (err error) {
-	//METHOD 1
-	//Check the shipment by ID using this way
-	// _, err = m.One(userID, id)
-	// if err != nil {
-	// 	return err
-	// }
+// Update changes the carrier, destination and parcels of a shipment that did not leave yet
+func (m ShipmentModel) Update(orgID int64, id int64, form forms.UpdateShipmentForm) (err error) {
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	status, orderID, err := m.lockStatus(tx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if status != ShipmentStatusPending {
+		err = ErrShipmentLocked
+		return err
+	}
+
+	if len(form.Items) > 0 {
+		_, err = orderModel.lockStatus(tx, orgID, orderID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("DELETE FROM public.shipment_item WHERE shipment_id=$1", id)
+		if err != nil {
+			return err
+		}
+
+		err = m.allocateItems(tx, orderID, id, form.Items)
+		if err != nil {
+			return err
+		}
+	}
+
+	address := form.Destination
+
+	_, err = tx.Exec("UPDATE public.shipment SET carrier=$2, service_level=$3, tracking_number=$4, recipient_name=$5, address_line1=$6, address_line2=$7, city=$8, region=$9, postal_code=$10, country=$11 WHERE id=$1", id, form.Carrier, form.ServiceLevel, form.TrackingNumber, address.Name, address.Line1, address.Line2, address.City, address.Region, address.PostalCode, address.Country)
+	if err != nil {
+		return err
+	}
 
-	operation, err := db.GetDB().Exec("UPDATE public.shipment SET title=$2, content=$3 WHERE id=$1", id, form.Title, form.Content)
+	_, err = tx.Exec("DELETE FROM public.shipment_parcel WHERE shipment_id=$1", id)
 	if err != nil {
 		return err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("updated 0 records")
+	err = m.insertParcels(tx, id, form.Parcels)
+	if err != nil {
+		return err
 	}
 
-	return err
+	return tx.Commit()
 }
 
 // Delete ...
-func (m ShipmentModel) Delete(userID, id int64) (err error) {
+func (m ShipmentModel) Delete(orgID, id int64) (err error) {
+
+	return db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("DELETE FROM public.shipment WHERE id=$1 AND organization_id=$2 AND status=$3", id, orgID, ShipmentStatusPending)
+		if err != nil {
+			return err
+		}
 
-	operation, err := db.GetDB().Exec("DELETE FROM public.shipment WHERE id=$1", id)
+		success, _ := operation.RowsAffected()
+		if success == 0 {
+			return errors.New("no records were deleted")
+		}
+
+		return nil
+	})
+}
+
+// AddEvent appends a tracking event to the shipment timeline.
+// The shipment takes the status of its latest event (carriers do not always report in order)
+// and the order is fulfilled once all its lines are shipped
+func (m ShipmentModel) AddEvent(orgID, userID, id int64, form forms.ShipmentEventForm) (shipment Shipment, err error) {
+	occurredAt := time.Now()
+	if form.OccurredAt != "" {
+		occurredAt, err = time.Parse(time.RFC3339, form.OccurredAt)
+		if err != nil {
+			return shipment, err
+		}
+	}
+
+	tx, err := db.BeginTenant(orgID)
+	if err != nil {
+		return shipment, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, orderID, err := m.lockStatus(tx, orgID, id)
+	if err != nil {
+		return shipment, err
+	}
+
+	_, err = tx.Exec("INSERT INTO public.shipment_event(shipment_id, status, description, location, occurred_at) VALUES($1, $2, $3, $4, $5)", id, form.Status, form.Description, form.Location, occurredAt.Unix())
+	if err != nil {
+		return shipment, err
+	}
+
+	var status string
+	err = tx.QueryRow("UPDATE public.shipment SET status=(SELECT status FROM public.shipment_event WHERE shipment_id=$1 ORDER BY occurred_at DESC, id DESC LIMIT 1) WHERE id=$1 RETURNING status", id).Scan(&status)
+	if err != nil {
+		return shipment, err
+	}
+
+	//The order is locked before the stock, like the order transitions do
+	_, err = orderModel.lockStatus(tx, orgID, orderID)
+	if err != nil {
+		return shipment, err
+	}
+
+	if isShipped(status) {
+		err = inventoryModel.ship(tx, orgID, userID, id, orderID)
+		if err != nil {
+			return shipment, err
+		}
+	}
+
+	err = m.fulfillOrder(tx, orgID, userID, orderID)
+	if err != nil {
+		return shipment, err
+	}
+
+	err = tx.Commit()
 	if err != nil {
+		return shipment, err
+	}
+
+	return m.One(orgID, id)
+}
+
+// orderShipped tells if the shipments of the order that left the warehouse carry the full quantity of all its lines
+func (m ShipmentModel) orderShipped(exec gorp.SqlExecutor, orderID int64) (shipped bool, err error) {
+	missing, err := exec.SelectInt("SELECT count(i.id) FROM public.order_item i WHERE i.order_id=$1 AND i.quantity > COALESCE((SELECT SUM(si.quantity) FROM public.shipment_item si INNER JOIN public.shipment s ON si.shipment_id = s.id WHERE si.order_item_id = i.id AND s.status = ANY($2)), 0)", orderID, pq.Array(shippedStatuses))
+	return missing == 0, err
+}
+
+// fulfillOrder marks a paid order as fulfilled when all its lines are shipped
+func (m ShipmentModel) fulfillOrder(exec gorp.SqlExecutor, orgID, userID, orderID int64) (err error) {
+	status, err := orderModel.lockStatus(exec, orgID, orderID)
+	if err != nil || status != OrderStatusPaid {
 		return err
 	}
 
-	success, _ := operation.RowsAffected()
-	if success == 0 {
-		return errors.New("no records were deleted")
+	shipped, err := m.orderShipped(exec, orderID)
+	if err != nil || !shipped {
+		return err
 	}
 
-	return err
+	return orderModel.transition(exec, orgID, userID, orderID, OrderStatusFulfilled, "All the order items were shipped")
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/Massad/gin-boilerplate/db"
@@ -23,39 +24,104 @@ type User struct {
 type UserModel struct{}
 
 var authModel = new(AuthModel)
+var userModel = new(UserModel)
+
+//ErrWrongPassword ...
+var ErrWrongPassword = errors.New("the current password is wrong")
 
 //Login ...
-func (m UserModel) Login(form forms.LoginForm) (user User, token Token, err error) {
+//challenge is set instead of the token when the user has two-factor authentication enabled, the login is completed with a code.
+//client is the device the session is started from
+func (m UserModel) Login(form forms.LoginForm, client SessionClient) (user User, token Token, challenge string, err error) {
+
+	//The attempt counts as a failure before the password is compared, and is not even made while the email or the IP is locked out
+	attempt, err := lockoutModel.Attempt(form.Email, client.IP)
+	if err != nil {
+		return user, token, challenge, err
+	}
 
-	err = db.GetDB().SelectOne(&user, "SELECT id, email, password, name, updated_at, created_at FROM public.user WHERE email=LOWER($1) LIMIT 1", form.Email)
+	//The users who signed up with an identity provider have no password, the login fails as for a wrong one
+	err = db.GetDB().SelectOne(&user, "SELECT id, email, COALESCE(password, '') AS password, name, updated_at, created_at FROM public.user WHERE email=LOWER($1) LIMIT 1", form.Email)
+	if err != nil && err != sql.ErrNoRows {
+		return user, token, challenge, err
+	}
+
+	if user.Password == "" {
+		//An unknown email or an account without password takes as long as a wrong password, the response does not tell them apart
+		compareDummyPassword(form.Password)
+		err = bcrypt.ErrMismatchedHashAndPassword
+	} else {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.Password))
+	}
 
 	if err != nil {
-		return user, token, err
+		if attempt.Locked() && user.ID > 0 {
+			auditModel.Record(user.ID, 0, AuditLoginLocked, client, nil)
+		}
+		return user, token, challenge, err
 	}
 
-	//Compare the password form and database if match
-	bytePassword := []byte(form.Password)
-	byteHashedPassword := []byte(user.Password)
+	attempt.Succeed()
+
+	if EmailVerificationRequired() {
+		verified, err := emailModel.Verified(user.ID)
+		if err != nil {
+			return user, token, challenge, err
+		}
+		if !verified {
+			return user, token, challenge, ErrEmailUnverified
+		}
+	}
+
+	token, challenge, err = m.login(user, client)
+	return user, token, challenge, err
+}
+
+//login ...
+//Issues the tokens of the authenticated user, or the challenge to complete with a code when two-factor authentication is enabled
+func (m UserModel) login(user User, client SessionClient) (token Token, challenge string, err error) {
+	enabled, err := mfaModel.Enabled(user.ID)
+	if err != nil {
+		return token, challenge, err
+	}
+	if enabled {
+		challenge, err = mfaModel.Challenge(user.ID)
+		return token, challenge, err
+	}
 
-	err = bcrypt.CompareHashAndPassword(byteHashedPassword, bytePassword)
+	token, err = m.issueToken(user, client)
+	return token, challenge, err
+}
 
+//issueToken ...
+//Logs the user in once authenticated, by password or by a login provider, in a new session on the device of client
+func (m UserModel) issueToken(user User, client SessionClient) (token Token, err error) {
+	//The user starts in the first organization they joined
+	orgID, err := organizationModel.Default(user)
 	if err != nil {
-		return user, token, err
+		return token, err
 	}
 
 	//Generate the JWT auth token
-	tokenDetails, err := authModel.CreateToken(user.ID)
+	tokenDetails, err := authModel.CreateToken(user.ID, orgID)
+	if err != nil {
+		return token, err
+	}
+
+	err = authModel.CreateAuth(user.ID, tokenDetails)
 	if err != nil {
-		return user, token, err
+		return token, err
 	}
 
-	saveErr := authModel.CreateAuth(user.ID, tokenDetails)
-	if saveErr == nil {
-		token.AccessToken = tokenDetails.AccessToken
-		token.RefreshToken = tokenDetails.RefreshToken
+	err = sessionModel.describe(tokenDetails.SessionID, client)
+	if err != nil {
+		return token, err
 	}
 
-	return user, token, nil
+	token.AccessToken = tokenDetails.AccessToken
+	token.RefreshToken = tokenDetails.RefreshToken
+
+	return token, nil
 }
 
 //Register ...
@@ -72,14 +138,37 @@ func (m UserModel) Register(form forms.RegisterForm) (user User, err error) {
 		return user, errors.New("email already exists")
 	}
 
+	err = forms.GetPasswordPolicy().Check(form.Password, form.Email, form.Name)
+	if err != nil {
+		return user, err
+	}
+
 	bytePassword := []byte(form.Password)
 	hashedPassword, err := bcrypt.GenerateFromPassword(bytePassword, bcrypt.DefaultCost)
 	if err != nil {
 		return user, errors.New("something went wrong, please try again later")
 	}
 
+	tx, err := getDb.Begin()
+	if err != nil {
+		return user, errors.New("something went wrong, please try again later")
+	}
+
 	//Create the user and return back the user ID
-	err = getDb.QueryRow("INSERT INTO public.user(email, password, name) VALUES($1, $2, $3) RETURNING id", form.Email, string(hashedPassword), form.Name).Scan(&user.ID)
+	err = tx.QueryRow("INSERT INTO public.user(email, password, name) VALUES($1, $2, $3) RETURNING id", form.Email, string(hashedPassword), form.Name).Scan(&user.ID)
+	if err != nil {
+		tx.Rollback()
+		return user, errors.New("something went wrong, please try again later")
+	}
+
+	//Every user owns an organization named after them to start with
+	_, err = organizationModel.create(tx, user.ID, form.Name)
+	if err != nil {
+		tx.Rollback()
+		return user, errors.New("something went wrong, please try again later")
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return user, errors.New("something went wrong, please try again later")
 	}
@@ -87,7 +176,50 @@ func (m UserModel) Register(form forms.RegisterForm) (user User, err error) {
 	user.Name = form.Name
 	user.Email = form.Email
 
-	return user, err
+	//The account is created either way, the user can ask for the email again
+	emailModel.deliver(user.Email, emailModel.SendVerification)
+
+	return user, nil
+}
+
+//ChangePassword ...
+//The current password is checked like a login, then the other sessions of the user end: sessionID is the one that stays logged in
+func (m UserModel) ChangePassword(userID int64, sessionID string, form forms.ChangePasswordForm, client SessionClient) (err error) {
+	var user User
+	err = db.GetDB().SelectOne(&user, "SELECT id, email, COALESCE(password, '') AS password, name FROM public.user WHERE id=$1 LIMIT 1", userID)
+	if err != nil {
+		return err
+	}
+
+	attempt, err := lockoutModel.Attempt(user.Email, client.IP)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.CurrentPassword)) != nil {
+		return ErrWrongPassword
+	}
+
+	attempt.Succeed()
+
+	err = forms.GetPasswordPolicy().Check(form.Password, user.Email, user.Name)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(form.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.GetDB().Exec("UPDATE public.user SET password=$2 WHERE id=$1", userID, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	auditModel.Record(userID, 0, AuditPasswordChanged, client, nil)
+
+	return sessionModel.RevokeOthers(userID, sessionID)
 }
 
 //One ...
@@ -0,0 +1,131 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var apiKeyID int64
+var apiKey string
+
+// requestWithAPIKey sends a JSON request through the test router authenticated with the API key
+func requestWithAPIKey(method, url string, form interface{}, key string) *httptest.ResponseRecorder {
+	var body *bytes.Buffer
+	if form != nil {
+		data, _ := json.Marshal(form)
+		body = bytes.NewBuffer(data)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+
+	req, _ := http.NewRequest(method, url, body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", key)
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	return resp
+}
+
+/**
+* TestCreateAPIKey
+* Test creating an API key limited to reading the customers
+*
+* Must return response code 200 with the key, only once
+ */
+func TestCreateAPIKey(t *testing.T) {
+	form := forms.CreateAPIKeyForm{Name: "Testing integration", Scopes: []string{"customer:read"}, ExpiresAt: time.Now().Add(time.Hour).Unix()}
+
+	resp := request("POST", "/v1/api-key", form, accessToken)
+
+	var res struct {
+		ID   int64         `json:"id"`
+		Data models.APIKey `json:"data"`
+	}
+	decode(resp, &res)
+
+	apiKeyID = res.ID
+	apiKey = res.Data.Key
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, apiKey)
+
+	var list struct {
+		Results []models.APIKey `json:"results"`
+	}
+	decode(request("GET", "/v1/api-keys", nil, accessToken), &list)
+
+	assert.NotEmpty(t, list.Results)
+	assert.Empty(t, list.Results[0].Key)
+}
+
+/**
+* TestCreateInvalidAPIKey
+* Test creating API keys with a scope that does not exist and an expiry in the past
+*
+* Must return response code 403 and 406
+ */
+func TestCreateInvalidAPIKey(t *testing.T) {
+	resp := request("POST", "/v1/api-key", forms.CreateAPIKeyForm{Name: "Testing integration", Scopes: []string{"everything:write"}}, accessToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = request("POST", "/v1/api-key", forms.CreateAPIKeyForm{Name: "Testing integration", ExpiresAt: 1}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestAPIKeyAccess
+* Test the API key reaches what its scopes allow and nothing else
+*
+* Must return response code 200 within the scopes and 403 outside of them
+ */
+func TestAPIKeyAccess(t *testing.T) {
+	resp := requestWithAPIKey("GET", "/v1/customers", nil, apiKey)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = requestWithAPIKey("GET", "/v1/products", nil, apiKey)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = requestWithAPIKey("GET", "/v1/api-keys", nil, apiKey)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = requestWithAPIKey("GET", "/v1/customers", nil, "gbk_invalid")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	var list struct {
+		Results []models.APIKey `json:"results"`
+	}
+	decode(request("GET", "/v1/api-keys", nil, accessToken), &list)
+
+	assert.NotNil(t, list.Results[0].LastUsedAt)
+}
+
+/**
+* TestRevokeAPIKey
+* Test a revoked API key is rejected at once
+*
+* Must return response code 200 then 401 with the revoked key
+ */
+func TestRevokeAPIKey(t *testing.T) {
+	resp := request("DELETE", fmt.Sprintf("/v1/api-key/%d", apiKeyID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = requestWithAPIKey("GET", "/v1/customers", nil, apiKey)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("DELETE", "/v1/api-key/1000000000", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
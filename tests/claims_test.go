@@ -0,0 +1,92 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenClaims returns the claims of the token without verifying it
+func tokenClaims(token string) models.TokenClaims {
+	var claims models.TokenClaims
+	new(jwt.Parser).ParseUnverified(token, &claims)
+	return claims
+}
+
+// resign signs the edited claims with ACCESS_SECRET, as the tokens without kid are
+func resign(claims models.TokenClaims, edit func(*models.TokenClaims)) string {
+	edit(&claims)
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+	return signed
+}
+
+/**
+* TestTokenClaims
+* Test the registered and custom claims of the tokens, and the tokens they must reject
+*
+* Must return response code 401 for a token of another type, issuer or audience, not valid yet or expired
+ */
+func TestTokenClaims(t *testing.T) {
+	defer setEnv("JWT_ALGORITHM", models.AlgorithmHS256)()
+
+	access, refresh := login(testEmail, testPassword)
+
+	claims := tokenClaims(access)
+	assert.NotEmpty(t, claims.Issuer)
+	assert.NotEmpty(t, claims.Audience)
+	assert.NotEmpty(t, claims.ID)
+	assert.NotEmpty(t, claims.SessionID)
+	assert.NotNil(t, claims.IssuedAt)
+	assert.NotNil(t, claims.NotBefore)
+	assert.True(t, claims.OrganizationID > 0)
+	assert.Empty(t, claims.Scopes)
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	assert.NoError(t, err)
+	assert.True(t, userID > 0)
+
+	//The claims of the access and refresh tokens are not interchangeable
+	resp := request("GET", "/v1/organizations", nil, refresh)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: access}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, resign(claims, func(claims *models.TokenClaims) {}))
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, resign(claims, func(claims *models.TokenClaims) { claims.Issuer = "another-issuer" }))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, resign(claims, func(claims *models.TokenClaims) { claims.Audience = jwt.ClaimStrings{"another-service"} }))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, resign(claims, func(claims *models.TokenClaims) { claims.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Hour)) }))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, resign(claims, func(claims *models.TokenClaims) { claims.Type = "refresh" }))
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//The lifetime of the access tokens is configured
+	restore := setEnv("JWT_ACCESS_EXPIRATION", "1s")
+	access, _ = login(testEmail, testPassword)
+	restore()
+
+	claims = tokenClaims(access)
+	assert.True(t, claims.ExpiresAt.Sub(claims.IssuedAt.Time) <= time.Second)
+
+	time.Sleep(2100 * time.Millisecond)
+
+	resp = request("GET", "/v1/organizations", nil, access)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
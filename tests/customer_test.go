@@ -0,0 +1,224 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var customerID int64
+
+// customerForm returns a valid company customer with a billing and a shipping address and a contact
+func customerForm() (form forms.CreateCustomerForm) {
+	form.Name = "Testing customer"
+	form.CompanyName = "Testing company"
+	form.TaxID = "DE123456789"
+	form.Email = "billing@example.com"
+	form.Phone = "+4930123456"
+	form.Tags = []string{"wholesale", "testing"}
+	form.Notes = "Testing customer notes"
+	form.Addresses = []forms.CustomerAddressForm{
+		{Type: "billing", Line1: "1 Testing street", City: "Berlin", PostalCode: "10115", Country: "DE"},
+		{Type: "shipping", Line1: "2 Testing street", City: "Hamburg", PostalCode: "20095", Country: "DE"},
+	}
+	form.Contacts = []forms.CustomerContactForm{
+		{Name: "Testing contact", Role: "Accounting", Email: "accounting@example.com"},
+	}
+	return form
+}
+
+/**
+* TestCreateCustomer
+* Test customer creation with its addresses and contacts
+*
+* Must return response code 200
+ */
+func TestCreateCustomer(t *testing.T) {
+	resp := request("POST", "/v1/customer", customerForm(), accessToken)
+
+	var res struct {
+		ID   int64           `json:"id"`
+		Data models.Customer `json:"data"`
+	}
+	decode(resp, &res)
+
+	customerID = res.ID
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, res.Data.Addresses, 2)
+	assert.True(t, res.Data.Addresses[0].Default)
+	assert.True(t, res.Data.Addresses[1].Default)
+	assert.Len(t, res.Data.Contacts, 1)
+	assert.Equal(t, []string{"wholesale", "testing"}, []string(res.Data.Tags))
+}
+
+/**
+* TestCreateInvalidCustomer
+* Test customer invalid creation without a name, with an invalid email, phone or country and with two default billing addresses
+*
+* Must return response code 406
+ */
+func TestCreateInvalidCustomer(t *testing.T) {
+	form := customerForm()
+	form.Name = ""
+
+	resp := request("POST", "/v1/customer", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	form = customerForm()
+	form.Email = "invalid"
+
+	resp = request("POST", "/v1/customer", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	form = customerForm()
+	form.Contacts[0].Phone = "030 123456"
+
+	resp = request("POST", "/v1/customer", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	form = customerForm()
+	form.Addresses[0].Country = "XX"
+
+	resp = request("POST", "/v1/customer", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	form = customerForm()
+	form.Addresses[1].Type = "billing"
+	form.Addresses[0].Default, form.Addresses[1].Default = true, true
+
+	resp = request("POST", "/v1/customer", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestGetCustomer
+* Test getting one customer
+*
+* Must return response code 200
+ */
+func TestGetCustomer(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/customer/%d", customerID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetCustomers
+* Test listing the customers
+*
+* Must return response code 200
+ */
+func TestGetCustomers(t *testing.T) {
+	resp := request("GET", "/v1/customers", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetCustomersByTag
+* Test listing the customers with a tag
+*
+* Must return response code 200 and only the tagged customers
+ */
+func TestGetCustomersByTag(t *testing.T) {
+	resp := request("GET", "/v1/customers?tag=wholesale", nil, accessToken)
+
+	var res struct {
+		Results []struct {
+			Data []models.Customer `json:"data"`
+		} `json:"results"`
+	}
+	decode(resp, &res)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, res.Results[0].Data)
+	for _, customer := range res.Results[0].Data {
+		assert.Contains(t, []string(customer.Tags), "wholesale")
+	}
+
+	decode(request("GET", "/v1/customers?tag=unknown-tag", nil, accessToken), &res)
+	assert.Empty(t, res.Results[0].Data)
+}
+
+/**
+* TestGetInvalidCustomer
+* Test getting invalid customer
+*
+* Must return response code 404
+ */
+func TestGetInvalidCustomer(t *testing.T) {
+	resp := request("GET", "/v1/customer/invalid", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+/**
+* TestGetCustomerNotLoggedin
+* Test getting the customer with logged out user
+*
+* Must return response code 401
+ */
+func TestGetCustomerNotLoggedin(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/customer/%d", customerID), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestUpdateCustomer
+* Test updating a customer
+*
+* Must return response code 200
+ */
+func TestUpdateCustomer(t *testing.T) {
+	form := customerForm()
+	form.Name = "Testing new customer"
+	form.Addresses = append(form.Addresses, forms.CustomerAddressForm{Type: "shipping", Default: true, Line1: "3 Testing street", City: "Munich", PostalCode: "80331", Country: "DE"})
+	form.Contacts = nil
+
+	resp := request("PUT", fmt.Sprintf("/v1/customer/%d", customerID), form, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var res struct {
+		Data models.Customer `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/customer/%d", customerID), nil, accessToken), &res)
+
+	assert.Equal(t, "Testing new customer", res.Data.Name)
+	assert.Len(t, res.Data.Contacts, 0)
+
+	shipping, ok := res.Data.DefaultAddress("shipping")
+	assert.True(t, ok)
+	assert.Equal(t, "Munich", shipping.City)
+}
+
+/**
+* TestDeleteOrderedCustomer
+* Test deleting a customer with orders
+*
+* Must return response code 409
+ */
+func TestDeleteOrderedCustomer(t *testing.T) {
+	var order struct {
+		Data models.Order `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", createTestOrder()), nil, accessToken), &order)
+
+	resp := request("DELETE", fmt.Sprintf("/v1/customer/%d", order.Data.CustomerID), nil, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+/**
+* TestDeleteCustomer
+* Test deleting a customer
+*
+* Must return response code 200
+ */
+func TestDeleteCustomer(t *testing.T) {
+	resp := request("DELETE", fmt.Sprintf("/v1/customer/%d", customerID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
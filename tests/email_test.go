@@ -0,0 +1,179 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/mail"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testVerifyEmail = "test-gin-boilerplate-verify@test.com"
+
+// testMailer keeps the emails sent by the tests
+var testMailer = mail.NewMemoryMailer()
+
+var emailTokenRegexp = regexp.MustCompile(`token=([A-Za-z0-9_\-.]+)`)
+
+// emailToken returns the token of the link in the last email sent to the address, once the requested emails are sent
+func emailToken(to string) string {
+	new(models.EmailModel).Wait()
+
+	message, ok := testMailer.Last(to)
+	if !ok {
+		return ""
+	}
+
+	match := emailTokenRegexp.FindStringSubmatch(message.Body)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// verifyEmail confirms the address with the link of the last email sent to it and returns the response code
+func verifyEmail(email string) int {
+	return request("POST", "/v1/user/email/verify", forms.EmailTokenForm{Token: emailToken(email)}, "").Code
+}
+
+/**
+* TestEmailVerification
+* Test a new user can only log in once the email is verified when EMAIL_VERIFICATION_REQUIRED is set,
+* with the link emailed at the registration
+*
+* Must return response code 403 before the verification and 200 after, the link only works once
+ */
+func TestEmailVerification(t *testing.T) {
+	os.Setenv("EMAIL_VERIFICATION_REQUIRED", "TRUE")
+	defer os.Unsetenv("EMAIL_VERIFICATION_REQUIRED")
+
+	resp := request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing verify", Email: testVerifyEmail, Password: testPassword}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	firstToken := emailToken(testVerifyEmail)
+	assert.NotEmpty(t, firstToken)
+
+	resp = request("POST", "/v1/user/login", forms.LoginForm{Email: testVerifyEmail, Password: testPassword}, "")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	//Asking again replaces the link of the first email
+	resp = request("POST", "/v1/user/email/verification", forms.EmailRequestForm{Email: testVerifyEmail}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	token := emailToken(testVerifyEmail)
+	assert.NotEqual(t, firstToken, token)
+
+	resp = request("POST", "/v1/user/email/verify", forms.EmailTokenForm{Token: firstToken}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/user/email/verify", forms.EmailTokenForm{Token: token}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/user/email/verify", forms.EmailTokenForm{Token: token}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	accessToken, _ := login(testVerifyEmail, testPassword)
+	assert.NotEmpty(t, accessToken)
+
+	//An unknown email gets the same answer
+	resp = request("POST", "/v1/user/email/verification", forms.EmailRequestForm{Email: "unknown-gin-boilerplate@test.com"}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestPasswordReset
+* Test resetting the password with the emailed link
+*
+* Must log in with the new password only, and reject a reused or forged token or one of another purpose
+* and a password breaking the policy
+ */
+func TestPasswordReset(t *testing.T) {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing verify", Email: testVerifyEmail, Password: testPassword}, "")
+
+	resp := request("POST", "/v1/user/password/forgot", forms.EmailRequestForm{Email: testVerifyEmail}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	token := emailToken(testVerifyEmail)
+	assert.NotEmpty(t, token)
+
+	//A reset token does not verify the email
+	resp = request("POST", "/v1/user/email/verify", forms.EmailTokenForm{Token: token}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//A password breaking the policy leaves the link usable
+	resp = request("POST", "/v1/user/password/reset", forms.ResetPasswordForm{Token: token, Password: "P@ssw0rd123"}, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = request("POST", "/v1/user/password/reset", forms.ResetPasswordForm{Token: token, Password: "new-password"}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/user/password/reset", forms.ResetPasswordForm{Token: token, Password: "other-password"}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	accessToken, _ := login(testVerifyEmail, testPassword)
+	assert.Empty(t, accessToken)
+
+	accessToken, _ = login(testVerifyEmail, "new-password")
+	assert.NotEmpty(t, accessToken)
+
+	resp = request("POST", "/v1/user/password/reset", forms.ResetPasswordForm{Token: "forged.token", Password: "new-password"}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+// forgotPasswordFromIP requests a password reset for the email from the IP
+func forgotPasswordFromIP(email, ip string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(forms.EmailRequestForm{Email: email})
+
+	req, _ := http.NewRequest("POST", "/v1/user/password/forgot", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", ip)
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	return resp
+}
+
+/**
+* TestEmailRequestThrottle
+* Test the emails requested too often for an address, registered or not, and from an IP
+*
+* Must return response code 429 with Retry-After once the address or the IP asked too many times
+ */
+func TestEmailRequestThrottle(t *testing.T) {
+	defer setEnv("EMAIL_REQUEST_MAX_ATTEMPTS", "2")()
+	defer setEnv("EMAIL_REQUEST_IP_MAX_ATTEMPTS", "4")()
+
+	ip := testIP()
+
+	for _, email := range []string{testEmail, "unknown-gin-boilerplate@test.com"} {
+		for i := 0; i < 2; i++ {
+			resp := forgotPasswordFromIP(email, ip)
+			assert.Equal(t, http.StatusOK, resp.Code, email)
+		}
+
+		resp := forgotPasswordFromIP(email, testIP())
+		assert.Equal(t, http.StatusTooManyRequests, resp.Code, email)
+
+		seconds, _ := strconv.Atoi(resp.Header().Get("Retry-After"))
+		assert.True(t, seconds > 0, email)
+	}
+
+	//The IP asked for 4 emails already, whatever the address
+	resp := forgotPasswordFromIP("other-gin-boilerplate@test.com", ip)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+
+	resp = forgotPasswordFromIP("another-gin-boilerplate@test.com", testIP())
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
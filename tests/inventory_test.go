@@ -0,0 +1,218 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var warehouseID int64
+var stockProduct models.Product
+var stockOrderID int64
+
+// createTrackedProduct creates a product with a variant whose stock is reserved when ordered
+func createTrackedProduct() (product models.Product) {
+	form := forms.CreateProductForm{
+		SKU:            testSKU(),
+		Name:           "Testing tracked product",
+		Price:          1000,
+		Currency:       "USD",
+		TrackInventory: true,
+		Variants:       []forms.ProductVariantForm{{SKU: testSKU() + "-L", Size: "L"}},
+	}
+
+	var res struct {
+		Data models.Product `json:"data"`
+	}
+	decode(request("POST", "/v1/product", form, accessToken), &res)
+
+	return res.Data
+}
+
+// moveStock records a stock movement of the product and returns the response code with the new stock
+func moveStock(productID int64, form forms.StockMovementForm) (int, models.Stock) {
+	resp := request("POST", fmt.Sprintf("/v1/product/%d/stock", productID), form, accessToken)
+
+	var res struct {
+		Data models.Stock `json:"data"`
+	}
+	decode(resp, &res)
+
+	return resp.Code, res.Data
+}
+
+// productStock returns the current stock of the product
+func productStock(productID int64) models.Stock {
+	var res struct {
+		Data models.Stock `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/product/%d/stock", productID), nil, accessToken), &res)
+
+	return res.Data
+}
+
+// placeStockOrder creates a draft order of the product (or its variant) and returns its ID with the code of placing it
+func placeStockOrder(productID, variantID, quantity int64) (int64, int) {
+	form := forms.CreateOrderForm{
+		CustomerID: createTestCustomer(),
+		Currency:   "USD",
+		Items:      []forms.OrderItemForm{{ProductID: productID, VariantID: variantID, Quantity: quantity, UnitPrice: 1000}},
+	}
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/order", form, accessToken), &res)
+
+	resp := request("POST", fmt.Sprintf("/v1/order/%d/transition", res.ID), forms.OrderTransitionForm{Status: "placed"}, accessToken)
+
+	return res.ID, resp.Code
+}
+
+/**
+* TestCreateWarehouse
+* Test warehouse creation and its unique code
+*
+* Must return response code 200 and 409 for the same code
+ */
+func TestCreateWarehouse(t *testing.T) {
+	form := forms.CreateWarehouseForm{Code: testSKU(), Name: "Testing warehouse"}
+
+	resp := request("POST", "/v1/warehouse", form, accessToken)
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(resp, &res)
+
+	warehouseID = res.ID
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/warehouse", form, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request("GET", "/v1/warehouses", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestReceiveStock
+* Test receiving stock of a product, a negative receipt and an unknown warehouse
+*
+* Must return response code 200 and 406 for the invalid movements
+ */
+func TestReceiveStock(t *testing.T) {
+	stockProduct = createTrackedProduct()
+
+	code, stock := moveStock(stockProduct.ID, forms.StockMovementForm{WarehouseID: warehouseID, Type: "receipt", Quantity: 5})
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(5), stock.OnHand)
+	assert.Equal(t, int64(0), stock.Reserved)
+	assert.Equal(t, int64(5), stock.Available)
+	assert.Len(t, stock.Levels, 1)
+
+	code, _ = moveStock(stockProduct.ID, forms.StockMovementForm{WarehouseID: warehouseID, Type: "receipt", Quantity: -1})
+	assert.Equal(t, http.StatusNotAcceptable, code)
+
+	code, _ = moveStock(stockProduct.ID, forms.StockMovementForm{WarehouseID: warehouseID + 1000000, Type: "receipt", Quantity: 1})
+	assert.Equal(t, http.StatusNotAcceptable, code)
+}
+
+/**
+* TestReserveStock
+* Test placing orders reserves the stock and fails once it is not available anymore
+*
+* Must return response code 200 for the first order and 409 for the second one
+ */
+func TestReserveStock(t *testing.T) {
+	var code int
+
+	stockOrderID, code = placeStockOrder(stockProduct.ID, 0, 3)
+	assert.Equal(t, http.StatusOK, code)
+
+	stock := productStock(stockProduct.ID)
+	assert.Equal(t, int64(5), stock.OnHand)
+	assert.Equal(t, int64(3), stock.Reserved)
+	assert.Equal(t, int64(2), stock.Available)
+
+	orderID, code := placeStockOrder(stockProduct.ID, 0, 3)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "draft", orderStatus(orderID))
+	assert.Equal(t, int64(2), productStock(stockProduct.ID).Available)
+
+	//The variant has its own stock
+	_, code = placeStockOrder(stockProduct.ID, stockProduct.Variants[0].ID, 1)
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+/**
+* TestAdjustReservedStock
+* Test removing more stock than what is not reserved
+*
+* Must return response code 409
+ */
+func TestAdjustReservedStock(t *testing.T) {
+	code, _ := moveStock(stockProduct.ID, forms.StockMovementForm{WarehouseID: warehouseID, Type: "adjustment", Quantity: -3, Note: "Damaged"})
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, stock := moveStock(stockProduct.ID, forms.StockMovementForm{WarehouseID: warehouseID, Type: "adjustment", Quantity: -1, Note: "Damaged"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(4), stock.OnHand)
+	assert.Equal(t, int64(1), stock.Available)
+}
+
+/**
+* TestCancelReleasesStock
+* Test cancelling a placed order gives its stock back
+*
+* Must leave nothing reserved
+ */
+func TestCancelReleasesStock(t *testing.T) {
+	resp := request("POST", fmt.Sprintf("/v1/order/%d/transition", stockOrderID), forms.OrderTransitionForm{Status: "cancelled"}, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	stock := productStock(stockProduct.ID)
+	assert.Equal(t, int64(4), stock.OnHand)
+	assert.Equal(t, int64(0), stock.Reserved)
+	assert.Equal(t, int64(4), stock.Available)
+}
+
+/**
+* TestShipmentTakesStock
+* Test a shipment that left takes its items out of the warehouse
+*
+* Must lower the stock on hand and release the reservation
+ */
+func TestShipmentTakesStock(t *testing.T) {
+	orderID, code := placeStockOrder(stockProduct.ID, 0, 2)
+	assert.Equal(t, http.StatusOK, code)
+
+	_, shipment := createShipment(shipmentForm(orderID))
+	addShipmentEvent(shipment.ID, forms.ShipmentEventForm{Status: "in_transit"})
+	addShipmentEvent(shipment.ID, forms.ShipmentEventForm{Status: "delivered"})
+
+	stock := productStock(stockProduct.ID)
+	assert.Equal(t, int64(2), stock.OnHand)
+	assert.Equal(t, int64(0), stock.Reserved)
+	assert.Equal(t, int64(2), stock.Available)
+}
+
+/**
+* TestGetInvalidStock
+* Test getting the stock of a product that does not exist
+*
+* Must return response code 404
+ */
+func TestGetInvalidStock(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/product/%d/stock", stockProduct.ID+1000000), nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,103 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenInvoice is a fixed invoice rendered by TestRenderInvoicePDF
+func goldenInvoice() (models.Invoice, models.Seller) {
+	invoice := models.Invoice{
+		ID:             1,
+		Number:         "INV-2024-000042",
+		Status:         "issued",
+		Currency:       "EUR",
+		Subtotal:       2999,
+		TaxTotal:       400,
+		Total:          3399,
+		BillingName:    "Café Müller GmbH",
+		BillingAddress: "Hauptstraße 1\n10115 Berlin\nGermany",
+		Notes:          "Payment by bank transfer within 30 days.",
+		DueDate:        "2024-03-31",
+		IssuedAt:       1709251200,
+		TaxBreakdown: models.TaxBreakdown{
+			{Rate: 0, Taxable: 999, Tax: 0},
+			{Rate: 2000, Taxable: 2000, Tax: 400},
+		},
+		Items: []models.InvoiceItem{
+			{Description: "Consulting hours with a description long enough to wrap on the next line of the table", Quantity: 2, UnitPrice: 1000, TaxRate: 2000, Subtotal: 2000, Tax: 400, Total: 2400},
+			{Description: "Shipping", Quantity: 1, UnitPrice: 999, TaxRate: 0, Subtotal: 999, Tax: 0, Total: 999},
+		},
+	}
+
+	seller := models.Seller{
+		Name:    "Gin Boilerplate Ltd",
+		Address: "1 Example Street\nExample City",
+		TaxID:   "GB123456789",
+	}
+
+	return invoice, seller
+}
+
+/**
+* TestRenderInvoicePDF
+* Test the invoice rendering against the golden file, run with -update to accept a new layout
+*
+* Must return the same bytes as testdata/invoice.golden.pdf
+ */
+func TestRenderInvoicePDF(t *testing.T) {
+	invoice, seller := goldenInvoice()
+
+	document, err := models.RenderInvoicePDF(invoice, seller)
+	assert.Nil(t, err)
+
+	again, _ := models.RenderInvoicePDF(invoice, seller)
+	assert.True(t, bytes.Equal(document, again), "rendering is not deterministic")
+
+	golden := filepath.Join("testdata", "invoice.golden.pdf")
+	if *updateGolden {
+		assert.Nil(t, ioutil.WriteFile(golden, document, 0644))
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(expected, document), "rendered invoice differs from %s", golden)
+}
+
+/**
+* TestInvoicePDF
+* Test downloading a draft and then an issued invoice
+*
+* Must return response code 409 for the draft and 200 with the same PDF on every download
+ */
+func TestInvoicePDF(t *testing.T) {
+	_, invoice := generateInvoice(createTestOrder("placed"))
+
+	url := fmt.Sprintf("/v1/invoice/%d/pdf", invoice.ID)
+
+	resp := request("GET", url, nil, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	request("POST", fmt.Sprintf("/v1/invoice/%d/issue", invoice.ID), nil, accessToken)
+
+	resp = request("GET", url, nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/pdf", resp.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(resp.Body.Bytes(), []byte("%PDF-")))
+
+	cached := request("GET", url, nil, accessToken)
+	assert.Equal(t, resp.Body.Bytes(), cached.Body.Bytes())
+}
@@ -0,0 +1,276 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var invoiceID int64
+var invoiceOrderID int64
+var invoiceNumber string
+
+// generateInvoice generates a draft invoice for the order and returns the response with the decoded invoice
+func generateInvoice(orderID int64) (int, models.Invoice) {
+	resp := request("POST", fmt.Sprintf("/v1/order/%d/invoice", orderID), nil, accessToken)
+
+	var res struct {
+		Data models.Invoice `json:"data"`
+	}
+	decode(resp, &res)
+
+	return resp.Code, res.Data
+}
+
+/**
+* TestGenerateInvoiceDraftOrder
+* Test generating an invoice from an order that is not placed yet
+*
+* Must return response code 409
+ */
+func TestGenerateInvoiceDraftOrder(t *testing.T) {
+	code, _ := generateInvoice(createTestOrder())
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+/**
+* TestGenerateInvoice
+* Test generating a draft invoice from a placed order
+*
+* Must return response code 200
+ */
+func TestGenerateInvoice(t *testing.T) {
+	invoiceOrderID = createTestOrder("placed")
+
+	code, invoice := generateInvoice(invoiceOrderID)
+
+	invoiceID = invoice.ID
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "draft", invoice.Status)
+	assert.Equal(t, "", invoice.Number)
+	assert.Equal(t, "USD", invoice.Currency)
+	assert.Len(t, invoice.Items, 2)
+	assert.Equal(t, int64(3399), invoice.Total)
+	assert.Equal(t, "Testing fixture company", invoice.BillingName)
+	assert.Equal(t, "1 Testing street\n10115 Berlin\nDE", invoice.BillingAddress)
+	assert.Equal(t, "DE123456789", invoice.BillingTaxID)
+	assert.Equal(t, time.Now().AddDate(0, 0, 30).Format("2006-01-02"), invoice.DueDate)
+	assert.Equal(t, models.TaxBreakdown{
+		{Rate: 0, Taxable: 999, Tax: 0},
+		{Rate: 2000, Taxable: 2000, Tax: 400},
+	}, invoice.TaxBreakdown)
+}
+
+/**
+* TestGenerateInvoiceTwice
+* Test generating a second invoice for an order that is already invoiced
+*
+* Must return response code 409
+ */
+func TestGenerateInvoiceTwice(t *testing.T) {
+	code, _ := generateInvoice(invoiceOrderID)
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+/**
+* TestGetInvoice
+* Test getting one invoice
+*
+* Must return response code 200
+ */
+func TestGetInvoice(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/invoice/%d", invoiceID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetInvoices
+* Test listing the invoices
+*
+* Must return response code 200
+ */
+func TestGetInvoices(t *testing.T) {
+	resp := request("GET", "/v1/invoices", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetInvalidInvoice
+* Test getting invalid invoice
+*
+* Must return response code 404
+ */
+func TestGetInvalidInvoice(t *testing.T) {
+	resp := request("GET", "/v1/invoice/invalid", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+/**
+* TestGetInvoiceNotLoggedin
+* Test getting the invoice with logged out user
+*
+* Must return response code 401
+ */
+func TestGetInvoiceNotLoggedin(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/invoice/%d", invoiceID), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestUpdateInvoice
+* Test updating a draft invoice
+*
+* Must return response code 200
+ */
+func TestUpdateInvoice(t *testing.T) {
+	var form forms.UpdateInvoiceForm
+
+	form.DueDate = "2030-01-31"
+	form.BillingName = "Testing billing name"
+	form.BillingAddress = "Testing billing address"
+
+	resp := request("PUT", fmt.Sprintf("/v1/invoice/%d", invoiceID), form, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestUpdateInvalidInvoice
+* Test updating an invoice with an invalid due date
+*
+* Must return response code 406
+ */
+func TestUpdateInvalidInvoice(t *testing.T) {
+	var form forms.UpdateInvoiceForm
+
+	form.DueDate = "31/01/2030"
+
+	resp := request("PUT", fmt.Sprintf("/v1/invoice/%d", invoiceID), form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestIssueInvoice
+* Test issuing a draft invoice which assigns its number and makes it immutable
+*
+* Must return response code 200
+ */
+func TestIssueInvoice(t *testing.T) {
+	resp := request("POST", fmt.Sprintf("/v1/invoice/%d/issue", invoiceID), nil, accessToken)
+
+	var res struct {
+		Data models.Invoice `json:"data"`
+	}
+	decode(resp, &res)
+
+	invoiceNumber = res.Data.Number
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "issued", res.Data.Status)
+	assert.Equal(t, "2030-01-31", res.Data.DueDate)
+	assert.Regexp(t, regexp.MustCompile(fmt.Sprintf(`^INV-%d-\d{6}$`, time.Now().Year())), invoiceNumber)
+
+	resp = request("POST", fmt.Sprintf("/v1/invoice/%d/issue", invoiceID), nil, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+/**
+* TestUpdateIssuedInvoice
+* Test updating and deleting an issued invoice
+*
+* Must return response code 406
+ */
+func TestUpdateIssuedInvoice(t *testing.T) {
+	var form forms.UpdateInvoiceForm
+
+	form.DueDate = "2030-02-28"
+
+	resp := request("PUT", fmt.Sprintf("/v1/invoice/%d", invoiceID), form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = request("DELETE", fmt.Sprintf("/v1/invoice/%d", invoiceID), nil, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestIssueInvoicesConcurrently
+* Test that invoices issued at the same time get consecutive numbers
+*
+* Must return consecutive numbers following the previous invoice
+ */
+func TestIssueInvoicesConcurrently(t *testing.T) {
+	ids := []int64{}
+	for i := 0; i < 5; i++ {
+		_, invoice := generateInvoice(createTestOrder("placed"))
+		ids = append(ids, invoice.ID)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	numbers := []string{}
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+
+			var res struct {
+				Data models.Invoice `json:"data"`
+			}
+			decode(request("POST", fmt.Sprintf("/v1/invoice/%d/issue", id), nil, accessToken), &res)
+
+			mutex.Lock()
+			numbers = append(numbers, res.Data.Number)
+			mutex.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	sort.Strings(numbers)
+
+	var year, last int
+	fmt.Sscanf(invoiceNumber, "INV-%d-%d", &year, &last)
+
+	for i, number := range numbers {
+		assert.Equal(t, fmt.Sprintf("INV-%d-%06d", year, last+i+1), number)
+	}
+}
+
+/**
+* TestVoidInvoice
+* Test voiding an issued invoice, which allows invoicing its order again
+*
+* Must return response code 200
+ */
+func TestVoidInvoice(t *testing.T) {
+	resp := request("POST", fmt.Sprintf("/v1/invoice/%d/void", invoiceID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", fmt.Sprintf("/v1/invoice/%d/void", invoiceID), nil, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+/**
+* TestDeleteInvoice
+* Test deleting a draft invoice
+*
+* Must return response code 200
+ */
+func TestDeleteInvoice(t *testing.T) {
+	code, invoice := generateInvoice(invoiceOrderID)
+	assert.Equal(t, http.StatusOK, code)
+
+	resp := request("DELETE", fmt.Sprintf("/v1/invoice/%d", invoice.ID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
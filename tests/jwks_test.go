@@ -0,0 +1,171 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/models"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// setEnv sets the variable for the test and returns the function restoring it
+func setEnv(key, value string) func() {
+	previous, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+
+	return func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+// jwks returns the published keys by their kid
+func jwks() map[string]models.JWK {
+	var set models.JWKSet
+	decode(request("GET", "/.well-known/jwks.json", nil, ""), &set)
+
+	keys := map[string]models.JWK{}
+	for _, key := range set.Keys {
+		keys[key.Kid] = key
+	}
+	return keys
+}
+
+// publicKey decodes the public key of the JWK as another service verifying our tokens would
+func publicKey(key models.JWK) interface{} {
+	decode := func(value string) []byte {
+		bytes, _ := base64.RawURLEncoding.DecodeString(value)
+		return bytes
+	}
+
+	switch key.Kty {
+	case "RSA":
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(decode(key.N)), E: int(new(big.Int).SetBytes(decode(key.E)).Int64())}
+	case "EC":
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(decode(key.X)), Y: new(big.Int).SetBytes(decode(key.Y))}
+	case "OKP":
+		return ed25519.PublicKey(decode(key.X))
+	}
+	return nil
+}
+
+// verifyWithJWKS verifies the access token with the published key of its kid and returns the kid
+func verifyWithJWKS(t *testing.T, token string) string {
+	keys := jwks()
+
+	parsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		key, ok := keys[token.Header["kid"].(string)]
+		if !ok || key.Alg != token.Method.Alg() {
+			return nil, models.ErrUnknownSigningKey
+		}
+		return publicKey(key), nil
+	})
+
+	assert.NoError(t, err)
+	if err != nil {
+		return ""
+	}
+	assert.True(t, parsed.Valid)
+
+	return parsed.Header["kid"].(string)
+}
+
+/**
+* TestAsymmetricSigning
+* Test the access tokens of each asymmetric algorithm are verified with the published JWKS
+*
+* Must verify the token with the public key of its kid, and accept it on the API
+ */
+func TestAsymmetricSigning(t *testing.T) {
+	for _, algorithm := range []string{models.AlgorithmRS256, models.AlgorithmES256, models.AlgorithmEdDSA} {
+		restore := setEnv("JWT_ALGORITHM", algorithm)
+
+		token, _ := login(testEmail, testPassword)
+		assert.NotEmpty(t, token, algorithm)
+
+		parsed, _, _ := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+		assert.Equal(t, algorithm, parsed.Method.Alg())
+
+		assert.NotEmpty(t, verifyWithJWKS(t, token), algorithm)
+
+		resp := request("GET", "/v1/organizations", nil, token)
+		assert.Equal(t, http.StatusOK, resp.Code, algorithm)
+
+		restore()
+	}
+}
+
+/**
+* TestSigningKeyRotation
+* Test a rotated key still verifies the tokens it signed, next to the new key
+*
+* Must sign with a new kid after the rotation and keep accepting the token of the previous one
+ */
+func TestSigningKeyRotation(t *testing.T) {
+	defer setEnv("JWT_ALGORITHM", models.AlgorithmES256)()
+	defer setEnv("JWT_KEY_ROTATION", "2s")()
+
+	before, _ := login(testEmail, testPassword)
+	beforeKid := verifyWithJWKS(t, before)
+
+	time.Sleep(2100 * time.Millisecond)
+
+	after, _ := login(testEmail, testPassword)
+	afterKid := verifyWithJWKS(t, after)
+
+	assert.NotEqual(t, beforeKid, afterKid)
+
+	keys := jwks()
+	assert.Contains(t, keys, beforeKid)
+	assert.Contains(t, keys, afterKid)
+
+	resp := request("GET", "/v1/organizations", nil, before)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	//A token can not pick its key with a kid that is not in the ring
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"access_uuid": "forged", "user_id": 1, "org_id": 1})
+	forged.Header["kid"] = "unknown"
+	signed, _ := forged.SignedString([]byte(os.Getenv("ACCESS_SECRET")))
+
+	resp = request("GET", "/v1/organizations", nil, signed)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestLegacyTokenAfterAlgorithmSwitch
+* Test the access tokens signed with ACCESS_SECRET once the algorithm is asymmetric
+*
+* Must reject them unless JWT_LEGACY_HS256_UNTIL is still ahead
+ */
+func TestLegacyTokenAfterAlgorithmSwitch(t *testing.T) {
+	legacy, _ := login(testEmail, testPassword)
+
+	defer setEnv("JWT_ALGORITHM", models.AlgorithmES256)()
+
+	resp := request("GET", "/v1/organizations", nil, legacy)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	restore := setEnv("JWT_LEGACY_HS256_UNTIL", time.Now().Add(time.Hour).Format(time.RFC3339))
+	resp = request("GET", "/v1/organizations", nil, legacy)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	restore()
+
+	defer setEnv("JWT_LEGACY_HS256_UNTIL", time.Now().Add(-time.Hour).Format(time.RFC3339))()
+	resp = request("GET", "/v1/organizations", nil, legacy)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
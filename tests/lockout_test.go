@@ -0,0 +1,169 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// loginFromIP tries the login from the IP
+func loginFromIP(email, password, ip string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(forms.LoginForm{Email: email, Password: password})
+
+	req, _ := http.NewRequest("POST", "/v1/user/login", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", ip)
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	return resp
+}
+
+// testIPs counts the IPs given to the tests
+var testIPs = 0
+
+// testIP returns a new IP of the documentation range, no other test logs in from it
+func testIP() string {
+	testIPs++
+	return fmt.Sprintf("198.51.100.%d", testIPs)
+}
+
+/**
+* TestLoginLockout
+* Test the back-off and the lockout after failed logins, and the unlock by an admin
+*
+* Must return response code 429 with Retry-After while locked, even for the right password
+ */
+func TestLoginLockout(t *testing.T) {
+	defer setEnv("LOGIN_MAX_ATTEMPTS", "3")()
+
+	ip := testIP()
+
+	resp := loginFromIP(testEmail, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = loginFromIP(testEmail, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	//The second failure delays the next attempt
+	resp = loginFromIP(testEmail, testPassword, ip)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+
+	time.Sleep(1100 * time.Millisecond)
+
+	resp = loginFromIP(testEmail, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = loginFromIP(testEmail, testPassword, ip)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+
+	retryAfter, _ := strconv.Atoi(resp.Header().Get("Retry-After"))
+	assert.True(t, retryAfter > 60)
+
+	//Only the members of the organization are unlocked
+	resp = request("POST", "/v1/organization/members/999999999/unlock", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = request("POST", fmt.Sprintf("/v1/organization/members/%d/unlock", tokenClaims(accessToken).UserID()), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = loginFromIP(testEmail, testPassword, ip)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var res struct {
+		Results []models.AuditLog `json:"results"`
+	}
+	decode(request("GET", "/v1/user/audit-log", nil, accessToken), &res)
+
+	events := []string{}
+	for _, log := range res.Results {
+		events = append(events, log.Event)
+	}
+	assert.Contains(t, events, models.AuditLoginLocked)
+	assert.Contains(t, events, models.AuditLoginUnlocked)
+}
+
+/**
+* TestLoginLockoutUnknownEmail
+* Test an email without account is delayed like the others, and an IP trying many emails is locked out
+*
+* Must return the same response codes as for an existing email
+ */
+func TestLoginLockoutUnknownEmail(t *testing.T) {
+	defer setEnv("LOGIN_IP_MAX_ATTEMPTS", "4")()
+
+	ip := testIP()
+	unknown := fmt.Sprintf("test-gin-boilerplate-unknown-%d@test.com", time.Now().UnixNano())
+
+	resp := loginFromIP(unknown, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = loginFromIP(unknown, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = loginFromIP(unknown, "wrong-password", ip)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+
+	resp = loginFromIP("another-"+unknown, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = loginFromIP("more-"+unknown, "wrong-password", ip)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	//The IP reached its maximum, whatever the email
+	resp = loginFromIP(testEmail, testPassword, ip)
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+
+	resp = loginFromIP(testEmail, testPassword, testIP())
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestLoginLockoutParallel
+* Test the failed logins sent at once are all counted before any password is compared
+*
+* Must compare the password of the free attempt and the first delayed one only, the others return 429
+ */
+func TestLoginLockoutParallel(t *testing.T) {
+	unknown := fmt.Sprintf("test-gin-boilerplate-parallel-%d@test.com", time.Now().UnixNano())
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		ip := testIP()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- loginFromIP(unknown, "wrong-password", ip).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	compared := 0
+	for code := range codes {
+		if code == http.StatusNotAcceptable {
+			compared++
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, code)
+		}
+	}
+	assert.Equal(t, 2, compared)
+}
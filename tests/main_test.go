@@ -0,0 +1,420 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/controllers"
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/mail"
+	"github.com/joho/godotenv"
+	"github.com/lib/pq"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var auth = new(controllers.AuthController)
+
+// TokenAuthMiddleware ...
+// JWT Authentication middleware attached to each request that needs to be authenitcated to validate the access_token in the header,
+// the server-to-server requests send an API key in the X-API-Key header instead
+func TokenAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" {
+			auth.APIKeyValid(c)
+		} else {
+			auth.TokenValid(c)
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission ...
+// Attached after TokenAuthMiddleware, aborts with 403 when the role of the user in the active organization is not granted the permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.Permission(c, permission)
+		c.Next()
+	}
+}
+
+// SetupRouter mirrors the routes registered in main.go
+func SetupRouter() *gin.Engine {
+	r := gin.Default()
+	gin.SetMode(gin.TestMode)
+
+	//Custom form validator
+	binding.Validator = new(forms.DefaultValidator)
+
+	r.GET("/.well-known/jwks.json", auth.JWKS)
+
+	v1 := r.Group("/v1")
+	{
+		/*** START USER ***/
+		user := new(controllers.UserController)
+
+		v1.POST("/user/login", user.Login)
+		v1.POST("/user/register", user.Register)
+		v1.GET("/user/logout", user.Logout)
+		v1.PUT("/user/password", TokenAuthMiddleware(), user.ChangePassword)
+
+		/*** START SESSION ***/
+		session := new(controllers.SessionController)
+
+		v1.GET("/user/sessions", TokenAuthMiddleware(), session.All)
+		v1.DELETE("/user/sessions/:id", TokenAuthMiddleware(), session.Revoke)
+		v1.DELETE("/user/sessions", TokenAuthMiddleware(), session.RevokeAll)
+
+		/*** START AUDIT ***/
+		audit := new(controllers.AuditController)
+
+		v1.GET("/user/audit-log", TokenAuthMiddleware(), audit.All)
+
+		/*** START EMAIL ***/
+		email := new(controllers.EmailController)
+
+		v1.POST("/user/email/verification", email.RequestVerification)
+		v1.POST("/user/email/verify", email.Verify)
+		v1.POST("/user/password/forgot", email.ForgotPassword)
+		v1.POST("/user/password/reset", email.ResetPassword)
+
+		/*** START AUTH ***/
+		auth := new(controllers.AuthController)
+
+		v1.POST("/token/refresh", auth.Refresh)
+
+		/*** START OIDC ***/
+		oidc := new(controllers.OIDCController)
+
+		v1.GET("/oidc/providers", oidc.Providers)
+		v1.GET("/oidc/:provider/login", oidc.Login)
+		v1.GET("/oidc/:provider/callback", oidc.Callback)
+
+		/*** START MFA ***/
+		mfa := new(controllers.MFAController)
+
+		v1.POST("/user/login/mfa", mfa.Login)
+		v1.POST("/user/mfa/enroll", TokenAuthMiddleware(), mfa.Enroll)
+		v1.POST("/user/mfa/activate", TokenAuthMiddleware(), mfa.Activate)
+		v1.POST("/user/mfa/disable", TokenAuthMiddleware(), mfa.Disable)
+
+		/*** START Organization ***/
+		organization := new(controllers.OrganizationController)
+
+		v1.POST("/organization", TokenAuthMiddleware(), organization.Create)
+		v1.GET("/organizations", TokenAuthMiddleware(), organization.All)
+		v1.GET("/organization/members", TokenAuthMiddleware(), organization.Members)
+		v1.POST("/organization/invitations", TokenAuthMiddleware(), RequirePermission("member:invite"), organization.Invite)
+		v1.POST("/organization/members/:id/unlock", TokenAuthMiddleware(), RequirePermission("member:unlock"), organization.Unlock)
+		v1.POST("/organization/:id/switch", TokenAuthMiddleware(), organization.Switch)
+		v1.GET("/invitations", TokenAuthMiddleware(), organization.Invitations)
+		v1.POST("/invitation/:id/accept", TokenAuthMiddleware(), organization.Accept)
+
+		/*** START API Key ***/
+		apiKey := new(controllers.APIKeyController)
+
+		v1.POST("/api-key", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.Create)
+		v1.GET("/api-keys", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.All)
+		v1.DELETE("/api-key/:id", TokenAuthMiddleware(), RequirePermission("api_key:manage"), apiKey.Revoke)
+
+		/*** START OAuth ***/
+		oauth := new(controllers.OAuthController)
+
+		v1.POST("/oauth/client", TokenAuthMiddleware(), RequirePermission("oauth_client:manage"), oauth.CreateClient)
+		v1.GET("/oauth/clients", TokenAuthMiddleware(), RequirePermission("oauth_client:manage"), oauth.Clients)
+		v1.GET("/oauth/authorize", TokenAuthMiddleware(), oauth.Consent)
+		v1.POST("/oauth/authorize", TokenAuthMiddleware(), oauth.Authorize)
+		v1.POST("/oauth/token", oauth.Token)
+
+		/*** START Article ***/
+		article := new(controllers.ArticleController)
+
+		v1.POST("/article", TokenAuthMiddleware(), RequirePermission("article:write"), article.Create)
+		v1.GET("/articles", TokenAuthMiddleware(), RequirePermission("article:read"), article.All)
+		v1.GET("/article/:id", TokenAuthMiddleware(), RequirePermission("article:read"), article.One)
+		v1.PUT("/article/:id", TokenAuthMiddleware(), RequirePermission("article:write"), article.Update)
+		v1.DELETE("/article/:id", TokenAuthMiddleware(), RequirePermission("article:write"), article.Delete)
+
+		/*** START Product ***/
+		product := new(controllers.ProductController)
+
+		v1.POST("/product", TokenAuthMiddleware(), RequirePermission("product:write"), product.Create)
+		v1.GET("/product", TokenAuthMiddleware(), RequirePermission("product:read"), product.All)
+		v1.GET("/products", TokenAuthMiddleware(), RequirePermission("product:read"), product.All)
+		v1.GET("/product/:id", TokenAuthMiddleware(), RequirePermission("product:read"), product.One)
+		v1.PUT("/product/:id", TokenAuthMiddleware(), RequirePermission("product:write"), product.Update)
+		v1.DELETE("/product/:id", TokenAuthMiddleware(), RequirePermission("product:write"), product.Delete)
+
+		/*** START Customer ***/
+		customer := new(controllers.CustomerController)
+
+		v1.POST("/customer", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Create)
+		v1.GET("/customers", TokenAuthMiddleware(), RequirePermission("customer:read"), customer.All)
+		v1.GET("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:read"), customer.One)
+		v1.PUT("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Update)
+		v1.DELETE("/customer/:id", TokenAuthMiddleware(), RequirePermission("customer:write"), customer.Delete)
+
+		/*** START Order ***/
+		order := new(controllers.OrderController)
+
+		v1.POST("/order", TokenAuthMiddleware(), RequirePermission("order:write"), order.Create)
+		v1.GET("/orders", TokenAuthMiddleware(), RequirePermission("order:read"), order.All)
+		v1.GET("/order/:id", TokenAuthMiddleware(), RequirePermission("order:read"), order.One)
+		v1.PUT("/order/:id", TokenAuthMiddleware(), RequirePermission("order:write"), order.Update)
+		v1.DELETE("/order/:id", TokenAuthMiddleware(), RequirePermission("order:write"), order.Delete)
+		v1.POST("/order/:id/transition", TokenAuthMiddleware(), RequirePermission("order:write"), order.Transition)
+		v1.GET("/order/:id/history", TokenAuthMiddleware(), RequirePermission("order:read"), order.History)
+
+		/*** START Invoice ***/
+		invoice := new(controllers.InvoiceController)
+
+		v1.POST("/order/:id/invoice", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Generate)
+		v1.GET("/invoices", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.All)
+		v1.GET("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.One)
+		v1.PUT("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Update)
+		v1.DELETE("/invoice/:id", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Delete)
+		v1.POST("/invoice/:id/issue", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Issue)
+		v1.POST("/invoice/:id/void", TokenAuthMiddleware(), RequirePermission("invoice:write"), invoice.Void)
+		v1.GET("/invoice/:id/pdf", TokenAuthMiddleware(), RequirePermission("invoice:read"), invoice.PDF)
+
+		/*** START Payment ***/
+		payment := new(controllers.PaymentController)
+
+		v1.POST("/invoice/:id/payments", TokenAuthMiddleware(), RequirePermission("payment:write"), payment.Create)
+		v1.GET("/invoice/:id/payments", TokenAuthMiddleware(), RequirePermission("payment:read"), payment.All)
+		v1.GET("/invoice/:id/balance", TokenAuthMiddleware(), RequirePermission("payment:read"), payment.Balance)
+
+		/*** START Shipment ***/
+		shipment := new(controllers.ShipmentController)
+
+		v1.POST("/shipment", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Create)
+		v1.GET("/shipments", TokenAuthMiddleware(), RequirePermission("shipment:read"), shipment.All)
+		v1.GET("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:read"), shipment.One)
+		v1.PUT("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Update)
+		v1.DELETE("/shipment/:id", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Delete)
+		v1.POST("/shipment/:id/events", TokenAuthMiddleware(), RequirePermission("shipment:write"), shipment.Event)
+
+		/*** START Inventory ***/
+		inventory := new(controllers.InventoryController)
+
+		v1.POST("/warehouse", TokenAuthMiddleware(), RequirePermission("inventory:write"), inventory.CreateWarehouse)
+		v1.GET("/warehouses", TokenAuthMiddleware(), RequirePermission("inventory:read"), inventory.Warehouses)
+		v1.GET("/product/:id/stock", TokenAuthMiddleware(), RequirePermission("inventory:read"), inventory.Stock)
+		v1.POST("/product/:id/stock", TokenAuthMiddleware(), RequirePermission("inventory:write"), inventory.Move)
+	}
+
+	return r
+}
+
+var testEmail = "test-gin-boilerplate@test.com"
+var testPassword = "Sturdy-Lantern-42"
+
+var accessToken string
+var refreshToken string
+
+/**
+* TestMain
+* Connects to the database and logs in the test user shared by every resource test,
+* then deletes the user and its organizations (and everything cascading from them) once all tests ran
+ */
+func TestMain(m *testing.M) {
+	//Load the .env file
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Fatal("Error loading .env file, please create one in the root directory")
+	}
+
+	db.Init()
+	db.InitRedis(1)
+
+	mail.SetMailer(testMailer)
+
+	cleanUp()
+
+	var registerForm forms.RegisterForm
+
+	registerForm.Name = "testing"
+	registerForm.Email = testEmail
+	registerForm.Password = testPassword
+
+	if resp := request("POST", "/v1/user/register", registerForm, ""); resp.Code != http.StatusOK {
+		log.Fatalf("could not register the test user: %s", resp.Body.String())
+	}
+
+	//The invitations and the identity providers only trust a verified email
+	if code := verifyEmail(testEmail); code != http.StatusOK {
+		log.Fatalf("could not verify the email of the test user: %d", code)
+	}
+
+	accessToken, refreshToken = login(testEmail, testPassword)
+	if accessToken == "" {
+		log.Fatal("could not login the test user")
+	}
+
+	code := m.Run()
+
+	cleanUp()
+
+	os.Exit(code)
+}
+
+// request sends a JSON request through the test router, form is skipped when nil
+func request(method, url string, form interface{}, token string) *httptest.ResponseRecorder {
+	testRouter := SetupRouter()
+
+	var body *bytes.Buffer
+	if form != nil {
+		data, _ := json.Marshal(form)
+		body = bytes.NewBuffer(data)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer: %s", token))
+	}
+
+	resp := httptest.NewRecorder()
+	testRouter.ServeHTTP(resp, req)
+
+	return resp
+}
+
+// decode unmarshals the response body into v
+func decode(resp *httptest.ResponseRecorder, v interface{}) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	json.Unmarshal(body, v)
+}
+
+// login returns a fresh access_token and refresh_token pair for the given credentials
+func login(email, password string) (string, string) {
+	var loginForm forms.LoginForm
+
+	loginForm.Email = email
+	loginForm.Password = password
+
+	var res struct {
+		Token struct {
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+		} `json:"token"`
+	}
+	decode(request("POST", "/v1/user/login", loginForm, ""), &res)
+
+	return res.Token.AccessToken, res.Token.RefreshToken
+}
+
+// testEmails are the users created by the tests
+func testEmails() []string {
+	return []string{testEmail, testRegisterEmail, testMemberEmail, testReadOnlyEmail, testBillingEmail, testOIDCEmail, testReclaimEmail, testMFAEmail, testVerifyEmail, testSessionEmail, testPolicyEmail}
+}
+
+// cleanUp deletes the organizations of the users created by the tests with all of their records, then the users
+func cleanUp() {
+	_, err := db.GetDB().Exec("DELETE FROM public.organization WHERE id IN (SELECT ms.organization_id FROM public.membership ms INNER JOIN public.user u ON ms.user_id = u.id WHERE u.email = ANY($1))", pq.Array(testEmails()))
+	if err != nil {
+		log.Println(err)
+	}
+
+	_, err = db.GetDB().Exec("DELETE FROM public.user WHERE email = ANY($1)", pq.Array(testEmails()))
+	if err != nil {
+		log.Println(err)
+	}
+
+	//The failed logins and requested emails of the previous runs, and the permissions cached before the role_permission table changed
+	for _, pattern := range []string{"login:*", "email-request:*", "role:permissions:*"} {
+		keys, _ := db.GetRedis().Keys(pattern).Result()
+		if len(keys) > 0 {
+			db.GetRedis().Del(keys...)
+		}
+	}
+}
+
+// createTestCustomer creates a company customer with a billing address for the resources that need one and returns its ID
+func createTestCustomer() int64 {
+	var form forms.CreateCustomerForm
+
+	form.Name = "Testing fixture customer"
+	form.CompanyName = "Testing fixture company"
+	form.TaxID = "DE123456789"
+	form.Addresses = []forms.CustomerAddressForm{
+		{Type: "billing", Line1: "1 Testing street", City: "Berlin", PostalCode: "10115", Country: "DE"},
+	}
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/customer", form, accessToken), &res)
+
+	return res.ID
+}
+
+// testSKU returns a SKU that is not used by the test user yet
+func testSKU() string {
+	return fmt.Sprintf("TEST-%d", time.Now().UnixNano())
+}
+
+// createTestProduct creates a product for the resources that need one and returns its ID
+func createTestProduct() int64 {
+	var form forms.CreateProductForm
+
+	form.SKU = testSKU()
+	form.Name = "Testing fixture product"
+	form.Price = 1000
+	form.Currency = "USD"
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/product", form, accessToken), &res)
+
+	return res.ID
+}
+
+// createTestOrder creates a draft order of 2 x 10.00 at 20% tax and 1 x 9.99 without tax
+// for the resources that need one, moves it through the given statuses and returns its ID
+func createTestOrder(statuses ...string) int64 {
+	var form forms.CreateOrderForm
+
+	productID := createTestProduct()
+
+	form.CustomerID = createTestCustomer()
+	form.Currency = "USD"
+	form.Items = []forms.OrderItemForm{
+		{ProductID: productID, Quantity: 2, UnitPrice: 1000, TaxRate: 2000},
+		{ProductID: productID, Quantity: 1, UnitPrice: 999},
+	}
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/order", form, accessToken), &res)
+
+	for _, status := range statuses {
+		request("POST", fmt.Sprintf("/v1/order/%d/transition", res.ID), forms.OrderTransitionForm{Status: status}, accessToken)
+	}
+
+	return res.ID
+}
@@ -0,0 +1,129 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMFAEmail = "test-gin-boilerplate-mfa@test.com"
+
+// mfaLogin logs in with the password and returns the challenge of the second step, empty when the tokens were returned
+func mfaLogin(email, password string) (challenge string, accessToken string) {
+	var res struct {
+		MFARequired bool   `json:"mfa_required"`
+		MFAToken    string `json:"mfa_token"`
+		Token       struct {
+			AccessToken string `json:"access_token"`
+		} `json:"token"`
+	}
+	decode(request("POST", "/v1/user/login", forms.LoginForm{Email: email, Password: password}, ""), &res)
+
+	return res.MFAToken, res.Token.AccessToken
+}
+
+/**
+* TestMFA
+* Test enrolling, activating and logging in with two-factor authentication,
+* with a TOTP code of the secret and with a recovery code, then disabling it
+*
+* Must only return the tokens once the second step is completed with a valid code
+ */
+func TestMFA(t *testing.T) {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing mfa", Email: testMFAEmail, Password: testPassword}, "")
+
+	challenge, token := mfaLogin(testMFAEmail, testPassword)
+	assert.Empty(t, challenge)
+	assert.NotEmpty(t, token)
+
+	var enrollment struct {
+		Data models.MFAEnrollment `json:"data"`
+	}
+	resp := request("POST", "/v1/user/mfa/enroll", nil, token)
+	decode(resp, &enrollment)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, enrollment.Data.Secret)
+
+	uri, _ := url.Parse(enrollment.Data.URI)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, enrollment.Data.Secret, uri.Query().Get("secret"))
+
+	resp = request("POST", "/v1/user/mfa/activate", forms.MFACodeForm{Code: "000000"}, token)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	code, _ := models.TOTP(enrollment.Data.Secret, time.Now())
+
+	var activated struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	resp = request("POST", "/v1/user/mfa/activate", forms.MFACodeForm{Code: code}, token)
+	decode(resp, &activated)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, activated.RecoveryCodes, 10)
+
+	resp = request("POST", "/v1/user/mfa/enroll", nil, token)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	//The password alone only returns the challenge
+	challenge, token = mfaLogin(testMFAEmail, testPassword)
+	assert.NotEmpty(t, challenge)
+	assert.Empty(t, token)
+
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: "000000"}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//The code of the activation was used, the next one is still accepted for the clock drift
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: code}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	code, _ = models.TOTP(enrollment.Data.Secret, time.Now().Add(30*time.Second))
+
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: code}, "")
+	_, loggedIn := loggedInUser(resp)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, loggedIn.AccessToken)
+
+	//A challenge only logs in once
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: activated.RecoveryCodes[0]}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//A recovery code is accepted instead of the TOTP code, once
+	challenge, _ = mfaLogin(testMFAEmail, testPassword)
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: activated.RecoveryCodes[0]}, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	challenge, _ = mfaLogin(testMFAEmail, testPassword)
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: activated.RecoveryCodes[0]}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//The wrong codes add up over the challenges, a new password login does not start over
+	for i := 0; i < 4; i++ {
+		challenge, _ = mfaLogin(testMFAEmail, testPassword)
+		resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: "000000"}, "")
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	}
+
+	code, _ = models.TOTP(enrollment.Data.Secret, time.Now().Add(-30*time.Second))
+
+	challenge, _ = mfaLogin(testMFAEmail, testPassword)
+	resp = request("POST", "/v1/user/login/mfa", forms.MFALoginForm{MFAToken: challenge, Code: code}, "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+
+	resp = request("POST", "/v1/user/mfa/disable", forms.MFACodeForm{Code: activated.RecoveryCodes[1]}, loggedIn.AccessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	challenge, token = mfaLogin(testMFAEmail, testPassword)
+	assert.Empty(t, challenge)
+	assert.NotEmpty(t, token)
+}
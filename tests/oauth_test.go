@@ -0,0 +1,265 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRedirectURI = "https://partner.test/callback"
+var testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+var oauthPublicClient models.OAuthClient
+var oauthConfidentialClient models.OAuthClient
+var oauthRefreshToken string
+
+// tokenRequest posts the form encoded values to the token endpoint through the test router
+func tokenRequest(values url.Values) (*httptest.ResponseRecorder, models.OAuthToken) {
+	req, _ := http.NewRequest("POST", "/v1/oauth/token", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	var token models.OAuthToken
+	decode(resp, &token)
+
+	return resp, token
+}
+
+// codeChallenge returns the S256 PKCE challenge of the verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authorizeForm returns an authorization request of the public client for reading the customers
+func authorizeForm(approve bool) forms.AuthorizeForm {
+	return forms.AuthorizeForm{
+		ResponseType:        "code",
+		ClientID:            oauthPublicClient.ClientID,
+		RedirectURI:         testRedirectURI,
+		Scope:               "customer:read",
+		State:               "xyz",
+		CodeChallenge:       codeChallenge(testCodeVerifier),
+		CodeChallengeMethod: "S256",
+		Approve:             approve,
+	}
+}
+
+/**
+* TestCreateOAuthClient
+* Test registering a public and a confidential client
+*
+* Must return response code 200, with a secret for the confidential client only
+ */
+func TestCreateOAuthClient(t *testing.T) {
+	var res struct {
+		Data models.OAuthClient `json:"data"`
+	}
+
+	form := forms.CreateOAuthClientForm{Name: "Testing app", RedirectURIs: []string{testRedirectURI}, Scopes: []string{"customer:read", "product:read"}}
+
+	resp := request("POST", "/v1/oauth/client", form, accessToken)
+	decode(resp, &res)
+	oauthPublicClient = res.Data
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, oauthPublicClient.ClientID)
+	assert.Empty(t, oauthPublicClient.ClientSecret)
+
+	form = forms.CreateOAuthClientForm{Name: "Testing server", Scopes: []string{"customer:read"}, Confidential: true}
+
+	resp = request("POST", "/v1/oauth/client", form, accessToken)
+	decode(resp, &res)
+	oauthConfidentialClient = res.Data
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotEmpty(t, oauthConfidentialClient.ClientSecret)
+
+	//A public client needs somewhere to send the code
+	resp = request("POST", "/v1/oauth/client", forms.CreateOAuthClientForm{Name: "Testing app", Scopes: []string{"customer:read"}}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestOAuthAuthorizationCode
+* Test the authorization code flow with PKCE end-to-end: consent, code, tokens, refresh
+*
+* Must return tokens limited to the approved scope and reject a reused code or a wrong verifier
+ */
+func TestOAuthAuthorizationCode(t *testing.T) {
+	form := authorizeForm(true)
+
+	query := url.Values{}
+	query.Set("response_type", form.ResponseType)
+	query.Set("client_id", form.ClientID)
+	query.Set("redirect_uri", form.RedirectURI)
+	query.Set("scope", form.Scope)
+	query.Set("code_challenge", form.CodeChallenge)
+	query.Set("code_challenge_method", form.CodeChallengeMethod)
+
+	var consent struct {
+		Data models.OAuthConsent `json:"data"`
+	}
+	resp := request("GET", "/v1/oauth/authorize?"+query.Encode(), nil, accessToken)
+	decode(resp, &consent)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Testing app", consent.Data.Client)
+	assert.Equal(t, []string{"customer:read"}, consent.Data.Scopes)
+
+	var res struct {
+		RedirectURI string `json:"redirect_uri"`
+	}
+	resp = request("POST", "/v1/oauth/authorize", form, accessToken)
+	decode(resp, &res)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	redirect, _ := url.Parse(res.RedirectURI)
+	code := redirect.Query().Get("code")
+	assert.NotEmpty(t, code)
+	assert.Equal(t, "xyz", redirect.Query().Get("state"))
+
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("client_id", oauthPublicClient.ClientID)
+	values.Set("code", code)
+	values.Set("redirect_uri", testRedirectURI)
+	values.Set("code_verifier", testCodeVerifier)
+
+	resp, token := tokenRequest(values)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, "customer:read", token.Scope)
+	assert.NotEmpty(t, token.RefreshToken)
+
+	oauthRefreshToken = token.RefreshToken
+
+	resp = request("GET", "/v1/customers", nil, token.AccessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/products", nil, token.AccessToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	//The token can not be turned into a full login token
+	resp = request("POST", "/v1/organization", forms.CreateOrganizationForm{Name: "Testing escalation"}, token.AccessToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	//The code is single use
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	//A code is only redeemed with the verifier of its challenge
+	resp = request("POST", "/v1/oauth/authorize", form, accessToken)
+	decode(resp, &res)
+	redirect, _ = url.Parse(res.RedirectURI)
+
+	values.Set("code", redirect.Query().Get("code"))
+	values.Set("code_verifier", strings.Repeat("a", 43))
+
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+/**
+* TestOAuthRefreshToken
+* Test refreshing the tokens of the client through the token endpoint
+*
+* Must return response code 200 with the same scope, and 400 when the refresh token is reused
+ */
+func TestOAuthRefreshToken(t *testing.T) {
+	values := url.Values{}
+	values.Set("grant_type", "refresh_token")
+	values.Set("client_id", oauthPublicClient.ClientID)
+	values.Set("refresh_token", oauthRefreshToken)
+
+	resp, token := tokenRequest(values)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "customer:read", token.Scope)
+
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	//The tokens of a client are not refreshed outside of the token endpoint
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: token.RefreshToken}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestOAuthDeniedAuthorization
+* Test the user denying the authorization and a redirect URI that is not registered
+*
+* Must redirect with access_denied and return 406 for the unknown redirect URI
+ */
+func TestOAuthDeniedAuthorization(t *testing.T) {
+	var res struct {
+		RedirectURI string `json:"redirect_uri"`
+	}
+	resp := request("POST", "/v1/oauth/authorize", authorizeForm(false), accessToken)
+	decode(resp, &res)
+
+	redirect, _ := url.Parse(res.RedirectURI)
+	assert.Equal(t, "access_denied", redirect.Query().Get("error"))
+	assert.Empty(t, redirect.Query().Get("code"))
+
+	form := authorizeForm(true)
+	form.RedirectURI = "https://attacker.test/callback"
+
+	resp = request("POST", "/v1/oauth/authorize", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestOAuthClientCredentials
+* Test the client credentials grant of the confidential client, with HTTP Basic authentication
+*
+* Must return an access token without refresh token, 401 with a wrong secret and 400 for the public client
+ */
+func TestOAuthClientCredentials(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/v1/oauth/token", strings.NewReader("grant_type=client_credentials"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(oauthConfidentialClient.ClientID, oauthConfidentialClient.ClientSecret)
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	var token models.OAuthToken
+	decode(resp, &token)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, token.RefreshToken)
+
+	resp = request("GET", "/v1/customers", nil, token.AccessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	values := url.Values{}
+	values.Set("grant_type", "client_credentials")
+	values.Set("client_id", oauthConfidentialClient.ClientID)
+	values.Set("client_secret", "gbs_wrong")
+
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	values.Set("client_id", oauthPublicClient.ClientID)
+	values.Del("client_secret")
+
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	values.Set("grant_type", "password")
+
+	resp, _ = tokenRequest(values)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
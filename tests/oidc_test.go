@@ -0,0 +1,255 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testOIDCEmail = "test-gin-boilerplate-oidc@test.com"
+var testReclaimEmail = "test-gin-boilerplate-reclaim@test.com"
+
+// stubIssuer is a local OpenID Connect issuer: the tests decide the claims of the ID token issued for each code
+type stubIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	codes  map[string]jwt.MapClaims
+}
+
+// newStubIssuer starts the issuer and configures it as the "stub" login provider
+func newStubIssuer() *stubIssuer {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	issuer := &stubIssuer{key: key, codes: map[string]jwt.MapClaims{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "stub",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		issuer.mu.Lock()
+		claims, ok := issuer.codes[r.PostForm.Get("code")]
+		delete(issuer.codes, r.PostForm.Get("code"))
+		issuer.mu.Unlock()
+
+		if !ok || r.PostForm.Get("client_id") != "stub-client" || r.PostForm.Get("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		idToken.Header["kid"] = "stub"
+		signed, _ := idToken.SignedString(key)
+
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "stub-access-token", "token_type": "Bearer", "id_token": signed})
+	})
+	issuer.server = httptest.NewServer(mux)
+
+	os.Setenv("OIDC_PROVIDERS", "stub")
+	os.Setenv("OIDC_STUB_ISSUER", issuer.server.URL)
+	os.Setenv("OIDC_STUB_CLIENT_ID", "stub-client")
+	os.Setenv("OIDC_STUB_CLIENT_SECRET", "stub-secret")
+	os.Setenv("OIDC_STUB_REDIRECT_URI", "http://localhost/v1/oidc/stub/callback")
+
+	return issuer
+}
+
+// login goes through the login at the issuer for a user with the claims, edit may change the claims of the ID token
+func (issuer *stubIssuer) login(subject, email string, edit func(jwt.MapClaims)) *httptest.ResponseRecorder {
+	var res struct {
+		URL string `json:"url"`
+	}
+	decode(request("GET", "/v1/oidc/stub/login", nil, ""), &res)
+
+	authURL, _ := url.Parse(res.URL)
+
+	claims := jwt.MapClaims{
+		"iss":            issuer.server.URL,
+		"aud":            "stub-client",
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"name":           "testing oidc",
+		"nonce":          authURL.Query().Get("nonce"),
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	}
+	if edit != nil {
+		edit(claims)
+	}
+
+	code := fmt.Sprintf("code-%d", time.Now().UnixNano())
+
+	issuer.mu.Lock()
+	issuer.codes[code] = claims
+	issuer.mu.Unlock()
+
+	query := url.Values{}
+	query.Set("code", code)
+	query.Set("state", authURL.Query().Get("state"))
+
+	return request("GET", "/v1/oidc/stub/callback?"+query.Encode(), nil, "")
+}
+
+// loggedInUser returns the user of a successful login response
+func loggedInUser(resp *httptest.ResponseRecorder) (user models.User, token models.Token) {
+	var res struct {
+		User  models.User  `json:"user"`
+		Token models.Token `json:"token"`
+	}
+	decode(resp, &res)
+
+	return res.User, res.Token
+}
+
+/**
+* TestOIDCLogin
+* Test logging in with the stub issuer: a new user is created, then found again by the identity,
+* and an existing user is linked by their verified email
+*
+* Must return response code 200 with the usual pair of tokens
+ */
+func TestOIDCLogin(t *testing.T) {
+	issuer := newStubIssuer()
+	defer issuer.server.Close()
+
+	var providers struct {
+		Results []string `json:"results"`
+	}
+	decode(request("GET", "/v1/oidc/providers", nil, ""), &providers)
+	assert.Equal(t, []string{"stub"}, providers.Results)
+
+	resp := issuer.login("stub-subject-1", testOIDCEmail, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	user, token := loggedInUser(resp)
+	assert.Equal(t, testOIDCEmail, user.Email)
+	assert.NotEmpty(t, token.AccessToken)
+
+	resp = request("GET", "/v1/organizations", nil, token.AccessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	again, _ := loggedInUser(issuer.login("stub-subject-1", testOIDCEmail, nil))
+	assert.Equal(t, user.ID, again.ID)
+
+	//The user has no password, a password login fails as for a wrong one
+	resp = request("POST", "/v1/user/login", forms.LoginForm{Email: testOIDCEmail, Password: testPassword}, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	//The password user is linked rather than duplicated
+	var res struct {
+		User models.User `json:"user"`
+	}
+	decode(request("POST", "/v1/user/login", forms.LoginForm{Email: testEmail, Password: testPassword}, ""), &res)
+
+	linked, _ := loggedInUser(issuer.login("stub-subject-2", testEmail, nil))
+	assert.Equal(t, res.User.ID, linked.ID)
+}
+
+/**
+* TestOIDCReclaimUnverified
+* Test signing in with a provider to an account registered with the email but never verified,
+* e.g. by someone else before the owner of the email
+*
+* Must log the owner in and end the password and the sessions of whoever registered it
+ */
+func TestOIDCReclaimUnverified(t *testing.T) {
+	issuer := newStubIssuer()
+	defer issuer.server.Close()
+
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing reclaim", Email: testReclaimEmail, Password: testPassword}, "")
+
+	squatterToken, _ := login(testReclaimEmail, testPassword)
+	assert.NotEmpty(t, squatterToken)
+
+	resp := issuer.login("stub-subject-reclaim", testReclaimEmail, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	_, token := loggedInUser(resp)
+	assert.NotEmpty(t, token.AccessToken)
+
+	resp = request("GET", "/v1/organizations", nil, squatterToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/user/login", forms.LoginForm{Email: testReclaimEmail, Password: testPassword}, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = request("GET", "/v1/organizations", nil, token.AccessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestOIDCInvalidLogin
+* Test the ID tokens and states that must be rejected
+*
+* Must return response code 401 for a wrong nonce, audience, expiry or state or a missing claim, 403 for an unverified email
+ */
+func TestOIDCInvalidLogin(t *testing.T) {
+	issuer := newStubIssuer()
+	defer issuer.server.Close()
+
+	resp := issuer.login("stub-subject-3", testOIDCEmail, func(claims jwt.MapClaims) { claims["nonce"] = "replayed" })
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = issuer.login("stub-subject-3", testOIDCEmail, func(claims jwt.MapClaims) { claims["aud"] = "another-client" })
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = issuer.login("stub-subject-3", testOIDCEmail, func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() })
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//Every claim the login relies on is required
+	for _, claim := range []string{"exp", "iat", "iss", "aud", "nonce", "sub"} {
+		name := claim
+		resp = issuer.login("stub-subject-3", testOIDCEmail, func(claims jwt.MapClaims) { delete(claims, name) })
+		assert.Equal(t, http.StatusUnauthorized, resp.Code, claim)
+	}
+
+	resp = issuer.login("stub-subject-3", testOIDCEmail, func(claims jwt.MapClaims) { claims["email_verified"] = false })
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	//A state that was never issued is rejected
+	query := url.Values{}
+	query.Set("code", "unknown")
+	query.Set("state", "unknown")
+	resp = request("GET", "/v1/oidc/stub/callback?"+query.Encode(), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/oidc/unknown/login", nil, "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,258 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var orderID int64
+var orderCustomerID int64
+var orderProductID int64
+var lifecycleOrderID int64
+
+// orderForm returns a valid order with two items: 2 x 10.00 at 20% tax and 1 x 9.99 without tax
+func orderForm() (form forms.CreateOrderForm) {
+	form.CustomerID = orderCustomerID
+	form.Currency = "USD"
+	form.Notes = "Testing order notes"
+	form.Items = []forms.OrderItemForm{
+		{ProductID: orderProductID, Quantity: 2, UnitPrice: 1000, TaxRate: 2000},
+		{ProductID: orderProductID, Quantity: 1, UnitPrice: 999},
+	}
+	return form
+}
+
+/**
+* TestCreateOrder
+* Test order creation with its items and computed totals
+*
+* Must return response code 200
+ */
+func TestCreateOrder(t *testing.T) {
+	orderCustomerID = createTestCustomer()
+	orderProductID = createTestProduct()
+
+	resp := request("POST", "/v1/order", orderForm(), accessToken)
+
+	var res struct {
+		ID   int64        `json:"id"`
+		Data models.Order `json:"data"`
+	}
+	decode(resp, &res)
+
+	orderID = res.ID
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "draft", res.Data.Status)
+	assert.Equal(t, fmt.Sprintf("ORD-%06d", orderID), res.Data.Number)
+	assert.Len(t, res.Data.Items, 2)
+	assert.Equal(t, int64(2999), res.Data.Subtotal)
+	assert.Equal(t, int64(400), res.Data.TaxTotal)
+	assert.Equal(t, int64(3399), res.Data.Total)
+}
+
+/**
+* TestCreateInvalidOrder
+* Test order invalid creation without items
+*
+* Must return response code 406
+ */
+func TestCreateInvalidOrder(t *testing.T) {
+	form := orderForm()
+	form.Items = nil
+
+	resp := request("POST", "/v1/order", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestCreateOrderInvalidCurrency
+* Test order creation with a currency that is not ISO 4217
+*
+* Must return response code 406
+ */
+func TestCreateOrderInvalidCurrency(t *testing.T) {
+	form := orderForm()
+	form.Currency = "ABC"
+
+	resp := request("POST", "/v1/order", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestCreateOrderUnitPriceTooLarge
+* Test order creation with a unit price above the limit that keeps the totals within int64
+*
+* Must return response code 406
+ */
+func TestCreateOrderUnitPriceTooLarge(t *testing.T) {
+	form := orderForm()
+	form.Items[0].UnitPrice = 1000000001
+
+	resp := request("POST", "/v1/order", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestCreateOrderUnknownProduct
+* Test order creation with a product that does not belong to the user
+*
+* Must return response code 406
+ */
+func TestCreateOrderUnknownProduct(t *testing.T) {
+	form := orderForm()
+	form.Items[0].ProductID = orderProductID + 1000000
+
+	resp := request("POST", "/v1/order", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestGetOrder
+* Test getting one order
+*
+* Must return response code 200
+ */
+func TestGetOrder(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/order/%d", orderID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetOrders
+* Test listing the orders
+*
+* Must return response code 200
+ */
+func TestGetOrders(t *testing.T) {
+	resp := request("GET", "/v1/orders", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetInvalidOrder
+* Test getting invalid order
+*
+* Must return response code 404
+ */
+func TestGetInvalidOrder(t *testing.T) {
+	resp := request("GET", "/v1/order/invalid", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+/**
+* TestGetOrderNotLoggedin
+* Test getting the order with logged out user
+*
+* Must return response code 401
+ */
+func TestGetOrderNotLoggedin(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/order/%d", orderID), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestUpdateOrder
+* Test updating an order items
+*
+* Must return response code 200
+ */
+func TestUpdateOrder(t *testing.T) {
+	form := orderForm()
+	form.Items = form.Items[:1]
+
+	resp := request("PUT", fmt.Sprintf("/v1/order/%d", orderID), form, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var res struct {
+		Data models.Order `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", orderID), nil, accessToken), &res)
+
+	assert.Len(t, res.Data.Items, 1)
+	assert.Equal(t, int64(2400), res.Data.Total)
+}
+
+/**
+* TestOrderTransition
+* Test moving an order through its lifecycle and rejecting the illegal moves
+*
+* Must return response code 200 for legal moves and 409 for illegal ones
+ */
+func TestOrderTransition(t *testing.T) {
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/order", orderForm(), accessToken), &res)
+
+	lifecycleOrderID = res.ID
+
+	url := fmt.Sprintf("/v1/order/%d/transition", lifecycleOrderID)
+
+	resp := request("POST", url, forms.OrderTransitionForm{Status: "paid"}, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request("POST", url, forms.OrderTransitionForm{Status: "placed", Reason: "Customer confirmed"}, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", url, forms.OrderTransitionForm{Status: "paid"}, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", url, forms.OrderTransitionForm{Status: "draft"}, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request("POST", url, forms.OrderTransitionForm{Status: "unknown"}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestOrderHistory
+* Test listing the recorded status changes of an order
+*
+* Must return response code 200
+ */
+func TestOrderHistory(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/order/%d/history", lifecycleOrderID), nil, accessToken)
+
+	var res struct {
+		Results []models.OrderStatusHistory `json:"results"`
+	}
+	decode(resp, &res)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, res.Results, 2)
+	assert.Equal(t, "draft", res.Results[0].FromStatus)
+	assert.Equal(t, "placed", res.Results[0].ToStatus)
+	assert.Equal(t, "Customer confirmed", res.Results[0].Reason)
+	assert.Equal(t, "paid", res.Results[1].ToStatus)
+}
+
+/**
+* TestUpdatePlacedOrder
+* Test updating an order that is not a draft anymore
+*
+* Must return response code 406
+ */
+func TestUpdatePlacedOrder(t *testing.T) {
+	resp := request("PUT", fmt.Sprintf("/v1/order/%d", lifecycleOrderID), orderForm(), accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestDeleteOrder
+* Test deleting an order
+*
+* Must return response code 200
+ */
+func TestDeleteOrder(t *testing.T) {
+	resp := request("DELETE", fmt.Sprintf("/v1/order/%d", orderID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
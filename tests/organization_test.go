@@ -0,0 +1,189 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMemberEmail = "test-gin-boilerplate-member@test.com"
+
+var organizationID int64
+var organizationToken string
+var organizationCustomerID int64
+
+// switchOrganization returns the response code with the access token of the organization
+func switchOrganization(id int64, token string) (int, string) {
+	resp := request("POST", fmt.Sprintf("/v1/organization/%d/switch", id), nil, token)
+
+	var res struct {
+		Token models.Token `json:"token"`
+	}
+	decode(resp, &res)
+
+	return resp.Code, res.Token.AccessToken
+}
+
+/**
+* TestCreateOrganization
+* Test creating a second organization next to the one created on register
+*
+* Must return response code 200 and list both organizations
+ */
+func TestCreateOrganization(t *testing.T) {
+	resp := request("POST", "/v1/organization", forms.CreateOrganizationForm{Name: "Testing organization"}, accessToken)
+
+	var res struct {
+		ID   int64               `json:"id"`
+		Data models.Organization `json:"data"`
+	}
+	decode(resp, &res)
+
+	organizationID = res.ID
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, models.RoleOwner, res.Data.Role)
+
+	var list struct {
+		Results []models.Organization `json:"results"`
+	}
+	decode(request("GET", "/v1/organizations", nil, accessToken), &list)
+
+	assert.Len(t, list.Results, 2)
+
+	resp = request("POST", "/v1/organization", forms.CreateOrganizationForm{Name: "T"}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestSwitchOrganization
+* Test the data of an organization is only reachable with a token of that organization
+*
+* Must return response code 200 in the new organization and 404 from the other one
+ */
+func TestSwitchOrganization(t *testing.T) {
+	var code int
+
+	code, organizationToken = switchOrganization(organizationID, accessToken)
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotEmpty(t, organizationToken)
+
+	var list struct {
+		Results []struct {
+			Data []models.Customer `json:"data"`
+		} `json:"results"`
+	}
+	decode(request("GET", "/v1/customers", nil, organizationToken), &list)
+	assert.Empty(t, list.Results[0].Data)
+
+	var res struct {
+		ID int64 `json:"id"`
+	}
+	decode(request("POST", "/v1/customer", customerForm(), organizationToken), &res)
+
+	organizationCustomerID = res.ID
+
+	resp := request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, organizationToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = request("DELETE", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestSwitchInvalidOrganization
+* Test switching to an organization the user is not a member of
+*
+* Must return response code 403
+ */
+func TestSwitchInvalidOrganization(t *testing.T) {
+	code, _ := switchOrganization(organizationID+1000000, accessToken)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+/**
+* TestInviteMember
+* Test inviting a user who accepts and then works with the data of the organization
+*
+* Must return response code 200, 409 for a second invitation and 403 when a member invites
+ */
+func TestInviteMember(t *testing.T) {
+	registerForm := forms.RegisterForm{Name: "testing member", Email: testMemberEmail, Password: testPassword}
+	resp := request("POST", "/v1/user/register", registerForm, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	form := forms.InviteMemberForm{Email: testMemberEmail}
+
+	resp = request("POST", "/v1/organization/invitations", form, organizationToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/organization/invitations", form, organizationToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	memberToken, _ := login(testMemberEmail, testPassword)
+
+	var invitations struct {
+		Results []models.Invitation `json:"results"`
+	}
+
+	//The invitations are only shown to the owner of the email once it is verified
+	decode(request("GET", "/v1/invitations", nil, memberToken), &invitations)
+	assert.Empty(t, invitations.Results)
+
+	invitationID, err := db.GetDB().SelectInt("SELECT id FROM public.invitation WHERE email=$1 AND organization_id=$2", testMemberEmail, organizationID)
+	assert.Nil(t, err)
+
+	resp = request("POST", fmt.Sprintf("/v1/invitation/%d/accept", invitationID), nil, memberToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	assert.Equal(t, http.StatusOK, verifyEmail(testMemberEmail))
+
+	decode(request("GET", "/v1/invitations", nil, memberToken), &invitations)
+
+	assert.Len(t, invitations.Results, 1)
+	assert.Equal(t, organizationID, invitations.Results[0].OrganizationID)
+
+	//The organization data is not reachable before switching to it
+	resp = request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, memberToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = request("POST", fmt.Sprintf("/v1/invitation/%d/accept", invitations.Results[0].ID), nil, memberToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	code, memberToken := switchOrganization(organizationID, memberToken)
+	assert.Equal(t, http.StatusOK, code)
+
+	resp = request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, memberToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var members struct {
+		Results []models.Member `json:"results"`
+	}
+	decode(request("GET", "/v1/organization/members", nil, memberToken), &members)
+	assert.Len(t, members.Results, 2)
+
+	resp = request("POST", "/v1/organization/invitations", forms.InviteMemberForm{Email: testRegisterEmail}, memberToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+/**
+* TestAcceptInvalidInvitation
+* Test accepting an invitation that does not exist
+*
+* Must return response code 404
+ */
+func TestAcceptInvalidInvitation(t *testing.T) {
+	resp := request("POST", "/v1/invitation/1000000000/accept", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
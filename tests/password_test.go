@@ -0,0 +1,89 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPolicyEmail = "test-gin-boilerplate-policy@test.com"
+
+/**
+* TestPasswordPolicy
+* Test the registration with a password breaking each rule of the policy
+*
+* Must return response code 406 with the message of the rule, and 200 once the password follows the policy
+ */
+func TestPasswordPolicy(t *testing.T) {
+	defer setEnv("PASSWORD_MIN_LENGTH", "10")()
+	defer setEnv("PASSWORD_CHARACTER_CLASSES", "2")()
+
+	form := forms.RegisterForm{Name: "testing policy", Email: testPolicyEmail}
+
+	cases := []struct {
+		password string
+		message  string
+	}{
+		{"Short-1", forms.UserForm{}.Password(forms.PasswordTooShort, "10")},
+		{"onlylowercaseletters", forms.UserForm{}.Password(forms.PasswordClasses, "2")},
+		{"Policy-Lantern-77", forms.UserForm{}.Password(forms.PasswordIdentity)},
+		{"P@ssw0rd123", forms.UserForm{}.Password(forms.PasswordBreached)},
+		//64 characters, but 96 bytes bcrypt would truncate
+		{strings.Repeat("Ünïcødé-", 8), forms.UserForm{}.Password(forms.PasswordTooLarge, "72")},
+	}
+
+	for _, c := range cases {
+		var res struct {
+			Message string `json:"message"`
+		}
+
+		form.Password = c.password
+		resp := request("POST", "/v1/user/register", form, "")
+		decode(resp, &res)
+
+		assert.Equal(t, http.StatusNotAcceptable, resp.Code, c.password)
+		assert.Equal(t, c.message, res.Message, c.password)
+	}
+
+	form.Password = testPassword
+	resp := request("POST", "/v1/user/register", form, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestChangePassword
+* Test changing the password with the current one, the new one following the policy
+*
+* Must return response code 401 for a wrong current password, 406 for a breached one,
+* and end the other sessions once changed
+ */
+func TestChangePassword(t *testing.T) {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing policy", Email: testPolicyEmail, Password: testPassword}, "")
+
+	token, _ := login(testPolicyEmail, testPassword)
+	other, _ := login(testPolicyEmail, testPassword)
+
+	resp := request("PUT", "/v1/user/password", forms.ChangePasswordForm{CurrentPassword: "wrong-password", Password: "Quiet-Harbor-Night-8"}, token)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("PUT", "/v1/user/password", forms.ChangePasswordForm{CurrentPassword: testPassword, Password: "Password123!"}, token)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = request("PUT", "/v1/user/password", forms.ChangePasswordForm{CurrentPassword: testPassword, Password: "Quiet-Harbor-Night-8"}, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, other)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	token, _ = login(testPolicyEmail, "Quiet-Harbor-Night-8")
+	assert.NotEmpty(t, token)
+}
@@ -0,0 +1,152 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var paymentInvoiceID int64
+
+// pay records a payment against the invoice and returns the response code with the new invoice balance
+func pay(invoiceID, amount int64) (int, models.Balance) {
+	form := forms.CreatePaymentForm{Amount: amount, Method: "bank_transfer", Reference: "Testing reference"}
+
+	resp := request("POST", fmt.Sprintf("/v1/invoice/%d/payments", invoiceID), form, accessToken)
+
+	var res struct {
+		Data models.Balance `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/invoice/%d/balance", invoiceID), nil, accessToken), &res)
+
+	return resp.Code, res.Data
+}
+
+/**
+* TestPayDraftInvoice
+* Test recording a payment against an invoice that is not issued
+*
+* Must return response code 409
+ */
+func TestPayDraftInvoice(t *testing.T) {
+	_, invoice := generateInvoice(createTestOrder("placed"))
+
+	paymentInvoiceID = invoice.ID
+
+	code, _ := pay(paymentInvoiceID, 1000)
+	assert.Equal(t, http.StatusConflict, code)
+
+	request("POST", fmt.Sprintf("/v1/invoice/%d/issue", paymentInvoiceID), nil, accessToken)
+}
+
+/**
+* TestCreateInvalidPayment
+* Test recording a payment without amount and with an unknown method
+*
+* Must return response code 406
+ */
+func TestCreateInvalidPayment(t *testing.T) {
+	url := fmt.Sprintf("/v1/invoice/%d/payments", paymentInvoiceID)
+
+	resp := request("POST", url, forms.CreatePaymentForm{Method: "cash"}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+
+	resp = request("POST", url, forms.CreatePaymentForm{Amount: 1000, Method: "barter"}, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestCreatePartialPayment
+* Test recording a payment smaller than the invoice total
+*
+* Must return response code 200 and keep the invoice issued
+ */
+func TestCreatePartialPayment(t *testing.T) {
+	code, balance := pay(paymentInvoiceID, 1000)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "issued", balance.Status)
+	assert.Equal(t, int64(3399), balance.Total)
+	assert.Equal(t, int64(1000), balance.Paid)
+	assert.Equal(t, int64(2399), balance.Balance)
+}
+
+/**
+* TestOverpayment
+* Test recording a payment larger than the invoice balance
+*
+* Must return response code 409
+ */
+func TestOverpayment(t *testing.T) {
+	code, balance := pay(paymentInvoiceID, 2400)
+
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, int64(2399), balance.Balance)
+}
+
+/**
+* TestPayInvoiceInFull
+* Test recording the payment of the remaining balance
+*
+* Must return response code 200 and mark the invoice paid
+ */
+func TestPayInvoiceInFull(t *testing.T) {
+	code, balance := pay(paymentInvoiceID, 2399)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "paid", balance.Status)
+	assert.Equal(t, int64(0), balance.Balance)
+}
+
+/**
+* TestRefundPayment
+* Test refunding part of a paid invoice
+*
+* Must return response code 200 and bring the invoice back to issued
+ */
+func TestRefundPayment(t *testing.T) {
+	code, balance := pay(paymentInvoiceID, -500)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "issued", balance.Status)
+	assert.Equal(t, int64(3399), balance.Paid)
+	assert.Equal(t, int64(500), balance.Refunded)
+	assert.Equal(t, int64(500), balance.Balance)
+}
+
+/**
+* TestRefundExceedsPaid
+* Test refunding more than what was paid
+*
+* Must return response code 409
+ */
+func TestRefundExceedsPaid(t *testing.T) {
+	code, _ := pay(paymentInvoiceID, -3000)
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+/**
+* TestGetPayments
+* Test listing the ledger of the invoice
+*
+* Must return response code 200
+ */
+func TestGetPayments(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/invoice/%d/payments", paymentInvoiceID), nil, accessToken)
+
+	var res struct {
+		Results []models.Payment `json:"results"`
+	}
+	decode(resp, &res)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, res.Results, 3)
+	assert.Equal(t, int64(-500), res.Results[2].Amount)
+}
@@ -0,0 +1,95 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testReadOnlyEmail = "test-gin-boilerplate-read-only@test.com"
+var testBillingEmail = "test-gin-boilerplate-billing@test.com"
+
+// joinOrganization registers a user invited with the role to the testing organization and returns its access token in it
+func joinOrganization(email, role string) string {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing " + role, Email: email, Password: testPassword}, "")
+	verifyEmail(email)
+	request("POST", "/v1/organization/invitations", forms.InviteMemberForm{Email: email, Role: role}, organizationToken)
+
+	token, _ := login(email, testPassword)
+
+	var invitations struct {
+		Results []models.Invitation `json:"results"`
+	}
+	decode(request("GET", "/v1/invitations", nil, token), &invitations)
+
+	for _, invitation := range invitations.Results {
+		request("POST", fmt.Sprintf("/v1/invitation/%d/accept", invitation.ID), nil, token)
+	}
+
+	_, token = switchOrganization(organizationID, token)
+
+	return token
+}
+
+/**
+* TestReadOnlyPermissions
+* Test a read-only member can read the data of the organization but not change it
+*
+* Must return response code 200 on GET and 403 on POST/PUT/DELETE
+ */
+func TestReadOnlyPermissions(t *testing.T) {
+	token := joinOrganization(testReadOnlyEmail, models.RoleReadOnly)
+	assert.NotEmpty(t, token)
+
+	resp := request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/invoices", nil, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/customer", customerForm(), token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = request("PUT", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), customerForm(), token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = request("DELETE", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = request("POST", "/v1/organization/invitations", forms.InviteMemberForm{Email: testBillingEmail}, token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
+
+/**
+* TestBillingPermissions
+* Test only the billing roles issue invoices, a plain member can not
+*
+* Must return response code 403 for the member and pass the permission check for billing
+ */
+func TestBillingPermissions(t *testing.T) {
+	memberToken, _ := login(testMemberEmail, testPassword)
+	_, memberToken = switchOrganization(organizationID, memberToken)
+
+	resp := request("POST", "/v1/invoice/1000000000/issue", nil, memberToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = request("POST", "/v1/invoice/1000000000/payments", nil, memberToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	token := joinOrganization(testBillingEmail, models.RoleBilling)
+	assert.NotEmpty(t, token)
+
+	//The invoice does not exist, but the role is allowed to issue it
+	resp = request("POST", "/v1/invoice/1000000000/issue", nil, token)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp = request("POST", "/v1/customer", customerForm(), token)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
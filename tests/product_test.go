@@ -7,247 +7,35 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
-
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
-	"github.com/Massad/gin-boilerplate/controllers"
-	"github.com/Massad/gin-boilerplate/db"
 	"github.com/Massad/gin-boilerplate/forms"
-	"github.com/joho/godotenv"
-
-	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
+	"github.com/Massad/gin-boilerplate/models"
 	"github.com/stretchr/testify/assert"
 )
 
-var auth = new(controllers.AuthController)
-
-// TokenAuthMiddleware ...
-// JWT Authentication middleware attached to each request that needs to be authenitcated to validate the access_token in the header
-func TokenAuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		auth.TokenValid(c)
-		c.Next()
-	}
-}
-
-func SetupRouter() *gin.Engine {
-	r := gin.Default()
-	gin.SetMode(gin.TestMode)
-
-	//Custom form validator
-	binding.Validator = new(forms.DefaultValidator)
-
-	v1 := r.Group("/v1")
-	{
-		/*** START USER ***/
-		user := new(controllers.UserController)
-
-		v1.POST("/user/login", user.Login)
-		v1.POST("/user/register", user.Register)
-		v1.GET("/user/logout", user.Logout)
-
-		/*** START AUTH ***/
-		auth := new(controllers.AuthController)
-
-		v1.POST("/token/refresh", auth.Refresh)
-
-		/*** START Product ***/
-		product := new(controllers.ProductController)
-
-		v1.POST("/product", TokenAuthMiddleware(), product.Create)
-		v1.GET("/products", TokenAuthMiddleware(), product.All)
-		v1.GET("/product/:id", TokenAuthMiddleware(), product.One)
-		v1.PUT("/product/:id", TokenAuthMiddleware(), product.Update)
-		v1.DELETE("/product/:id", TokenAuthMiddleware(), product.Delete)
-	}
-
-	return r
-}
-
-func main() {
-	r := SetupRouter()
-	r.Run()
-}
-
-var loginCookie string
-
-var testEmail = "test-gin-boilerplate@test.com"
-var testPassword = "123456"
-
-var accessToken string
-var refreshToken string
-
 var productID int
-
-/**
-* TestIntDB
-* It tests the connection to the database and init the db for this test
-*
-* Must pass
- */
-func TestIntDB(t *testing.T) {
-
-	//Load the .env file
-	err := godotenv.Load("../.env")
-	if err != nil {
-		log.Fatal("Error loading .env file, please create one in the root directory")
+var productSKU string
+
+// productForm returns a valid product with two variants, the second one overriding the price
+func productForm() (form forms.CreateProductForm) {
+	price := int64(2500)
+
+	form.SKU = productSKU
+	form.Name = "Testing product name"
+	form.Description = "Testing product description"
+	form.Price = 1999
+	form.Currency = "USD"
+	form.Attributes = map[string]interface{}{"material": "cotton", "weight": 180}
+	form.Variants = []forms.ProductVariantForm{
+		{SKU: productSKU + "-M-RED", Size: "M", Color: "red"},
+		{SKU: productSKU + "-XL-RED", Size: "XL", Color: "red", Price: &price},
 	}
-
-	fmt.Println("DB_PASS", os.Getenv("DB_PASS"))
-
-	db.Init()
-	db.InitRedis(1)
-}
-
-/**
-* TestRegister
-* Test user registration
-*
-* Must return response code 200
- */
-func TestRegister(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var registerForm forms.RegisterForm
-
-	registerForm.Name = "testing"
-	registerForm.Email = testEmail
-	registerForm.Password = testPassword
-
-	data, _ := json.Marshal(registerForm)
-
-	req, err := http.NewRequest("POST", "/v1/user/register", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	resp := httptest.NewRecorder()
-
-	testRouter.ServeHTTP(resp, req)
-	assert.Equal(t, http.StatusOK, resp.Code)
-}
-
-/**
-* TestRegisterInvalidEmail
-* Test user registration with invalid email
-*
-* Must return response code 406
- */
-func TestRegisterInvalidEmail(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var registerForm forms.RegisterForm
-
-	registerForm.Name = "testing"
-	registerForm.Email = "invalid@email"
-	registerForm.Password = testPassword
-
-	data, _ := json.Marshal(registerForm)
-
-	req, err := http.NewRequest("POST", "/v1/user/register", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	resp := httptest.NewRecorder()
-
-	testRouter.ServeHTTP(resp, req)
-	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
-}
-
-/**
-* TestLogin
-* Test user login
-* and get the access_token and refresh_token stored
-*
-* Must return response code 200
- */
-func TestLogin(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var loginForm forms.LoginForm
-
-	loginForm.Email = testEmail
-	loginForm.Password = testPassword
-
-	data, _ := json.Marshal(loginForm)
-
-	req, err := http.NewRequest("POST", "/v1/user/login", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	resp := httptest.NewRecorder()
-
-	testRouter.ServeHTTP(resp, req)
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	var res struct {
-		Message string `json:"message"`
-		User    struct {
-			CreatedAt int64  `json:"created_at"`
-			Email     string `json:"email"`
-			ID        int64  `json:"id"`
-			Name      string `json:"name"`
-			UpdatedAt int64  `json:"updated_at"`
-		} `json:"user"`
-		Token struct {
-			AccessToken  string `json:"access_token"`
-			RefreshToken string `json:"refresh_token"`
-		} `json:"token"`
-	}
-	json.Unmarshal(body, &res)
-
-	accessToken = res.Token.AccessToken
-	refreshToken = res.Token.RefreshToken
-
-	assert.Equal(t, http.StatusOK, resp.Code)
-}
-
-/**
-* TestInvalidLogin
-* Test invalid login
-*
-* Must return response code 406
- */
-func TestInvalidLogin(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var loginForm forms.LoginForm
-
-	loginForm.Email = "wrong@email.com"
-	loginForm.Password = testPassword
-
-	data, _ := json.Marshal(loginForm)
-
-	req, err := http.NewRequest("POST", "/v1/user/login", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
-
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	resp := httptest.NewRecorder()
-
-	testRouter.ServeHTTP(resp, req)
-
-	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+	return form
 }
 
 /**
@@ -259,10 +47,9 @@ func TestInvalidLogin(t *testing.T) {
 func TestCreateProduct(t *testing.T) {
 	testRouter := SetupRouter()
 
-	var form forms.CreateProductForm
+	productSKU = testSKU()
 
-	form.Title = "Testing product title"
-	form.Content = "Testing product content"
+	form := productForm()
 
 	data, _ := json.Marshal(form)
 
@@ -285,12 +72,38 @@ func TestCreateProduct(t *testing.T) {
 	var res struct {
 		Status int
 		ID     int
+		Data   models.Product
 	}
 	json.Unmarshal(body, &res)
 
 	productID = res.ID
 
 	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.True(t, res.Data.Active)
+	assert.Equal(t, "cotton", res.Data.Attributes["material"])
+	assert.Len(t, res.Data.Variants, 2)
+	assert.Nil(t, res.Data.Variants[0].Price)
+	assert.Equal(t, int64(1999), res.Data.Variants[0].EffectivePrice)
+	assert.Equal(t, int64(2500), res.Data.Variants[1].EffectivePrice)
+}
+
+/**
+* TestCreateProductDuplicateSKU
+* Test product creation with a SKU used by another product or variant of the user
+*
+* Must return response code 409
+ */
+func TestCreateProductDuplicateSKU(t *testing.T) {
+	form := productForm()
+	form.Variants = nil
+
+	resp := request("POST", "/v1/product", form, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	form.SKU = productSKU + "-M-RED"
+
+	resp = request("POST", "/v1/product", form, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
 }
 
 /**
@@ -302,9 +115,8 @@ func TestCreateProduct(t *testing.T) {
 func TestCreateInvalidProduct(t *testing.T) {
 	testRouter := SetupRouter()
 
-	var form forms.CreateProductForm
-
-	form.Title = "Testing product title"
+	form := productForm()
+	form.SKU = "invalid sku!"
 
 	data, _ := json.Marshal(form)
 
@@ -344,6 +156,19 @@ func TestGetProduct(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
 
+/**
+* TestGetProducts
+* Test listing the products at /product and at /products
+*
+* Must return response code 200 with the same products on both
+ */
+func TestGetProducts(t *testing.T) {
+	resp := request("GET", "/v1/product", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	assert.Equal(t, listIDs("/v1/products", accessToken), listIDs("/v1/product", accessToken))
+}
+
 /**
 * TestGetInvalidProduct
 * Test getting invalid product
@@ -420,10 +245,12 @@ func TestGetProductUnauthorized(t *testing.T) {
 func TestUpdateProduct(t *testing.T) {
 	testRouter := SetupRouter()
 
-	var form forms.CreateProductForm
+	active := false
 
-	form.Title = "Testing new product title"
-	form.Content = "Testing new product content"
+	form := productForm()
+	form.Name = "Testing new product name"
+	form.Active = &active
+	form.Variants = form.Variants[1:]
 
 	data, _ := json.Marshal(form)
 
@@ -444,96 +271,70 @@ func TestUpdateProduct(t *testing.T) {
 }
 
 /**
-* TestDeleteProduct
-* Test deleting an product
+* TestGetUpdatedProduct
+* Test the update replaced the variants and deactivated the product
 *
 * Must return response code 200
  */
-func TestDeleteProduct(t *testing.T) {
-	testRouter := SetupRouter()
-
-	url := fmt.Sprintf("/v1/product/%d", productID)
-
-	req, err := http.NewRequest("DELETE", url, nil)
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer: %s", accessToken))
+func TestGetUpdatedProduct(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/product/%d", productID), nil, accessToken)
 
-	if err != nil {
-		fmt.Println(err)
+	var res struct {
+		Data models.Product `json:"data"`
 	}
-
-	resp := httptest.NewRecorder()
-	testRouter.ServeHTTP(resp, req)
+	decode(resp, &res)
 
 	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.False(t, res.Data.Active)
+	assert.Len(t, res.Data.Variants, 1)
+	assert.Equal(t, productSKU+"-XL-RED", res.Data.Variants[0].SKU)
 }
 
 /**
-* TestRefreshToken
-* Test refreshing the token with valid refresh_token
+* TestOrderInactiveProduct
+* Test ordering a product that is not active
 *
-* Must return response code 200
+* Must return response code 406
  */
-func TestRefreshToken(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var tokenForm forms.Token
+func TestOrderInactiveProduct(t *testing.T) {
+	var form forms.CreateOrderForm
 
-	tokenForm.RefreshToken = refreshToken
-
-	data, _ := json.Marshal(tokenForm)
-
-	req, err := http.NewRequest("POST", "/v1/token/refresh", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
+	form.CustomerID = createTestCustomer()
+	form.Currency = "USD"
+	form.Items = []forms.OrderItemForm{{ProductID: int64(productID), Quantity: 1, UnitPrice: 1999}}
 
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	resp := httptest.NewRecorder()
-	testRouter.ServeHTTP(resp, req)
-
-	assert.Equal(t, http.StatusOK, resp.Code)
+	resp := request("POST", "/v1/order", form, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
 }
 
 /**
-* TestInvalidRefreshToken
-* Test refreshing the token with invalid refresh_token
+* TestDeleteOrderedProduct
+* Test deleting a product used by an order
 *
-* Must return response code 401
+* Must return response code 409
  */
-func TestInvalidRefreshToken(t *testing.T) {
-	testRouter := SetupRouter()
-
-	var tokenForm forms.Token
-
-	//Since we didn't update it in the test before - this will not be valid anymore
-	tokenForm.RefreshToken = refreshToken
-
-	data, _ := json.Marshal(tokenForm)
-
-	req, err := http.NewRequest("POST", "/v1/token/refresh", bytes.NewBufferString(string(data)))
-	req.Header.Set("Content-Type", "application/json")
-
-	if err != nil {
-		fmt.Println(err)
+func TestDeleteOrderedProduct(t *testing.T) {
+	var res struct {
+		Data models.Order `json:"data"`
 	}
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", createTestOrder()), nil, accessToken), &res)
 
-	resp := httptest.NewRecorder()
-	testRouter.ServeHTTP(resp, req)
-
-	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	resp := request("DELETE", fmt.Sprintf("/v1/product/%d", res.Data.Items[0].ProductID), nil, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
 }
 
 /**
-* TestUserSignout
-* Test logout a user
+* TestDeleteProduct
+* Test deleting an product
 *
 * Must return response code 200
  */
-func TestUserLogout(t *testing.T) {
+func TestDeleteProduct(t *testing.T) {
 	testRouter := SetupRouter()
 
-	req, err := http.NewRequest("GET", "/v1/user/logout", nil)
+	url := fmt.Sprintf("/v1/product/%d", productID)
+
+	req, err := http.NewRequest("DELETE", url, nil)
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer: %s", accessToken))
 
 	if err != nil {
@@ -545,17 +346,3 @@ func TestUserLogout(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
-
-/**
-* TestCleanUp
-* Deletes the created user with it's products
-*
-* Must pass
- */
-func TestCleanUp(t *testing.T) {
-	var err error
-	_, err = db.GetDB().Exec("DELETE FROM public.user WHERE email=$1", testEmail)
-	if err != nil {
-		t.Error(err)
-	}
-}
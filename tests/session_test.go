@@ -0,0 +1,161 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSessionEmail = "test-gin-boilerplate-session@test.com"
+
+// loginFrom logs in from the device of the user agent and returns the tokens of the new session
+func loginFrom(email, userAgent string) (string, string) {
+	data, _ := json.Marshal(forms.LoginForm{Email: email, Password: testPassword})
+
+	req, _ := http.NewRequest("POST", "/v1/user/login", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp := httptest.NewRecorder()
+	SetupRouter().ServeHTTP(resp, req)
+
+	_, token := loggedInUser(resp)
+	return token.AccessToken, token.RefreshToken
+}
+
+// sessions lists the sessions of the user of the token
+func sessions(token string) (int, []models.Session) {
+	var res struct {
+		Results []models.Session `json:"results"`
+	}
+	resp := request("GET", "/v1/user/sessions", nil, token)
+	decode(resp, &res)
+
+	return resp.Code, res.Results
+}
+
+/**
+* TestSessions
+* Test listing the sessions of two devices, revoking one and logging out everywhere
+*
+* Must revoke the access and the refresh token of a session together
+ */
+func TestSessions(t *testing.T) {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing session", Email: testSessionEmail, Password: testPassword}, "")
+
+	laptop, laptopRefresh := loginFrom(testSessionEmail, "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:109.0) Gecko/20100101 Firefox/115.0")
+	phone, phoneRefresh := loginFrom(testSessionEmail, "Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Mobile/15E148 Safari/604.1")
+
+	code, results := sessions(laptop)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, results, 2)
+
+	var laptopSession, phoneSession models.Session
+	for _, session := range results {
+		if session.Current {
+			laptopSession = session
+		} else {
+			phoneSession = session
+		}
+	}
+	assert.Equal(t, "Firefox on Windows", laptopSession.Device)
+	assert.Equal(t, "Safari on iOS", phoneSession.Device)
+
+	//Refreshing keeps the session, the previous access token stops working
+	var refreshed models.Token
+	resp := request("POST", "/v1/token/refresh", forms.Token{RefreshToken: phoneRefresh}, "")
+	decode(resp, &refreshed)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, phone)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	_, results = sessions(refreshed.AccessToken)
+	assert.Len(t, results, 2)
+
+	//The laptop revokes the phone
+	resp = request("DELETE", "/v1/user/sessions/"+phoneSession.ID, nil, laptop)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, refreshed.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: refreshed.RefreshToken}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//A request seen after the revocation does not bring the session back
+	db.GetRedis().Del("session:" + phoneSession.ID + ":seen")
+	new(models.SessionModel).Touch(phoneSession.ID, "203.0.113.1")
+
+	exists, _ := db.GetRedis().Exists("session:" + phoneSession.ID).Result()
+	assert.Zero(t, exists)
+
+	//The sessions of the other users are not found
+	resp = request("DELETE", "/v1/user/sessions/"+laptopSession.ID, nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	//Log out everywhere
+	loginFrom(testSessionEmail, "curl/8.0")
+
+	resp = request("DELETE", "/v1/user/sessions", nil, laptop)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, laptop)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: laptopRefresh}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestRefreshTokenReuse
+* Test presenting a refresh token again once it was rotated, as a thief replaying a stolen token would
+*
+* Must return response code 401 and revoke the tokens of the whole session, with the reuse in the audit log
+ */
+func TestRefreshTokenReuse(t *testing.T) {
+	request("POST", "/v1/user/register", forms.RegisterForm{Name: "testing session", Email: testSessionEmail, Password: testPassword}, "")
+
+	token, stolen := loginFrom(testSessionEmail, "curl/8.0")
+
+	var rotated models.Token
+	resp := request("POST", "/v1/token/refresh", forms.Token{RefreshToken: stolen}, "")
+	decode(resp, &rotated)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: stolen}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	//The pair the legitimate user was given is revoked with the family
+	resp = request("GET", "/v1/user/sessions", nil, rotated.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: rotated.RefreshToken}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("GET", "/v1/user/sessions", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	token, _ = loginFrom(testSessionEmail, "curl/8.0")
+
+	var res struct {
+		Results []models.AuditLog `json:"results"`
+	}
+	resp = request("GET", "/v1/user/audit-log", nil, token)
+	decode(resp, &res)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	if assert.NotEmpty(t, res.Results) {
+		assert.Equal(t, models.AuditRefreshTokenReuse, res.Results[0].Event)
+	}
+}
@@ -0,0 +1,322 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/Massad/gin-boilerplate/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var shipmentID int64
+var shipmentOrderID int64
+
+// shipmentForm returns a valid shipment of the order with two parcels
+func shipmentForm(orderID int64) (form forms.CreateShipmentForm) {
+	form.OrderID = orderID
+	form.Carrier = "DHL"
+	form.ServiceLevel = "express"
+	form.TrackingNumber = "JD014600003812345678"
+	form.Destination = forms.ShipmentAddressForm{
+		Name:       "Testing recipient",
+		Line1:      "1 Testing street",
+		City:       "Berlin",
+		PostalCode: "10115",
+		Country:    "DE",
+	}
+	form.Parcels = []forms.ParcelForm{
+		{Weight: 1200, Length: 300, Width: 200, Height: 100},
+		{Weight: 500, Length: 200, Width: 200, Height: 50},
+	}
+	return form
+}
+
+// createShipment creates a shipment of the order and returns the response code with the decoded shipment
+func createShipment(form forms.CreateShipmentForm) (int, models.Shipment) {
+	resp := request("POST", "/v1/shipment", form, accessToken)
+
+	var res struct {
+		Data models.Shipment `json:"data"`
+	}
+	decode(resp, &res)
+
+	return resp.Code, res.Data
+}
+
+// addShipmentEvent records a tracking event and returns the response code with the updated shipment
+func addShipmentEvent(id int64, form forms.ShipmentEventForm) (int, models.Shipment) {
+	resp := request("POST", fmt.Sprintf("/v1/shipment/%d/events", id), form, accessToken)
+
+	var res struct {
+		Data models.Shipment `json:"data"`
+	}
+	decode(resp, &res)
+
+	return resp.Code, res.Data
+}
+
+// orderStatus returns the current status of the order
+func orderStatus(id int64) string {
+	var res struct {
+		Data models.Order `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", id), nil, accessToken), &res)
+
+	return res.Data.Status
+}
+
+/**
+* TestCreateShipmentDraftOrder
+* Test shipping an order that is not placed yet
+*
+* Must return response code 409
+ */
+func TestCreateShipmentDraftOrder(t *testing.T) {
+	code, _ := createShipment(shipmentForm(createTestOrder()))
+	assert.Equal(t, http.StatusConflict, code)
+}
+
+/**
+* TestCreateShipment
+* Test shipment creation with its parcels
+*
+* Must return response code 200
+ */
+func TestCreateShipment(t *testing.T) {
+	shipmentOrderID = createTestOrder("placed")
+
+	code, shipment := createShipment(shipmentForm(shipmentOrderID))
+
+	shipmentID = shipment.ID
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "pending", shipment.Status)
+	assert.Equal(t, shipmentOrderID, shipment.OrderID)
+	assert.Len(t, shipment.Parcels, 2)
+	assert.Equal(t, int64(1200), shipment.Parcels[0].Weight)
+}
+
+/**
+* TestCreateInvalidShipment
+* Test shipment invalid creation with an unknown country and without parcels
+*
+* Must return response code 406
+ */
+func TestCreateInvalidShipment(t *testing.T) {
+	form := shipmentForm(shipmentOrderID)
+	form.Destination.Country = "XX"
+
+	code, _ := createShipment(form)
+	assert.Equal(t, http.StatusNotAcceptable, code)
+
+	form = shipmentForm(shipmentOrderID)
+	form.Parcels = nil
+
+	code, _ = createShipment(form)
+	assert.Equal(t, http.StatusNotAcceptable, code)
+}
+
+/**
+* TestGetShipment
+* Test getting one shipment
+*
+* Must return response code 200
+ */
+func TestGetShipment(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/shipment/%d", shipmentID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetShipments
+* Test listing the shipments
+*
+* Must return response code 200
+ */
+func TestGetShipments(t *testing.T) {
+	resp := request("GET", "/v1/shipments", nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestGetInvalidShipment
+* Test getting invalid shipment
+*
+* Must return response code 404
+ */
+func TestGetInvalidShipment(t *testing.T) {
+	resp := request("GET", "/v1/shipment/invalid", nil, accessToken)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+/**
+* TestGetShipmentNotLoggedin
+* Test getting the shipment with logged out user
+*
+* Must return response code 401
+ */
+func TestGetShipmentNotLoggedin(t *testing.T) {
+	resp := request("GET", fmt.Sprintf("/v1/shipment/%d", shipmentID), nil, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestUpdateShipment
+* Test updating a pending shipment
+*
+* Must return response code 200
+ */
+func TestUpdateShipment(t *testing.T) {
+	form := shipmentForm(shipmentOrderID)
+
+	update := forms.UpdateShipmentForm{
+		Carrier:     "UPS",
+		Destination: form.Destination,
+		Parcels:     form.Parcels[:1],
+	}
+
+	resp := request("PUT", fmt.Sprintf("/v1/shipment/%d", shipmentID), update, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestShipmentEvents
+* Test recording tracking events, including one reported late by the carrier
+*
+* Must return response code 200 and keep the status of the latest event
+ */
+func TestShipmentEvents(t *testing.T) {
+	code, shipment := addShipmentEvent(shipmentID, forms.ShipmentEventForm{Status: "in_transit", Location: "Leipzig hub", OccurredAt: "2030-01-02T10:00:00Z"})
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "in_transit", shipment.Status)
+
+	code, shipment = addShipmentEvent(shipmentID, forms.ShipmentEventForm{Status: "pending", Description: "Label created", OccurredAt: "2030-01-01T10:00:00Z"})
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "in_transit", shipment.Status)
+	assert.Len(t, shipment.Events, 2)
+	assert.Equal(t, "pending", shipment.Events[0].Status)
+
+	code, _ = addShipmentEvent(shipmentID, forms.ShipmentEventForm{Status: "lost"})
+	assert.Equal(t, http.StatusNotAcceptable, code)
+}
+
+/**
+* TestUpdateShippedShipment
+* Test updating and deleting a shipment that left the warehouse
+*
+* Must return response code 409 for the update and 406 for the delete
+ */
+func TestUpdateShippedShipment(t *testing.T) {
+	form := shipmentForm(shipmentOrderID)
+
+	update := forms.UpdateShipmentForm{
+		Carrier:     "UPS",
+		Destination: form.Destination,
+		Parcels:     form.Parcels,
+	}
+
+	resp := request("PUT", fmt.Sprintf("/v1/shipment/%d", shipmentID), update, accessToken)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request("DELETE", fmt.Sprintf("/v1/shipment/%d", shipmentID), nil, accessToken)
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestShipmentFulfilsOrder
+* Test the order is fulfilled once it is paid and shipped, in any order
+*
+* Must move both orders to fulfilled
+ */
+func TestShipmentFulfilsOrder(t *testing.T) {
+	//Shipped before being paid
+	assert.Equal(t, "placed", orderStatus(shipmentOrderID))
+
+	request("POST", fmt.Sprintf("/v1/order/%d/transition", shipmentOrderID), forms.OrderTransitionForm{Status: "paid"}, accessToken)
+	assert.Equal(t, "fulfilled", orderStatus(shipmentOrderID))
+
+	//Paid before being shipped
+	orderID := createTestOrder("placed", "paid")
+
+	_, shipment := createShipment(shipmentForm(orderID))
+	assert.Equal(t, "paid", orderStatus(orderID))
+
+	addShipmentEvent(shipment.ID, forms.ShipmentEventForm{Status: "delivered"})
+	assert.Equal(t, "fulfilled", orderStatus(orderID))
+}
+
+/**
+* TestSplitShipments
+* Test shipping an order in several shipments without shipping more than ordered
+*
+* Must reject the shipments over the ordered quantities and fulfil the order once every line left
+ */
+func TestSplitShipments(t *testing.T) {
+	orderID := createTestOrder("placed", "paid")
+
+	var res struct {
+		Data models.Order `json:"data"`
+	}
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", orderID), nil, accessToken), &res)
+
+	first, second := res.Data.Items[0].ID, res.Data.Items[1].ID
+
+	form := shipmentForm(orderID)
+	form.Items = []forms.ShipmentItemForm{{OrderItemID: first, Quantity: 1}}
+
+	code, firstShipment := createShipment(form)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, firstShipment.Items, 1)
+
+	form.Items = []forms.ShipmentItemForm{{OrderItemID: first, Quantity: 2}}
+
+	code, _ = createShipment(form)
+	assert.Equal(t, http.StatusConflict, code)
+
+	form.Items = []forms.ShipmentItemForm{{OrderItemID: second + 1000000, Quantity: 1}}
+
+	code, _ = createShipment(form)
+	assert.Equal(t, http.StatusNotAcceptable, code)
+
+	form.Items = nil
+
+	code, secondShipment := createShipment(form)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, secondShipment.Items, 2)
+
+	code, _ = createShipment(form)
+	assert.Equal(t, http.StatusConflict, code)
+
+	addShipmentEvent(firstShipment.ID, forms.ShipmentEventForm{Status: "in_transit"})
+
+	decode(request("GET", fmt.Sprintf("/v1/order/%d", orderID), nil, accessToken), &res)
+
+	assert.Equal(t, "paid", res.Data.Status)
+	assert.Equal(t, int64(2), res.Data.Items[0].Allocated)
+	assert.Equal(t, int64(1), res.Data.Items[0].Shipped)
+	assert.Equal(t, int64(0), res.Data.Items[0].Remaining)
+	assert.Equal(t, int64(0), res.Data.Items[1].Shipped)
+
+	addShipmentEvent(secondShipment.ID, forms.ShipmentEventForm{Status: "in_transit"})
+	assert.Equal(t, "fulfilled", orderStatus(orderID))
+}
+
+/**
+* TestDeleteShipment
+* Test deleting a pending shipment
+*
+* Must return response code 200
+ */
+func TestDeleteShipment(t *testing.T) {
+	_, shipment := createShipment(shipmentForm(createTestOrder("placed")))
+
+	resp := request("DELETE", fmt.Sprintf("/v1/shipment/%d", shipment.ID), nil, accessToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
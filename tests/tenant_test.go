@@ -0,0 +1,129 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/db"
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+)
+
+// activeOrganization returns the organization the access token works in
+func activeOrganization(token string) int64 {
+	var res struct {
+		Active int64 `json:"active"`
+	}
+	decode(request("GET", "/v1/organizations", nil, token), &res)
+
+	return res.Active
+}
+
+/**
+* TestTenantIsolation
+* Test the row level security hides the rows of the other organizations even without filtering on them
+*
+* Must find, update and delete no row of the other organization and fail to insert one for it
+ */
+func TestTenantIsolation(t *testing.T) {
+	orgID := activeOrganization(accessToken)
+	assert.NotZero(t, orgID)
+	assert.NotEqual(t, organizationID, orgID)
+
+	err := db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		count, err := tx.SelectInt("SELECT count(*) FROM public.customer WHERE id=$1", organizationCustomerID)
+		assert.Zero(t, count)
+
+		return err
+	})
+	assert.Nil(t, err)
+
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		operation, err := tx.Exec("UPDATE public.customer SET name='Leaked' WHERE id=$1", organizationCustomerID)
+		if err != nil {
+			return err
+		}
+		updated, _ := operation.RowsAffected()
+		assert.Zero(t, updated)
+
+		operation, err = tx.Exec("DELETE FROM public.customer WHERE id=$1", organizationCustomerID)
+		if err != nil {
+			return err
+		}
+		deleted, _ := operation.RowsAffected()
+		assert.Zero(t, deleted)
+
+		count, err := tx.SelectInt("SELECT count(*) FROM public.customer_address WHERE customer_id=$1", organizationCustomerID)
+		assert.Zero(t, count)
+
+		return err
+	})
+	assert.Nil(t, err)
+
+	//Inserting a row for another organization violates the policy
+	err = db.WithTenant(orgID, func(tx gorp.SqlExecutor) error {
+		_, err := tx.Exec("INSERT INTO public.customer(organization_id, name) VALUES($1, 'Leaked')", organizationID)
+		return err
+	})
+	assert.NotNil(t, err)
+
+	//An organization that does not exist sees no row at all
+	err = db.WithTenant(0, func(tx gorp.SqlExecutor) error {
+		count, err := tx.SelectInt("SELECT count(*) FROM public.customer")
+		assert.Zero(t, count)
+
+		return err
+	})
+	assert.Nil(t, err)
+
+	//The customer is left untouched in its own organization
+	resp := request("GET", fmt.Sprintf("/v1/customer/%d", organizationCustomerID), nil, organizationToken)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+// listIDs returns the IDs of the rows listed by the path
+func listIDs(path, token string) (ids []int64) {
+	var list struct {
+		Results []struct {
+			Data []struct {
+				ID int64 `json:"id"`
+			} `json:"data"`
+		} `json:"results"`
+	}
+	decode(request("GET", path, nil, token), &list)
+
+	for _, result := range list.Results {
+		for _, row := range result.Data {
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids
+}
+
+/**
+* TestTenantLists
+* Test the lists show the rows of the active organization, which is not the organization with the ID of the user
+*
+* Must list the order and the product in their organization only
+ */
+func TestTenantLists(t *testing.T) {
+	userID := tokenClaims(organizationToken).UserID()
+	assert.NotEqual(t, userID, activeOrganization(organizationToken))
+
+	orderID := createTestOrder()
+	productID := createTestProduct()
+
+	assert.Contains(t, listIDs("/v1/orders", accessToken), orderID)
+	assert.Contains(t, listIDs("/v1/products", accessToken), productID)
+
+	for _, path := range []string{"/v1/orders", "/v1/products", "/v1/articles", "/v1/invoices", "/v1/shipments", "/v1/warehouses"} {
+		personal := listIDs(path, accessToken)
+		for _, id := range listIDs(path, organizationToken) {
+			assert.NotContains(t, personal, id, path)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+//go:build all
+// +build all
+
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Massad/gin-boilerplate/forms"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRegisterEmail = "test-gin-boilerplate-register@test.com"
+
+/**
+* TestRegister
+* Test user registration
+*
+* Must return response code 200
+ */
+func TestRegister(t *testing.T) {
+	var registerForm forms.RegisterForm
+
+	registerForm.Name = "testing"
+	registerForm.Email = testRegisterEmail
+	registerForm.Password = testPassword
+
+	resp := request("POST", "/v1/user/register", registerForm, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestRegisterInvalidEmail
+* Test user registration with invalid email
+*
+* Must return response code 406
+ */
+func TestRegisterInvalidEmail(t *testing.T) {
+	var registerForm forms.RegisterForm
+
+	registerForm.Name = "testing"
+	registerForm.Email = "invalid@email"
+	registerForm.Password = testPassword
+
+	resp := request("POST", "/v1/user/register", registerForm, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestRegisterExistingEmail
+* Test user registration with an email that is already taken
+*
+* Must return response code 406
+ */
+func TestRegisterExistingEmail(t *testing.T) {
+	var registerForm forms.RegisterForm
+
+	registerForm.Name = "testing"
+	registerForm.Email = testEmail
+	registerForm.Password = testPassword
+
+	resp := request("POST", "/v1/user/register", registerForm, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestLogin
+* Test user login
+*
+* Must return response code 200
+ */
+func TestLogin(t *testing.T) {
+	var loginForm forms.LoginForm
+
+	loginForm.Email = testEmail
+	loginForm.Password = testPassword
+
+	resp := request("POST", "/v1/user/login", loginForm, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestInvalidLogin
+* Test invalid login
+*
+* Must return response code 406
+ */
+func TestInvalidLogin(t *testing.T) {
+	var loginForm forms.LoginForm
+
+	loginForm.Email = "wrong@email.com"
+	loginForm.Password = testPassword
+
+	resp := request("POST", "/v1/user/login", loginForm, "")
+	assert.Equal(t, http.StatusNotAcceptable, resp.Code)
+}
+
+/**
+* TestRefreshToken
+* Test refreshing the token with valid refresh_token
+*
+* Must return response code 200
+ */
+func TestRefreshToken(t *testing.T) {
+	var tokenForm forms.Token
+
+	tokenForm.RefreshToken = refreshToken
+
+	resp := request("POST", "/v1/token/refresh", tokenForm, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+/**
+* TestInvalidRefreshToken
+* Test refreshing the token with invalid refresh_token
+*
+* Must return response code 401
+ */
+func TestInvalidRefreshToken(t *testing.T) {
+	var tokenForm forms.Token
+
+	//Since we didn't update it in the test before - this will not be valid anymore
+	tokenForm.RefreshToken = refreshToken
+
+	resp := request("POST", "/v1/token/refresh", tokenForm, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+/**
+* TestUserLogout
+* Test logout a user with its own session so the shared access_token stays valid
+*
+* Must return response code 200, then 401 for both the access and the refresh token of the session
+ */
+func TestUserLogout(t *testing.T) {
+	token, refresh := login(testEmail, testPassword)
+
+	resp := request("GET", "/v1/user/logout", nil, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("GET", "/v1/article/1", nil, token)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	resp = request("POST", "/v1/token/refresh", forms.Token{RefreshToken: refresh}, "")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}